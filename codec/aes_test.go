@@ -0,0 +1,150 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+func TestNewAESGCM(t *testing.T) {
+	tests := []struct {
+		name        string
+		activeKeyID string
+		keys        map[string][]byte
+		wantErr     bool
+	}{
+		{
+			name:        "valid keys",
+			activeKeyID: "a",
+			keys:        map[string][]byte{"a": genKey(32), "b": genKey(16)},
+		},
+		{
+			name:        "active key not found",
+			activeKeyID: "c",
+			keys:        map[string][]byte{"a": genKey(32)},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid key size",
+			activeKeyID: "a",
+			keys:        map[string][]byte{"a": genKey(10)},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAESGCM(tt.activeKeyID, tt.keys)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAESGCM_EncodeDecode(t *testing.T) {
+	c, err := NewAESGCM("a", map[string][]byte{"a": genKey(32)})
+	require.NoError(t, err)
+
+	want := genPayload()
+
+	encoded, err := c.Encode([]*commonpb.Payload{want})
+	require.NoError(t, err)
+	require.Len(t, encoded, 1)
+	assert.Equal(t, MetadataEncodingEncrypted, string(encoded[0].Metadata[converter.MetadataEncoding]))
+	assert.Equal(t, "a", string(encoded[0].Metadata[MetadataEncryptionKeyID]))
+	assert.NotContains(t, string(encoded[0].Data), string(want.Data))
+
+	decoded, err := c.Decode(encoded)
+	require.NoError(t, err)
+	require.Len(t, decoded, 1)
+	assert.Equal(t, want.Metadata, decoded[0].Metadata)
+	assert.Equal(t, want.Data, decoded[0].Data)
+}
+
+func TestAESGCM_Decode(t *testing.T) {
+	oldKey := genKey(32)
+	old, err := NewAESGCM("old", map[string][]byte{"old": oldKey})
+	require.NoError(t, err)
+
+	rotated, err := NewAESGCM("new", map[string][]byte{"old": oldKey, "new": genKey(32)})
+	require.NoError(t, err)
+
+	other, err := NewAESGCM("other", map[string][]byte{"other": genKey(32)})
+	require.NoError(t, err)
+
+	want := genPayload()
+
+	encodedOld, err := old.Encode([]*commonpb.Payload{want})
+	require.NoError(t, err)
+
+	tampered, err := old.Encode([]*commonpb.Payload{want})
+	require.NoError(t, err)
+	tampered[0].Data[len(tampered[0].Data)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		codec   *AESGCM
+		payload *commonpb.Payload
+		wantErr bool
+	}{
+		{
+			name:    "decode with rotated key",
+			codec:   rotated,
+			payload: encodedOld[0],
+		},
+		{
+			name:    "unencrypted payload is unchanged",
+			codec:   rotated,
+			payload: want,
+		},
+		{
+			name:    "key not found",
+			codec:   other,
+			payload: encodedOld[0],
+			wantErr: true,
+		},
+		{
+			name:    "tampered payload",
+			codec:   old,
+			payload: tampered[0],
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.codec.Decode([]*commonpb.Payload{tt.payload})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, want.Metadata, got[0].Metadata)
+			assert.Equal(t, want.Data, got[0].Data)
+		})
+	}
+}
+
+func genKey(size int) []byte {
+	key := make([]byte, size)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+func genPayload() *commonpb.Payload {
+	return &commonpb.Payload{
+		Metadata: map[string][]byte{
+			converter.MetadataEncoding: []byte(converter.MetadataEncodingJSON),
+		},
+		Data: []byte(`{"password":"hunter2"}`),
+	}
+}
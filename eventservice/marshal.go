@@ -14,6 +14,15 @@ var typeProto = map[Type]eventsv1.Event_Type{
 	TypeCaseExecutionStarted:   eventsv1.Event_TYPE_CASE_EXECUTION_STARTED,
 	TypeCaseExecutionFinished:  eventsv1.Event_TYPE_CASE_EXECUTION_FINISHED,
 	TypeLogPublished:           eventsv1.Event_TYPE_LOG_PUBLISHED,
+	// The events API has no dedicated cancelled types yet: cancellations are
+	// sent as finished events carrying the cancelled execution.
+	TypeTestExecutionCancelled: eventsv1.Event_TYPE_TEST_EXECUTION_FINISHED,
+	TypeCaseExecutionCancelled: eventsv1.Event_TYPE_CASE_EXECUTION_FINISHED,
+	// Retries schedule a new attempt of the same test execution.
+	TypeTestExecutionRetried: eventsv1.Event_TYPE_TEST_EXECUTION_SCHEDULED,
+	// The events API has no dedicated progress type yet: progress is sent as
+	// started events carrying the running case execution.
+	TypeCaseExecutionProgressed: eventsv1.Event_TYPE_CASE_EXECUTION_STARTED,
 }
 
 func (t Type) Proto() eventsv1.Event_Type {
@@ -24,6 +33,20 @@ func (t Type) Proto() eventsv1.Event_Type {
 	return pb
 }
 
+var typeNames = map[Type]string{
+	TypeTestExecutionCancelled:  "TYPE_TEST_EXECUTION_CANCELLED",
+	TypeCaseExecutionCancelled:  "TYPE_CASE_EXECUTION_CANCELLED",
+	TypeTestExecutionRetried:    "TYPE_TEST_EXECUTION_RETRIED",
+	TypeCaseExecutionProgressed: "TYPE_CASE_EXECUTION_PROGRESSED",
+}
+
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return t.Proto().String()
+}
+
 var dataTypeProto = map[DataType]eventsv1.Event_Data_Type{
 	DataTypeUnspecified:   eventsv1.Event_Data_TYPE_UNSPECIFIED,
 	DataTypeNone:          eventsv1.Event_Data_TYPE_NONE,
@@ -55,7 +55,7 @@ func (s *streamer) streamTestExecutionEvents(ctx context.Context, id test.TestEx
 		for _, event := range existingEvents {
 			out <- event
 			seenEventIDs.Add(event.ID)
-			if event.Type == TypeTestExecutionFinished {
+			if event.Type.IsTestExecutionTerminal() {
 				return
 			}
 		}
@@ -71,7 +71,7 @@ func (s *streamer) streamTestExecutionEvents(ctx context.Context, id test.TestEx
 				if !seenEventIDs.Contains(event.ID) {
 					out <- event
 				}
-				if event.Type == TypeTestExecutionFinished {
+				if event.Type.IsTestExecutionTerminal() {
 					return
 				}
 			}
@@ -85,9 +85,12 @@ func (s *streamer) getExistingEvents(
 	ctx context.Context,
 	testExec *test.TestExecution,
 ) ([]*ExecutionEvent, error) {
-	events := []*ExecutionEvent{NewTestExecutionEvent(TypeTestExecutionScheduled, testExec)}
+	events := []*ExecutionEvent{NewTestExecutionEvent(testExecScheduledEventType(testExec.Attempt), testExec)}
 
 	if testExec.StartTime == nil {
+		if testExec.Status.IsFinal() {
+			events = append(events, NewTestExecutionEvent(testExecFinalEventType(testExec.Status), testExec))
+		}
 		return events, nil
 	}
 	events = append(events, NewTestExecutionEvent(TypeTestExecutionStarted, testExec))
@@ -123,10 +126,10 @@ func (s *streamer) getExistingEvents(
 			testLogEvents = testLogEvents[1:]
 		}
 
-		events = append(events, NewCaseExecutionEvent(TypeCaseExecutionScheduled, caseExec))
+		events = append(events, NewCaseExecutionEvent(TypeCaseExecutionScheduled, caseExec, testExec.Attempt))
 
 		if caseExec.StartTime != nil {
-			events = append(events, NewCaseExecutionEvent(TypeCaseExecutionStarted, caseExec))
+			events = append(events, NewCaseExecutionEvent(TypeCaseExecutionStarted, caseExec, testExec.Attempt))
 		}
 
 		caseLogEvents, ok := caseLogEventsMap[caseExec.ID]
@@ -136,8 +139,12 @@ func (s *streamer) getExistingEvents(
 			}
 		}
 
-		if caseExec.FinishTime != nil {
-			events = append(events, NewCaseExecutionEvent(TypeCaseExecutionFinished, caseExec))
+		if caseExec.HeartbeatTime != nil {
+			events = append(events, NewCaseExecutionEvent(TypeCaseExecutionProgressed, caseExec, testExec.Attempt))
+		}
+
+		if caseExec.Status.IsFinal() {
+			events = append(events, NewCaseExecutionEvent(caseExecFinalEventType(caseExec.Status), caseExec, testExec.Attempt))
 		}
 	}
 
@@ -148,8 +155,8 @@ func (s *streamer) getExistingEvents(
 		}
 	}
 
-	if testExec.FinishTime != nil {
-		events = append(events, NewTestExecutionEvent(TypeTestExecutionFinished, testExec))
+	if testExec.Status.IsFinal() {
+		events = append(events, NewTestExecutionEvent(testExecFinalEventType(testExec.Status), testExec))
 	}
 
 	return events, nil
@@ -186,3 +193,24 @@ func (s *streamer) getLogEvents(ctx context.Context, testExecID test.TestExecuti
 
 	return testLogEvents, caseLogEvents, nil
 }
+
+func testExecScheduledEventType(attempt int32) Type {
+	if attempt > 1 {
+		return TypeTestExecutionRetried
+	}
+	return TypeTestExecutionScheduled
+}
+
+func testExecFinalEventType(status test.ExecutionStatus) Type {
+	if status == test.ExecutionStatusCancelled {
+		return TypeTestExecutionCancelled
+	}
+	return TypeTestExecutionFinished
+}
+
+func caseExecFinalEventType(status test.ExecutionStatus) Type {
+	if status == test.ExecutionStatusCancelled {
+		return TypeCaseExecutionCancelled
+	}
+	return TypeCaseExecutionFinished
+}
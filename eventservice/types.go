@@ -2,6 +2,7 @@ package eventservice
 
 import (
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,8 +21,18 @@ const (
 	TypeCaseExecutionStarted
 	TypeCaseExecutionFinished
 	TypeLogPublished
+	TypeTestExecutionCancelled
+	TypeCaseExecutionCancelled
+	TypeTestExecutionRetried
+	TypeCaseExecutionProgressed
 )
 
+// IsTestExecutionTerminal reports whether the event type ends the event stream
+// of a test execution.
+func (t Type) IsTestExecutionTerminal() bool {
+	return t == TypeTestExecutionFinished || t == TypeTestExecutionCancelled
+}
+
 type DataType uint
 
 const (
@@ -81,9 +92,11 @@ func NewTestExecutionEvent(eventType Type, testExec *test.TestExecution) *Execut
 	}
 }
 
-func NewCaseExecutionEvent(eventType Type, caseExec *test.CaseExecution) *ExecutionEvent {
+// NewCaseExecutionEvent creates an event of a case execution scheduled by the
+// given attempt of its test execution.
+func NewCaseExecutionEvent(eventType Type, caseExec *test.CaseExecution, testExecAttempt int32) *ExecutionEvent {
 	return &ExecutionEvent{
-		ID:         getCaseExecEventID(caseExec, eventType),
+		ID:         getCaseExecEventID(caseExec, testExecAttempt, eventType),
 		TestExecID: caseExec.TestExecutionID,
 		Type:       eventType,
 		Data: Data{
@@ -110,12 +123,24 @@ func NewLogEvent(eventType Type, log *test.Log) *ExecutionEvent {
 var uuidNameSpaceEvent = uuid.MustParse("4a4da572-d093-4fe4-a60b-d1ddae369894")
 
 func getTestExecEventID(testExec *test.TestExecution, eventType Type) uuid.UUID {
-	raw := testExec.ID.String() + "." + eventType.Proto().String()
+	raw := testExec.ID.String() + "." + eventType.String()
+	if testExec.Attempt > 1 {
+		// Retried attempts repeat the event types of earlier attempts
+		raw += "." + strconv.Itoa(int(testExec.Attempt))
+	}
 	return uuid.NewSHA1(uuidNameSpaceEvent, []byte(raw))
 }
 
-func getCaseExecEventID(caseExec *test.CaseExecution, eventType Type) uuid.UUID {
-	raw := caseExec.TestExecutionID.String() + "." + caseExec.ID.String() + "." + eventType.Proto().String()
+func getCaseExecEventID(caseExec *test.CaseExecution, testExecAttempt int32, eventType Type) uuid.UUID {
+	raw := caseExec.TestExecutionID.String() + "." + caseExec.ID.String() + "." + eventType.String()
+	if testExecAttempt > 1 {
+		// Retried attempts reschedule the case executions of earlier attempts
+		raw += "." + strconv.Itoa(int(testExecAttempt))
+	}
+	if eventType == TypeCaseExecutionProgressed && caseExec.HeartbeatTime != nil {
+		// Each heartbeat of a case execution reports its progress again
+		raw += "." + strconv.FormatInt(caseExec.HeartbeatTime.UnixMicro(), 10)
+	}
 	return uuid.NewSHA1(uuidNameSpaceEvent, []byte(raw))
 }
 
@@ -1,6 +1,7 @@
 package inmem
 
 import (
+	"cmp"
 	"context"
 	"slices"
 
@@ -29,15 +30,134 @@ func (c *CaseExecutionReader) GetCaseExecution(_ context.Context, testExecID tes
 	if !ok {
 		return nil, test.ErrorCaseExecutionNotFound
 	}
-	return ptr.Copy(ce), nil
+	return decodeCaseExec(c.db.codec, ce), nil
 }
 
 func (c *CaseExecutionReader) ListCaseExecutions(_ context.Context, testExecID test.TestExecutionID) (test.CaseExecutionList, error) {
 	c.db.mu.RLock()
 	defer c.db.mu.RUnlock()
 
+	execs := listCaseExecsUnsafe(c.db, testExecID)
+	for i, ce := range execs {
+		execs[i] = decodeCaseExec(c.db.codec, ce)
+	}
+	return execs, nil
+}
+
+func (c *CaseExecutionReader) ListAttemptCaseExecutions(_ context.Context, testExecID test.TestExecutionID, attempt int32) (test.CaseExecutionList, error) {
+	c.db.mu.RLock()
+	defer c.db.mu.RUnlock()
+
+	a, ok := getAttemptUnsafe(c.db, testExecID, attempt)
+	if !ok {
+		return nil, test.ErrorAttemptNotFound
+	}
+
+	execs := make(test.CaseExecutionList, len(a.caseExecs))
+	for i, ce := range a.caseExecs {
+		execs[i] = decodeCaseExec(c.db.codec, ce)
+	}
+	return execs, nil
+}
+
+func (c *CaseExecutionReader) ListCaseExecutionAttempts(_ context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID) (test.CaseExecutionAttemptList, error) {
+	c.db.mu.RLock()
+	defer c.db.mu.RUnlock()
+
+	attempts := c.db.caseExecAttempts[getCaseExecKey(testExecID, caseExecID)]
+	copied := make(test.CaseExecutionAttemptList, len(attempts))
+	for i, a := range attempts {
+		copied[i] = decodeCaseExecAttempt(c.db.codec, a)
+	}
+	return copied, nil
+}
+
+func (c *CaseExecutionReader) GetCaseExecutionPayloads(_ context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID) (*test.CaseExecutionPayloads, error) {
+	c.db.mu.RLock()
+	defer c.db.mu.RUnlock()
+
+	key := getCaseExecKey(testExecID, caseExecID)
+	if _, ok := c.db.caseExecs[key]; !ok {
+		return nil, test.ErrorCaseExecutionNotFound
+	}
+	return decodeCaseExecPayloads(c.db.codec, c.db.caseExecPayloads[key])
+}
+
+func (c *CaseExecutionReader) ListAttemptCaseExecutionAttempts(_ context.Context, testExecID test.TestExecutionID, attempt int32, caseExecID test.CaseExecutionID) (test.CaseExecutionAttemptList, error) {
+	c.db.mu.RLock()
+	defer c.db.mu.RUnlock()
+
+	a, ok := getAttemptUnsafe(c.db, testExecID, attempt)
+	if !ok {
+		return nil, test.ErrorAttemptNotFound
+	}
+
+	attempts := a.caseExecAttempts[caseExecID]
+	copied := make(test.CaseExecutionAttemptList, len(attempts))
+	for i, ca := range attempts {
+		copied[i] = decodeCaseExecAttempt(c.db.codec, ca)
+	}
+	return copied, nil
+}
+
+func (c *CaseExecutionReader) GetAttemptCaseExecutionPayloads(_ context.Context, testExecID test.TestExecutionID, attempt int32, caseExecID test.CaseExecutionID) (*test.CaseExecutionPayloads, error) {
+	c.db.mu.RLock()
+	defer c.db.mu.RUnlock()
+
+	a, ok := getAttemptUnsafe(c.db, testExecID, attempt)
+	if !ok {
+		return nil, test.ErrorAttemptNotFound
+	}
+	return decodeCaseExecPayloads(c.db.codec, a.caseExecPayloads[caseExecID])
+}
+
+// decodeCaseExecPayloads copies stored case execution payloads with their
+// payloads decoded. Case executions without stored payloads have none.
+func decodeCaseExecPayloads(codec test.PayloadCodec, payloads *test.CaseExecutionPayloads) (*test.CaseExecutionPayloads, error) {
+	decoded := &test.CaseExecutionPayloads{}
+	if payloads == nil {
+		return decoded, nil
+	}
+
+	decoded.Input = make([]*test.Payload, len(payloads.Input))
+	for i, p := range payloads.Input {
+		var err error
+		if decoded.Input[i], err = test.DecodePayload(codec, ptr.Copy(p)); err != nil {
+			return nil, err
+		}
+	}
+	if payloads.Result != nil {
+		var err error
+		if decoded.Result, err = test.DecodePayload(codec, ptr.Copy(payloads.Result)); err != nil {
+			return nil, err
+		}
+	}
+	return decoded, nil
+}
+
+// decodeCaseExec copies a case execution with its failure and progress
+// decoded. Like failures, progress that cannot be decoded is treated as
+// missing.
+func decodeCaseExec(codec test.PayloadCodec, ce *test.CaseExecution) *test.CaseExecution {
+	decoded := ptr.Copy(ce)
+	decoded.Failure = decodeFailure(codec, ce.Failure)
+	progress, err := test.DecodePayloads(codec, ce.Progress)
+	if err != nil {
+		progress = nil
+	}
+	decoded.Progress = progress
+	return decoded
+}
+
+func decodeCaseExecAttempt(codec test.PayloadCodec, a *test.CaseExecutionAttempt) *test.CaseExecutionAttempt {
+	decoded := ptr.Copy(a)
+	decoded.Failure = decodeFailure(codec, a.Failure)
+	return decoded
+}
+
+func listCaseExecsUnsafe(db *DB, testExecID test.TestExecutionID) test.CaseExecutionList {
 	var execs test.CaseExecutionList
-	for _, ce := range c.db.caseExecs {
+	for _, ce := range db.caseExecs {
 		if ce.TestExecutionID == testExecID {
 			execs = append(execs, ptr.Copy(ce))
 		}
@@ -48,8 +168,7 @@ func (c *CaseExecutionReader) ListCaseExecutions(_ context.Context, testExecID t
 		}
 		return 1
 	})
-
-	return execs, nil
+	return execs
 }
 
 type CaseExecutionWriter struct {
@@ -73,10 +192,11 @@ func (c *CaseExecutionWriter) CreateScheduledCaseExecution(_ context.Context, sc
 		TestExecutionID: scheduled.TestExecID,
 		CaseName:        scheduled.CaseName,
 		ScheduleTime:    scheduled.ScheduleTime,
+		Status:          test.ExecutionStatusScheduled,
 	}
 	c.db.caseExecs[getCaseExecKey(ce.TestExecutionID, ce.ID)] = ce
-	c.db.events.Publish(eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionScheduled, ce))
-	return ptr.Copy(ce), nil
+	c.db.events.Publish(eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionScheduled, decodeCaseExec(c.db.codec, ce), testExecAttemptUnsafe(c.db, ce.TestExecutionID)))
+	return decodeCaseExec(c.db.codec, ce), nil
 }
 
 func (c *CaseExecutionWriter) UpdateStartedCaseExecution(_ context.Context, started *test.StartedCaseExecution) (*test.CaseExecution, error) {
@@ -88,10 +208,20 @@ func (c *CaseExecutionWriter) UpdateStartedCaseExecution(_ context.Context, star
 	if !ok {
 		return nil, test.ErrorCaseExecutionNotFound
 	}
+	// Stopped case executions are not restarted by late acks
+	if ce.Status.IsStopped() {
+		return decodeCaseExec(c.db.codec, ce), nil
+	}
 	ce.StartTime = &started.StartTime
+	ce.FinishTime = nil
+	ce.Error = nil
+	ce.Failure = nil
+	ce.HeartbeatTime = nil
+	ce.Progress = nil
+	ce.Status = test.ExecutionStatusRunning
 	c.db.caseExecs[key] = ce
-	c.db.events.Publish(eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionStarted, ce))
-	return ptr.Copy(ce), nil
+	c.db.events.Publish(eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionStarted, decodeCaseExec(c.db.codec, ce), testExecAttemptUnsafe(c.db, ce.TestExecutionID)))
+	return decodeCaseExec(c.db.codec, ce), nil
 }
 
 func (c *CaseExecutionWriter) UpdateFinishedCaseExecution(_ context.Context, finished *test.FinishedCaseExecution) (*test.CaseExecution, error) {
@@ -103,20 +233,175 @@ func (c *CaseExecutionWriter) UpdateFinishedCaseExecution(_ context.Context, fin
 	if !ok {
 		return nil, test.ErrorCaseExecutionNotFound
 	}
+	// Stopped case executions keep their final status
+	if ce.Status.IsStopped() {
+		return decodeCaseExec(c.db.codec, ce), nil
+	}
+	failure, err := test.EncodeFailure(c.db.codec, finished.Failure)
+	if err != nil {
+		return nil, err
+	}
 	ce.FinishTime = &finished.FinishTime
 	ce.Error = finished.Error
+	ce.Failure = failure
+	ce.Status = finished.Status()
+	c.db.caseExecs[key] = ce
+	c.db.events.Publish(eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionFinished, decodeCaseExec(c.db.codec, ce), testExecAttemptUnsafe(c.db, ce.TestExecutionID)))
+	return decodeCaseExec(c.db.codec, ce), nil
+}
+
+func (c *CaseExecutionWriter) UpdateCaseExecutionHeartbeat(_ context.Context, heartbeat *test.CaseExecutionHeartbeat) (*test.CaseExecution, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	key := getCaseExecKey(heartbeat.TestExecutionID, heartbeat.ID)
+	ce, ok := c.db.caseExecs[key]
+	if !ok {
+		return nil, test.ErrorCaseExecutionNotFound
+	}
+	// Stopped case executions keep their final heartbeat and progress
+	if ce.Status.IsStopped() {
+		return decodeCaseExec(c.db.codec, ce), nil
+	}
+	if len(heartbeat.Progress) > 0 {
+		progress, err := test.EncodePayloads(c.db.codec, heartbeat.Progress)
+		if err != nil {
+			return nil, err
+		}
+		ce.Progress = progress
+	}
+	ce.HeartbeatTime = &heartbeat.HeartbeatTime
 	c.db.caseExecs[key] = ce
-	c.db.events.Publish(eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionFinished, ce))
-	return ptr.Copy(ce), nil
+	c.db.events.Publish(eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionProgressed, decodeCaseExec(c.db.codec, ce), testExecAttemptUnsafe(c.db, ce.TestExecutionID)))
+	return decodeCaseExec(c.db.codec, ce), nil
 }
 
 func (c *CaseExecutionWriter) DeleteCaseExecution(_ context.Context, testExecID test.TestExecutionID, id test.CaseExecutionID) error {
 	c.db.mu.Lock()
 	defer c.db.mu.Unlock()
 	delete(c.db.caseExecs, getCaseExecKey(testExecID, id))
+	delete(c.db.caseExecPayloads, getCaseExecKey(testExecID, id))
+	delete(c.db.caseExecAttempts, getCaseExecKey(testExecID, id))
+	return nil
+}
+
+func (c *CaseExecutionWriter) UpdateCaseExecutionInput(_ context.Context, testExecID test.TestExecutionID, id test.CaseExecutionID, input []*test.Payload) error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	key := getCaseExecKey(testExecID, id)
+	if _, ok := c.db.caseExecs[key]; !ok {
+		return test.ErrorCaseExecutionNotFound
+	}
+
+	encoded := make([]*test.Payload, len(input))
+	for i, p := range input {
+		var err error
+		if encoded[i], err = test.EncodePayload(c.db.codec, ptr.Copy(p)); err != nil {
+			return err
+		}
+	}
+
+	payloads := getCaseExecPayloadsUnsafe(c.db, key)
+	payloads.Input = encoded
+	return nil
+}
+
+func (c *CaseExecutionWriter) UpdateCaseExecutionResult(_ context.Context, testExecID test.TestExecutionID, id test.CaseExecutionID, result *test.Payload) error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	key := getCaseExecKey(testExecID, id)
+	if _, ok := c.db.caseExecs[key]; !ok {
+		return test.ErrorCaseExecutionNotFound
+	}
+
+	encoded, err := test.EncodePayload(c.db.codec, ptr.Copy(result))
+	if err != nil {
+		return err
+	}
+
+	payloads := getCaseExecPayloadsUnsafe(c.db, key)
+	payloads.Result = encoded
 	return nil
 }
 
+func (c *CaseExecutionWriter) CreateStartedCaseExecutionAttempt(_ context.Context, started *test.StartedCaseExecutionAttempt) (*test.CaseExecutionAttempt, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	key := getCaseExecKey(started.TestExecutionID, started.CaseExecutionID)
+	if _, ok := c.db.caseExecs[key]; !ok {
+		return nil, test.ErrorCaseExecutionNotFound
+	}
+
+	// Previous attempts that never reported finishing timed out
+	for _, a := range c.db.caseExecAttempts[key] {
+		if a.Attempt < started.Attempt && a.Status == test.ExecutionStatusRunning {
+			a.FinishTime = &started.StartTime
+			a.Status = test.ExecutionStatusTimedOut
+		}
+	}
+
+	a := getCaseExecAttemptUnsafe(c.db, key, started.Attempt)
+	a.StartTime = &started.StartTime
+	a.FinishTime = nil
+	a.Error = nil
+	a.Failure = nil
+	a.Status = test.ExecutionStatusRunning
+	return decodeCaseExecAttempt(c.db.codec, a), nil
+}
+
+func (c *CaseExecutionWriter) UpdateFinishedCaseExecutionAttempt(_ context.Context, finished *test.FinishedCaseExecutionAttempt) (*test.CaseExecutionAttempt, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	key := getCaseExecKey(finished.TestExecutionID, finished.CaseExecutionID)
+	if _, ok := c.db.caseExecs[key]; !ok {
+		return nil, test.ErrorCaseExecutionNotFound
+	}
+
+	failure, err := test.EncodeFailure(c.db.codec, finished.Failure)
+	if err != nil {
+		return nil, err
+	}
+
+	a := getCaseExecAttemptUnsafe(c.db, key, finished.Attempt)
+	a.FinishTime = &finished.FinishTime
+	a.Error = finished.Error
+	a.Failure = failure
+	a.Status = finished.Status()
+	return decodeCaseExecAttempt(c.db.codec, a), nil
+}
+
+// getCaseExecAttemptUnsafe gets an attempt of a case execution, creating it in
+// attempt order if it does not exist.
+func getCaseExecAttemptUnsafe(db *DB, key caseExecKey, attempt int32) *test.CaseExecutionAttempt {
+	attempts := db.caseExecAttempts[key]
+	i, found := slices.BinarySearchFunc(attempts, attempt, func(a *test.CaseExecutionAttempt, attempt int32) int {
+		return cmp.Compare(a.Attempt, attempt)
+	})
+	if found {
+		return attempts[i]
+	}
+	a := &test.CaseExecutionAttempt{
+		TestExecutionID: key.testExecID,
+		CaseExecutionID: key.caseExecID,
+		Attempt:         attempt,
+	}
+	db.caseExecAttempts[key] = slices.Insert(attempts, i, a)
+	return a
+}
+
+func getCaseExecPayloadsUnsafe(db *DB, key caseExecKey) *test.CaseExecutionPayloads {
+	payloads, ok := db.caseExecPayloads[key]
+	if !ok {
+		payloads = &test.CaseExecutionPayloads{}
+		db.caseExecPayloads[key] = payloads
+	}
+	return payloads
+}
+
 type caseExecKey struct {
 	testExecID test.TestExecutionID
 	caseExecID test.CaseExecutionID
@@ -128,3 +413,12 @@ func getCaseExecKey(testExecID test.TestExecutionID, caseExecID test.CaseExecuti
 		caseExecID: caseExecID,
 	}
 }
+
+// testExecAttemptUnsafe gets the current attempt of a test execution, which is
+// the attempt its case executions are scheduled by.
+func testExecAttemptUnsafe(db *DB, testExecID test.TestExecutionID) int32 {
+	if te, ok := db.testExecs[testExecID]; ok {
+		return te.Attempt
+	}
+	return 0
+}
@@ -3,11 +3,14 @@ package inmem
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
 
+	"github.com/annexsh/annex/codec"
 	"github.com/annexsh/annex/internal/fake"
 	"github.com/annexsh/annex/internal/ptr"
 	"github.com/annexsh/annex/test"
@@ -91,6 +94,7 @@ func TestCaseExecutionWriter_CreateScheduledCaseExecution(t *testing.T) {
 			assert.Nil(t, got.StartTime)
 			assert.Nil(t, got.FinishTime)
 			assert.Nil(t, got.Error)
+			assert.Equal(t, test.ExecutionStatusScheduled, got.Status)
 		})
 	}
 }
@@ -99,12 +103,18 @@ func TestCaseExecutionWriter_UpdateStartedCaseExecution(t *testing.T) {
 	tests := []struct {
 		name         string
 		existingCase bool
+		stopped      bool
 		wantErr      error
 	}{
 		{
 			name:         "update success",
 			existingCase: true,
 		},
+		{
+			name:         "stopped case execution unchanged",
+			existingCase: true,
+			stopped:      true,
+		},
 		{
 			name:         "case execution not found error",
 			existingCase: false,
@@ -122,6 +132,11 @@ func TestCaseExecutionWriter_UpdateStartedCaseExecution(t *testing.T) {
 			existing.StartTime = nil
 			existing.FinishTime = nil
 			existing.Error = nil
+			existing.Status = test.ExecutionStatusScheduled
+			if tt.stopped {
+				existing.FinishTime = ptr.Get(time.Now().UTC())
+				existing.Status = test.ExecutionStatusCancelled
+			}
 			dbKey := getCaseExecKey(existing.TestExecutionID, existing.ID)
 
 			if tt.existingCase {
@@ -136,6 +151,10 @@ func TestCaseExecutionWriter_UpdateStartedCaseExecution(t *testing.T) {
 			}
 
 			require.NoError(t, err)
+			if tt.stopped {
+				assert.Equal(t, existing, got)
+				return
+			}
 			assert.Equal(t, started.ID, got.ID)
 			assert.Equal(t, existing.TestExecutionID, got.TestExecutionID)
 			assert.Equal(t, existing.CaseName, got.CaseName)
@@ -143,6 +162,7 @@ func TestCaseExecutionWriter_UpdateStartedCaseExecution(t *testing.T) {
 			assert.Equal(t, started.StartTime, *got.StartTime)
 			assert.Nil(t, got.FinishTime)
 			assert.Nil(t, got.Error)
+			assert.Equal(t, test.ExecutionStatusRunning, got.Status)
 		})
 	}
 }
@@ -151,12 +171,18 @@ func TestCaseExecutionWriter_UpdateFinishedCaseExecution(t *testing.T) {
 	tests := []struct {
 		name         string
 		existingCase bool
+		stopped      bool
 		wantErr      error
 	}{
 		{
 			name:         "update success",
 			existingCase: true,
 		},
+		{
+			name:         "stopped case execution unchanged",
+			existingCase: true,
+			stopped:      true,
+		},
 		{
 			name:         "case execution not found error",
 			existingCase: false,
@@ -173,6 +199,11 @@ func TestCaseExecutionWriter_UpdateFinishedCaseExecution(t *testing.T) {
 			existing := fake.GenCaseExec(test.NewTestExecutionID())
 			existing.FinishTime = nil
 			existing.Error = nil
+			existing.Status = test.ExecutionStatusRunning
+			if tt.stopped {
+				existing.FinishTime = ptr.Get(time.Now().UTC())
+				existing.Status = test.ExecutionStatusCancelled
+			}
 			dbKey := getCaseExecKey(existing.TestExecutionID, existing.ID)
 
 			if tt.existingCase {
@@ -180,6 +211,11 @@ func TestCaseExecutionWriter_UpdateFinishedCaseExecution(t *testing.T) {
 			}
 
 			finished := fake.GenFinishedCaseExec(existing.TestExecutionID, existing.ID, ptr.Get("bang"))
+			finished.Failure = &test.Failure{
+				Kind:    test.FailureKindApplication,
+				Message: "bang",
+				Cause:   &test.Failure{Message: "boom"},
+			}
 			got, err := w.UpdateFinishedCaseExecution(ctx, finished)
 			if tt.wantErr != nil {
 				require.ErrorIs(t, err, tt.wantErr)
@@ -187,6 +223,10 @@ func TestCaseExecutionWriter_UpdateFinishedCaseExecution(t *testing.T) {
 			}
 
 			require.NoError(t, err)
+			if tt.stopped {
+				assert.Equal(t, existing, got)
+				return
+			}
 			assert.Equal(t, finished.ID, got.ID)
 			assert.Equal(t, existing.TestExecutionID, got.TestExecutionID)
 			assert.Equal(t, existing.CaseName, got.CaseName)
@@ -194,10 +234,75 @@ func TestCaseExecutionWriter_UpdateFinishedCaseExecution(t *testing.T) {
 			assert.Equal(t, existing.StartTime, got.StartTime)
 			assert.Equal(t, finished.FinishTime, *got.FinishTime)
 			assert.Equal(t, finished.Error, got.Error)
+			assert.Equal(t, finished.Failure, got.Failure)
+			assert.Equal(t, test.ExecutionStatusFailed, got.Status)
 		})
 	}
 }
 
+func TestCaseExecutionWriter_UpdateCaseExecutionHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewCaseExecutionWriter(db)
+
+	ce := fake.GenCaseExec(test.NewTestExecutionID())
+	ce.FinishTime = nil
+	ce.Status = test.ExecutionStatusRunning
+	db.caseExecs[getCaseExecKey(ce.TestExecutionID, ce.ID)] = ce
+
+	_, err := w.UpdateCaseExecutionHeartbeat(ctx, &test.CaseExecutionHeartbeat{
+		ID:              fake.GenCaseID(),
+		TestExecutionID: ce.TestExecutionID,
+		HeartbeatTime:   time.Now().UTC(),
+	})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	progress := []*test.Payload{fake.GenInput()}
+	heartbeat := &test.CaseExecutionHeartbeat{
+		ID:              ce.ID,
+		TestExecutionID: ce.TestExecutionID,
+		HeartbeatTime:   time.Now().UTC(),
+		Progress:        progress,
+	}
+	got, err := w.UpdateCaseExecutionHeartbeat(ctx, heartbeat)
+	require.NoError(t, err)
+	assert.Equal(t, heartbeat.HeartbeatTime, *got.HeartbeatTime)
+	assert.Equal(t, progress, got.Progress)
+
+	// Heartbeats without details keep the latest progress
+	heartbeat = &test.CaseExecutionHeartbeat{
+		ID:              ce.ID,
+		TestExecutionID: ce.TestExecutionID,
+		HeartbeatTime:   time.Now().UTC(),
+	}
+	got, err = w.UpdateCaseExecutionHeartbeat(ctx, heartbeat)
+	require.NoError(t, err)
+	assert.Equal(t, heartbeat.HeartbeatTime, *got.HeartbeatTime)
+	assert.Equal(t, progress, got.Progress)
+
+	// Progress is cleared when the case is started again by an activity retry
+	got, err = w.UpdateStartedCaseExecution(ctx, &test.StartedCaseExecution{
+		ID:              ce.ID,
+		TestExecutionID: ce.TestExecutionID,
+		StartTime:       time.Now().UTC(),
+	})
+	require.NoError(t, err)
+	assert.Nil(t, got.HeartbeatTime)
+	assert.Nil(t, got.Progress)
+
+	// Stopped case executions keep their final heartbeat and progress
+	ce.FinishTime = ptr.Get(time.Now().UTC())
+	ce.Status = test.ExecutionStatusCancelled
+	got, err = w.UpdateCaseExecutionHeartbeat(ctx, &test.CaseExecutionHeartbeat{
+		ID:              ce.ID,
+		TestExecutionID: ce.TestExecutionID,
+		HeartbeatTime:   time.Now().UTC(),
+		Progress:        progress,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ce, got)
+}
+
 func TestCaseExecutionWriter_DeleteCaseExecution(t *testing.T) {
 	ctx := context.Background()
 	db := NewDB()
@@ -211,3 +316,190 @@ func TestCaseExecutionWriter_DeleteCaseExecution(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, db.caseExecs)
 }
+
+func TestCaseExecutionWriter_UpdateCaseExecutionHeartbeat_payloadCodec(t *testing.T) {
+	ctx := context.Background()
+	aesgcm, err := codec.NewAESGCM("key", map[string][]byte{"key": make([]byte, 32)})
+	require.NoError(t, err)
+
+	db := NewDB(WithPayloadCodec(aesgcm))
+	w := NewCaseExecutionWriter(db)
+	r := NewCaseExecutionReader(db)
+
+	ce := fake.GenCaseExec(test.NewTestExecutionID())
+	ce.FinishTime = nil
+	ce.Status = test.ExecutionStatusRunning
+	key := getCaseExecKey(ce.TestExecutionID, ce.ID)
+	db.caseExecs[key] = ce
+
+	progress := []*test.Payload{fake.GenInput()}
+	got, err := w.UpdateCaseExecutionHeartbeat(ctx, &test.CaseExecutionHeartbeat{
+		ID:              ce.ID,
+		TestExecutionID: ce.TestExecutionID,
+		HeartbeatTime:   time.Now().UTC(),
+		Progress:        progress,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, progress, got.Progress)
+
+	stored := db.caseExecs[key].Progress
+	assert.Equal(t, codec.MetadataEncodingEncrypted, string(stored[0].Metadata[converter.MetadataEncoding]))
+	assert.NotEqual(t, progress[0].Data, stored[0].Data)
+
+	got, err = r.GetCaseExecution(ctx, ce.TestExecutionID, ce.ID)
+	require.NoError(t, err)
+	assert.Equal(t, progress, got.Progress)
+}
+
+func TestCaseExecutionWriter_UpdateCaseExecutionPayloads(t *testing.T) {
+	ctx := context.Background()
+	aesgcm, err := codec.NewAESGCM("key", map[string][]byte{"key": make([]byte, 32)})
+	require.NoError(t, err)
+
+	db := NewDB(WithPayloadCodec(aesgcm))
+	w := NewCaseExecutionWriter(db)
+	r := NewCaseExecutionReader(db)
+
+	ce := fake.GenCaseExec(test.NewTestExecutionID())
+	key := getCaseExecKey(ce.TestExecutionID, ce.ID)
+	db.caseExecs[key] = ce
+
+	err = w.UpdateCaseExecutionInput(ctx, ce.TestExecutionID, fake.GenCaseID(), []*test.Payload{fake.GenInput()})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	got, err := r.GetCaseExecutionPayloads(ctx, ce.TestExecutionID, ce.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Input)
+	assert.Nil(t, got.Result)
+
+	input := []*test.Payload{fake.GenInput(), fake.GenInput()}
+	require.NoError(t, w.UpdateCaseExecutionInput(ctx, ce.TestExecutionID, ce.ID, input))
+	result := fake.GenInput()
+	require.NoError(t, w.UpdateCaseExecutionResult(ctx, ce.TestExecutionID, ce.ID, result))
+
+	stored := db.caseExecPayloads[key]
+	assert.Equal(t, codec.MetadataEncodingEncrypted, string(stored.Result.Metadata[converter.MetadataEncoding]))
+	assert.NotEqual(t, result.Data, stored.Result.Data)
+
+	got, err = r.GetCaseExecutionPayloads(ctx, ce.TestExecutionID, ce.ID)
+	require.NoError(t, err)
+	assert.Equal(t, input, got.Input)
+	assert.Equal(t, result, got.Result)
+
+	require.NoError(t, w.DeleteCaseExecution(ctx, ce.TestExecutionID, ce.ID))
+	assert.Empty(t, db.caseExecPayloads)
+}
+
+func TestCaseExecutionWriter_UpdateFinishedCaseExecution_payloadCodec(t *testing.T) {
+	ctx := context.Background()
+	aesgcm, err := codec.NewAESGCM("key", map[string][]byte{"key": make([]byte, 32)})
+	require.NoError(t, err)
+
+	db := NewDB(WithPayloadCodec(aesgcm))
+	w := NewCaseExecutionWriter(db)
+	r := NewCaseExecutionReader(db)
+
+	ce := fake.GenCaseExec(test.NewTestExecutionID())
+	key := getCaseExecKey(ce.TestExecutionID, ce.ID)
+	db.caseExecs[key] = ce
+
+	finished := fake.GenFinishedCaseExec(ce.TestExecutionID, ce.ID, ptr.Get("bang"))
+	finished.Failure = &test.Failure{
+		Kind:    test.FailureKindApplication,
+		Message: "bang",
+		Details: []*test.Payload{fake.GenInput()},
+		Cause: &test.Failure{
+			Kind:    test.FailureKindApplication,
+			Message: "cause",
+			Details: []*test.Payload{fake.GenInput()},
+		},
+	}
+
+	got, err := w.UpdateFinishedCaseExecution(ctx, finished)
+	require.NoError(t, err)
+	assert.Equal(t, finished.Failure, got.Failure)
+
+	stored := db.caseExecs[key].Failure
+	for _, f := range []*test.Failure{stored, stored.Cause} {
+		assert.Equal(t, codec.MetadataEncodingEncrypted, string(f.Details[0].Metadata[converter.MetadataEncoding]))
+	}
+	assert.NotEqual(t, finished.Failure.Details[0].Data, stored.Details[0].Data)
+
+	got, err = r.GetCaseExecution(ctx, ce.TestExecutionID, ce.ID)
+	require.NoError(t, err)
+	assert.Equal(t, finished.Failure, got.Failure)
+}
+
+func TestCaseExecutionWriter_CaseExecutionAttempts(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewCaseExecutionWriter(db)
+	r := NewCaseExecutionReader(db)
+
+	ce := fake.GenCaseExec(test.NewTestExecutionID())
+	db.caseExecs[getCaseExecKey(ce.TestExecutionID, ce.ID)] = ce
+
+	start := func(attempt int32) time.Time {
+		startTime := time.Now().UTC()
+		got, err := w.CreateStartedCaseExecutionAttempt(ctx, &test.StartedCaseExecutionAttempt{
+			TestExecutionID: ce.TestExecutionID,
+			CaseExecutionID: ce.ID,
+			Attempt:         attempt,
+			StartTime:       startTime,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, test.ExecutionStatusRunning, got.Status)
+		return startTime
+	}
+	finish := func(attempt int32, execErr *string) {
+		got, err := w.UpdateFinishedCaseExecutionAttempt(ctx, &test.FinishedCaseExecutionAttempt{
+			TestExecutionID: ce.TestExecutionID,
+			CaseExecutionID: ce.ID,
+			Attempt:         attempt,
+			FinishTime:      time.Now().UTC(),
+			Error:           execErr,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, execErr, got.Error)
+	}
+
+	_, err := w.CreateStartedCaseExecutionAttempt(ctx, &test.StartedCaseExecutionAttempt{
+		TestExecutionID: ce.TestExecutionID,
+		CaseExecutionID: fake.GenCaseID(),
+		Attempt:         1,
+		StartTime:       time.Now(),
+	})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	// Attempt 1 never reports finishing, e.g. because its runner crashed
+	start(1)
+	secondStart := start(2)
+	finish(2, ptr.Get("boom"))
+	start(3)
+	finish(3, nil)
+
+	got, err := r.ListCaseExecutionAttempts(ctx, ce.TestExecutionID, ce.ID)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, int32(1), got[0].Attempt)
+	assert.Equal(t, test.ExecutionStatusTimedOut, got[0].Status)
+	assert.Equal(t, secondStart, *got[0].FinishTime)
+	assert.Equal(t, int32(2), got[1].Attempt)
+	assert.Equal(t, test.ExecutionStatusFailed, got[1].Status)
+	assert.Equal(t, int32(3), got[2].Attempt)
+	assert.Equal(t, test.ExecutionStatusPassed, got[2].Status)
+	assert.NotNil(t, got[2].StartTime)
+	assert.NotNil(t, got[2].FinishTime)
+
+	// Finished attempts that were never recorded as started have no start time
+	finish(5, nil)
+	got, err = r.ListCaseExecutionAttempts(ctx, ce.TestExecutionID, ce.ID)
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+	assert.Equal(t, int32(5), got[3].Attempt)
+	assert.Nil(t, got[3].StartTime)
+
+	require.NoError(t, w.DeleteCaseExecution(ctx, ce.TestExecutionID, ce.ID))
+	assert.Empty(t, db.caseExecAttempts)
+}
@@ -20,13 +20,17 @@ func NewContextReader(db *DB) *ContextReader {
 }
 
 func (c *ContextReader) ListContexts(_ context.Context) ([]string, error) {
-	return c.db.contexts.ToSlice(), nil
+	return c.db.contexts.Difference(c.db.archivedContexts).ToSlice(), nil
 }
 
 func (c *ContextReader) ContextExists(_ context.Context, id string) (bool, error) {
 	return c.db.contexts.Contains(id), nil
 }
 
+func (c *ContextReader) IsContextArchived(_ context.Context, id string) (bool, error) {
+	return c.db.archivedContexts.Contains(id), nil
+}
+
 type ContextWriter struct {
 	db *DB
 }
@@ -39,3 +43,41 @@ func (c *ContextWriter) CreateContext(_ context.Context, id string) error {
 	c.db.contexts.Add(id)
 	return nil
 }
+
+func (c *ContextWriter) UpdateContextArchived(_ context.Context, id string, archived bool) error {
+	if archived {
+		c.db.archivedContexts.Add(id)
+	} else {
+		c.db.archivedContexts.Remove(id)
+	}
+	return nil
+}
+
+func (c *ContextWriter) DeleteContext(_ context.Context, id string) error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	if err := c.db.deleteTestsUnsafe(func(t *test.Test) bool {
+		return t.ContextID == id
+	}); err != nil {
+		return err
+	}
+
+	for runID, run := range c.db.testRuns {
+		if run.ContextID == id {
+			delete(c.db.testRuns, runID)
+		}
+	}
+
+	for group := range c.db.groups.Iter() {
+		if group.contextID == id {
+			c.db.groups.Remove(group)
+			c.db.archivedGroups.Remove(group)
+			delete(c.db.groupTaskQueues, group)
+		}
+	}
+
+	c.db.contexts.Remove(id)
+	c.db.archivedContexts.Remove(id)
+	return nil
+}
@@ -12,31 +12,84 @@ import (
 type DB struct {
 	mu               *sync.RWMutex
 	contexts         mapset.Set[string]
+	archivedContexts mapset.Set[string]
 	groups           mapset.Set[groupKey]
+	archivedGroups   mapset.Set[groupKey]
+	groupTaskQueues  map[groupKey]string
 	tests            map[uuid.UUID]*test.Test
 	defaultInputs    map[uuid.UUID]*test.Payload
+	testVersions     map[uuid.UUID]test.TestVersionList
 	testExecs        map[test.TestExecutionID]*test.TestExecution
-	testExecPayloads map[test.TestExecutionID][]byte
+	testExecPayloads map[test.TestExecutionID]*test.Payload
 	caseExecs        map[caseExecKey]*test.CaseExecution
+	caseExecPayloads map[caseExecKey]*test.CaseExecutionPayloads
+	caseExecAttempts map[caseExecKey]test.CaseExecutionAttemptList
 	execLogs         map[uuid.UUID]*test.Log
+	schedules        map[uuid.UUID]*test.Schedule
+	scheduleInputs   map[uuid.UUID]*test.Payload
+	testRuns         map[uuid.UUID]*test.TestRun
+	attempts         map[test.TestExecutionID][]*archivedAttempt
 	events           *TestExecutionEventSource
+	codec            test.PayloadCodec
 }
 
-func NewDB() *DB {
+type DBOption func(opts *dbOptions)
+
+// WithPayloadCodec encodes test inputs, failure details and progress with the
+// codec before they are stored and decodes them when they are read, e.g. to
+// encrypt them at rest.
+func WithPayloadCodec(codec test.PayloadCodec) DBOption {
+	return func(opts *dbOptions) {
+		opts.codec = codec
+	}
+}
+
+func NewDB(opts ...DBOption) *DB {
+	var options dbOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &DB{
 		mu:               new(sync.RWMutex),
 		contexts:         mapset.NewSet[string](),
+		archivedContexts: mapset.NewSet[string](),
 		groups:           mapset.NewSet[groupKey](),
+		archivedGroups:   mapset.NewSet[groupKey](),
+		groupTaskQueues:  map[groupKey]string{},
 		tests:            map[uuid.UUID]*test.Test{},
 		defaultInputs:    map[uuid.UUID]*test.Payload{},
+		testVersions:     map[uuid.UUID]test.TestVersionList{},
 		testExecs:        map[test.TestExecutionID]*test.TestExecution{},
-		testExecPayloads: map[test.TestExecutionID][]byte{},
+		testExecPayloads: map[test.TestExecutionID]*test.Payload{},
 		caseExecs:        map[caseExecKey]*test.CaseExecution{},
+		caseExecPayloads: map[caseExecKey]*test.CaseExecutionPayloads{},
+		caseExecAttempts: map[caseExecKey]test.CaseExecutionAttemptList{},
 		execLogs:         map[uuid.UUID]*test.Log{},
+		schedules:        map[uuid.UUID]*test.Schedule{},
+		scheduleInputs:   map[uuid.UUID]*test.Payload{},
+		testRuns:         map[uuid.UUID]*test.TestRun{},
+		attempts:         map[test.TestExecutionID][]*archivedAttempt{},
 		events:           NewTestExecutionEventSource(),
+		codec:            options.codec,
 	}
 }
 
+type dbOptions struct {
+	codec test.PayloadCodec
+}
+
+// archivedAttempt is a test execution attempt archived with the case
+// executions, their attempts and payloads, and the logs it had when it was
+// retried.
+type archivedAttempt struct {
+	attempt          *test.TestExecutionAttempt
+	caseExecs        test.CaseExecutionList
+	caseExecAttempts map[test.CaseExecutionID]test.CaseExecutionAttemptList
+	caseExecPayloads map[test.CaseExecutionID]*test.CaseExecutionPayloads
+	logs             test.LogList
+}
+
 func (d *DB) TestExecutionEventSource() *TestExecutionEventSource {
 	return d.events
 }
@@ -35,20 +35,39 @@ func (e *LogReader) GetLog(_ context.Context, id uuid.UUID) (*test.Log, error) {
 func (e *LogReader) ListLogs(_ context.Context, testExecID test.TestExecutionID) (test.LogList, error) {
 	e.db.mu.RLock()
 	defer e.db.mu.RUnlock()
+	return listLogsUnsafe(e.db, testExecID), nil
+}
+
+func (e *LogReader) ListAttemptLogs(_ context.Context, testExecID test.TestExecutionID, attempt int32) (test.LogList, error) {
+	e.db.mu.RLock()
+	defer e.db.mu.RUnlock()
+
+	a, ok := getAttemptUnsafe(e.db, testExecID, attempt)
+	if !ok {
+		return nil, test.ErrorAttemptNotFound
+	}
+
+	logs := make(test.LogList, len(a.logs))
+	for i, l := range a.logs {
+		logs[i] = ptr.Copy(l)
+	}
+	return logs, nil
+}
 
+func listLogsUnsafe(db *DB, testExecID test.TestExecutionID) test.LogList {
 	var logs test.LogList
-	for _, l := range e.db.execLogs {
+	for _, l := range db.execLogs {
 		if l.TestExecutionID == testExecID {
 			logs = append(logs, ptr.Copy(l))
 		}
 	}
 	slices.SortFunc(logs, func(a, b *test.Log) int {
-		if a.CreateTime.Before(b.CreateTime) {
+		if a.CreateTime.Before(b.CreateTime) || a.CreateTime.Equal(b.CreateTime) && a.ID.String() < b.ID.String() {
 			return -1
 		}
 		return 1
 	})
-	return logs, nil
+	return logs
 }
 
 type LogWriter struct {
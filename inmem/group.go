@@ -3,6 +3,8 @@ package inmem
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/annexsh/annex/test"
 )
 
@@ -19,9 +21,12 @@ func NewGroupReader(db *DB) *GroupReader {
 	return &GroupReader{db: db}
 }
 
-func (g *GroupReader) ListGroups(_ context.Context, contextID string) ([]string, error) {
+func (g *GroupReader) ListGroups(_ context.Context, contextID string, filter *test.GroupListFilter) ([]string, error) {
 	var groups []string
 	for group := range g.db.groups.Iter() {
+		if g.db.archivedGroups.Contains(group) && !filter.IncludeArchived {
+			continue
+		}
 		if group.contextID == contextID {
 			groups = append(groups, group.groupID)
 		}
@@ -33,6 +38,33 @@ func (g *GroupReader) GroupExists(_ context.Context, contextID string, groupID s
 	return g.db.groups.Contains(getGroupKey(contextID, groupID)), nil
 }
 
+func (g *GroupReader) GetGroupTaskQueue(_ context.Context, contextID string, groupID string) (string, error) {
+	g.db.mu.RLock()
+	defer g.db.mu.RUnlock()
+
+	taskQueue, ok := g.db.groupTaskQueues[getGroupKey(contextID, groupID)]
+	if !ok {
+		return "", test.ErrorGroupNotFound
+	}
+	return taskQueue, nil
+}
+
+func (g *GroupReader) TaskQueueExists(_ context.Context, taskQueue string) (bool, error) {
+	g.db.mu.RLock()
+	defer g.db.mu.RUnlock()
+
+	for _, tq := range g.db.groupTaskQueues {
+		if tq == taskQueue {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (g *GroupReader) IsGroupArchived(_ context.Context, contextID string, groupID string) (bool, error) {
+	return g.db.archivedGroups.Contains(getGroupKey(contextID, groupID)), nil
+}
+
 type GroupWriter struct {
 	db *DB
 }
@@ -41,8 +73,91 @@ func NewGroupWriter(db *DB) *GroupWriter {
 	return &GroupWriter{db: db}
 }
 
-func (g *GroupWriter) CreateGroup(_ context.Context, contextID string, groupID string) error {
-	g.db.groups.Add(getGroupKey(contextID, groupID))
+func (g *GroupWriter) CreateGroup(_ context.Context, contextID string, groupID string, taskQueue string) error {
+	g.db.mu.Lock()
+	defer g.db.mu.Unlock()
+
+	key := getGroupKey(contextID, groupID)
+	if !g.db.groups.Contains(key) {
+		g.db.groupTaskQueues[key] = taskQueue
+	}
+	g.db.groups.Add(key)
+	g.db.archivedGroups.Remove(key)
+	return nil
+}
+
+func (g *GroupWriter) UpdateGroupArchived(_ context.Context, contextID string, groupID string, archived bool) error {
+	key := getGroupKey(contextID, groupID)
+	if archived {
+		g.db.archivedGroups.Add(key)
+	} else {
+		g.db.archivedGroups.Remove(key)
+	}
+	return nil
+}
+
+func (g *GroupWriter) DeleteGroup(_ context.Context, contextID string, groupID string) error {
+	g.db.mu.Lock()
+	defer g.db.mu.Unlock()
+
+	if err := g.db.deleteTestsUnsafe(func(t *test.Test) bool {
+		return t.ContextID == contextID && t.GroupID == groupID
+	}); err != nil {
+		return err
+	}
+
+	for runID, run := range g.db.testRuns {
+		if run.ContextID == contextID && run.GroupID != nil && *run.GroupID == groupID {
+			delete(g.db.testRuns, runID)
+		}
+	}
+
+	key := getGroupKey(contextID, groupID)
+	g.db.groups.Remove(key)
+	g.db.archivedGroups.Remove(key)
+	delete(g.db.groupTaskQueues, key)
+	return nil
+}
+
+func (g *GroupWriter) RenameGroup(_ context.Context, contextID string, groupID string, newGroupID string) error {
+	g.db.mu.Lock()
+	defer g.db.mu.Unlock()
+
+	key := getGroupKey(contextID, groupID)
+	newKey := getGroupKey(contextID, newGroupID)
+
+	if g.db.groups.Contains(newKey) {
+		return test.ErrorGroupAlreadyExists
+	}
+
+	inGroup := func(t *test.Test) bool {
+		return t.ContextID == contextID && t.GroupID == groupID
+	}
+	if g.db.hasActiveExecutionsUnsafe(inGroup) {
+		return test.ErrorExecutionsActive
+	}
+
+	for _, t := range g.db.tests {
+		if inGroup(t) {
+			t.GroupID = newGroupID
+		}
+	}
+	for _, run := range g.db.testRuns {
+		if run.ContextID == contextID && run.GroupID != nil && *run.GroupID == groupID {
+			run.GroupID = &newGroupID
+		}
+	}
+
+	if g.db.groups.Contains(key) {
+		g.db.groups.Remove(key)
+		g.db.groups.Add(newKey)
+		g.db.groupTaskQueues[newKey] = g.db.groupTaskQueues[key]
+		delete(g.db.groupTaskQueues, key)
+	}
+	if g.db.archivedGroups.Contains(key) {
+		g.db.archivedGroups.Remove(key)
+		g.db.archivedGroups.Add(newKey)
+	}
 	return nil
 }
 
@@ -57,3 +172,73 @@ func getGroupKey(contextID string, groupID string) groupKey {
 		groupID:   groupID,
 	}
 }
+
+// hasActiveExecutionsUnsafe reports whether any execution of the matching
+// tests has not finished.
+func (d *DB) hasActiveExecutionsUnsafe(match func(t *test.Test) bool) bool {
+	for _, te := range d.testExecs {
+		if t, ok := d.tests[te.TestID]; ok && match(t) && !te.Status.IsFinal() {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteTestsUnsafe deletes the matching tests with their executions. Nothing
+// is deleted if any execution of the tests has not finished.
+func (d *DB) deleteTestsUnsafe(match func(t *test.Test) bool) error {
+	if d.hasActiveExecutionsUnsafe(match) {
+		return test.ErrorExecutionsActive
+	}
+
+	deletedTests := map[uuid.UUID]bool{}
+	for id, t := range d.tests {
+		if match(t) {
+			deletedTests[id] = true
+		}
+	}
+
+	deletedExecs := map[test.TestExecutionID]bool{}
+	for id, te := range d.testExecs {
+		if deletedTests[te.TestID] {
+			deletedExecs[id] = true
+		}
+	}
+
+	for id := range deletedExecs {
+		delete(d.testExecs, id)
+		delete(d.testExecPayloads, id)
+		delete(d.attempts, id)
+	}
+	for _, te := range d.testExecs {
+		if te.RerunOfID != nil && deletedExecs[*te.RerunOfID] {
+			te.RerunOfID = nil
+		}
+	}
+	for key := range d.caseExecs {
+		if deletedExecs[key.testExecID] {
+			delete(d.caseExecs, key)
+			delete(d.caseExecPayloads, key)
+			delete(d.caseExecAttempts, key)
+		}
+	}
+	for id, l := range d.execLogs {
+		if deletedExecs[l.TestExecutionID] {
+			delete(d.execLogs, id)
+		}
+	}
+
+	for id, s := range d.schedules {
+		if deletedTests[s.TestID] {
+			delete(d.schedules, id)
+			delete(d.scheduleInputs, id)
+		}
+	}
+	for id := range deletedTests {
+		delete(d.tests, id)
+		delete(d.defaultInputs, id)
+		delete(d.testVersions, id)
+	}
+
+	return nil
+}
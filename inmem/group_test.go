@@ -0,0 +1,178 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/test"
+)
+
+func TestGroupReader_ListGroups_archived(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewGroupReader(db)
+	w := NewGroupWriter(db)
+
+	contextID := uuid.NewString()
+
+	require.NoError(t, w.CreateGroup(ctx, contextID, "active", uuid.NewString()))
+	require.NoError(t, w.CreateGroup(ctx, contextID, "archived", uuid.NewString()))
+	require.NoError(t, w.UpdateGroupArchived(ctx, contextID, "archived", true))
+
+	got, err := r.ListGroups(ctx, contextID, &test.GroupListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"active"}, got)
+
+	got, err = r.ListGroups(ctx, contextID, &test.GroupListFilter{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"active", "archived"}, got)
+
+	archived, err := r.IsGroupArchived(ctx, contextID, "archived")
+	require.NoError(t, err)
+	assert.True(t, archived)
+
+	// Creating an archived group restores it
+	require.NoError(t, w.CreateGroup(ctx, contextID, "archived", uuid.NewString()))
+	archived, err = r.IsGroupArchived(ctx, contextID, "archived")
+	require.NoError(t, err)
+	assert.False(t, archived)
+}
+
+func TestGroupWriter_DeleteGroup(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewTestRepository(db)
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+
+	require.NoError(t, repo.CreateGroup(ctx, contextID, groupID, uuid.NewString()))
+	require.NoError(t, repo.CreateGroup(ctx, contextID, "other", uuid.NewString()))
+
+	deleted, err := repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID)))
+	require.NoError(t, err)
+	kept, err := repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID("other")))
+	require.NoError(t, err)
+
+	te, err := repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(deleted.ID))
+	require.NoError(t, err)
+	ce, err := repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(te.ID))
+	require.NoError(t, err)
+	log := fake.GenCaseExecLog(te.ID, ce.ID)
+	require.NoError(t, repo.CreateLog(ctx, log))
+	_, err = repo.CreateSchedule(ctx, fake.GenScheduleDefinition(deleted.ID))
+	require.NoError(t, err)
+
+	keptExec, err := repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(kept.ID))
+	require.NoError(t, err)
+
+	groupRun, err := repo.CreateTestRun(ctx, &test.TestRunDefinition{
+		ID:        uuid.New(),
+		ContextID: contextID,
+		GroupID:   &groupID,
+	})
+	require.NoError(t, err)
+	contextRun, err := repo.CreateTestRun(ctx, &test.TestRunDefinition{
+		ID:        uuid.New(),
+		ContextID: contextID,
+	})
+	require.NoError(t, err)
+
+	err = repo.DeleteGroup(ctx, contextID, groupID)
+	require.ErrorIs(t, err, test.ErrorExecutionsActive)
+	_, err = repo.GetTest(ctx, deleted.ID)
+	require.NoError(t, err)
+
+	_, err = repo.UpdateStartedTestExecution(ctx, fake.GenStartedTestExec(te.ID))
+	require.NoError(t, err)
+	_, err = repo.UpdateFinishedTestExecution(ctx, fake.GenFinishedTestExec(te.ID, nil))
+	require.NoError(t, err)
+
+	err = repo.DeleteGroup(ctx, contextID, groupID)
+	require.NoError(t, err)
+
+	exists, err := repo.GroupExists(ctx, contextID, groupID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = repo.GetTest(ctx, deleted.ID)
+	require.Error(t, err)
+	_, err = repo.GetTestExecution(ctx, te.ID)
+	require.Error(t, err)
+	_, err = repo.GetCaseExecution(ctx, te.ID, ce.ID)
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+	_, err = repo.GetLog(ctx, log.ID)
+	require.ErrorIs(t, err, test.ErrorLogNotFound)
+	schedules, err := repo.ListSchedules(ctx, deleted.ID)
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+	_, err = repo.GetTestRun(ctx, groupRun.ID)
+	require.ErrorIs(t, err, test.ErrorTestRunNotFound)
+
+	// Other groups are untouched
+	_, err = repo.GetTest(ctx, kept.ID)
+	require.NoError(t, err)
+	_, err = repo.GetTestExecution(ctx, keptExec.ID)
+	require.NoError(t, err)
+	_, err = repo.GetTestRun(ctx, contextRun.ID)
+	require.NoError(t, err)
+}
+
+func TestGroupWriter_RenameGroup(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewTestRepository(db)
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+	newGroupID := uuid.NewString()
+
+	require.NoError(t, repo.CreateGroup(ctx, contextID, groupID, uuid.NewString()))
+	require.NoError(t, repo.CreateGroup(ctx, contextID, "taken", uuid.NewString()))
+
+	tt, err := repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID)))
+	require.NoError(t, err)
+	run, err := repo.CreateTestRun(ctx, &test.TestRunDefinition{
+		ID:        uuid.New(),
+		ContextID: contextID,
+		GroupID:   &groupID,
+	})
+	require.NoError(t, err)
+	te, err := repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+
+	err = repo.RenameGroup(ctx, contextID, groupID, "taken")
+	require.ErrorIs(t, err, test.ErrorGroupAlreadyExists)
+
+	err = repo.RenameGroup(ctx, contextID, groupID, newGroupID)
+	require.ErrorIs(t, err, test.ErrorExecutionsActive)
+
+	_, err = repo.UpdateStartedTestExecution(ctx, fake.GenStartedTestExec(te.ID))
+	require.NoError(t, err)
+	_, err = repo.UpdateFinishedTestExecution(ctx, fake.GenFinishedTestExec(te.ID, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RenameGroup(ctx, contextID, groupID, newGroupID))
+
+	groups, err := repo.ListGroups(ctx, contextID, &test.GroupListFilter{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{newGroupID, "taken"}, groups)
+
+	got, err := repo.GetTest(ctx, tt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newGroupID, got.GroupID)
+
+	gotRun, err := repo.GetTestRun(ctx, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &newGroupID, gotRun.GroupID)
+
+	execs, err := repo.ListTestExecutions(ctx, tt.ID, &test.TestExecutionListFilter{PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, execs, 1)
+	assert.Equal(t, te.ID, execs[0].ID)
+}
@@ -0,0 +1,169 @@
+package inmem
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/annexsh/annex/internal/ptr"
+	"github.com/annexsh/annex/test"
+)
+
+var (
+	_ test.ScheduleReader = (*ScheduleReader)(nil)
+	_ test.ScheduleWriter = (*ScheduleWriter)(nil)
+)
+
+type ScheduleReader struct {
+	db *DB
+}
+
+func NewScheduleReader(db *DB) *ScheduleReader {
+	return &ScheduleReader{db: db}
+}
+
+func (s *ScheduleReader) GetSchedule(_ context.Context, id uuid.UUID) (*test.Schedule, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	schedule, ok := s.db.schedules[id]
+	if !ok {
+		return nil, test.ErrorScheduleNotFound
+	}
+	return ptr.Copy(schedule), nil
+}
+
+func (s *ScheduleReader) GetScheduleInput(_ context.Context, id uuid.UUID) (*test.Payload, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	input, ok := s.db.scheduleInputs[id]
+	if !ok {
+		return nil, test.ErrorScheduleNotFound
+	}
+	return test.DecodePayload(s.db.codec, ptr.Copy(input))
+}
+
+func (s *ScheduleReader) ListSchedules(_ context.Context, testID uuid.UUID) (test.ScheduleList, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var schedules test.ScheduleList
+	for _, schedule := range s.db.schedules {
+		if schedule.TestID == testID {
+			schedules = append(schedules, ptr.Copy(schedule))
+		}
+	}
+
+	slices.SortFunc(schedules, func(a, b *test.Schedule) int {
+		return compareScheduled(a.CreateTime, a.ID, b.CreateTime, b.ID)
+	})
+
+	return schedules, nil
+}
+
+func (s *ScheduleReader) ListDueSchedules(_ context.Context, now time.Time) (test.ScheduleList, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var due test.ScheduleList
+	for _, schedule := range s.db.schedules {
+		if !schedule.Paused && !schedule.NextFireTime.After(now) {
+			due = append(due, ptr.Copy(schedule))
+		}
+	}
+
+	slices.SortFunc(due, func(a, b *test.Schedule) int {
+		return compareScheduled(a.NextFireTime, a.ID, b.NextFireTime, b.ID)
+	})
+
+	return due, nil
+}
+
+type ScheduleWriter struct {
+	db *DB
+}
+
+func NewScheduleWriter(db *DB) *ScheduleWriter {
+	return &ScheduleWriter{db: db}
+}
+
+func (s *ScheduleWriter) CreateSchedule(_ context.Context, definition *test.ScheduleDefinition) (*test.Schedule, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.tests[definition.TestID]; !ok {
+		return nil, test.ErrorTestNotFound
+	}
+
+	input, err := test.EncodePayload(s.db.codec, definition.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &test.Schedule{
+		ID:           definition.ID,
+		TestID:       definition.TestID,
+		Cron:         definition.Cron,
+		HasInput:     definition.Input != nil,
+		CreateTime:   time.Now().UTC(),
+		NextFireTime: definition.NextFireTime,
+	}
+	s.db.schedules[schedule.ID] = schedule
+	if definition.Input != nil {
+		s.db.scheduleInputs[schedule.ID] = ptr.Copy(input)
+	}
+	return ptr.Copy(schedule), nil
+}
+
+func (s *ScheduleWriter) UpdateFiredSchedule(_ context.Context, fired *test.FiredSchedule) (*test.Schedule, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	schedule, ok := s.db.schedules[fired.ID]
+	if !ok || schedule.Paused || !schedule.NextFireTime.Equal(fired.FireTime) {
+		return nil, test.ErrorScheduleNotDue
+	}
+	schedule.LastFireTime = &fired.FireTime
+	schedule.NextFireTime = fired.NextFireTime
+	return ptr.Copy(schedule), nil
+}
+
+func (s *ScheduleWriter) UpdatePausedSchedule(_ context.Context, id uuid.UUID) (*test.Schedule, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	schedule, ok := s.db.schedules[id]
+	if !ok {
+		return nil, test.ErrorScheduleNotFound
+	}
+	schedule.Paused = true
+	return ptr.Copy(schedule), nil
+}
+
+func (s *ScheduleWriter) UpdateResumedSchedule(_ context.Context, resumed *test.ResumedSchedule) (*test.Schedule, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	schedule, ok := s.db.schedules[resumed.ID]
+	if !ok {
+		return nil, test.ErrorScheduleNotFound
+	}
+	schedule.Paused = false
+	schedule.NextFireTime = resumed.NextFireTime
+	return ptr.Copy(schedule), nil
+}
+
+func (s *ScheduleWriter) DeleteSchedule(_ context.Context, id uuid.UUID) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.schedules[id]; !ok {
+		return test.ErrorScheduleNotFound
+	}
+	delete(s.db.schedules, id)
+	delete(s.db.scheduleInputs, id)
+	return nil
+}
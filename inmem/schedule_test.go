@@ -0,0 +1,256 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/test"
+)
+
+func TestScheduleReader_GetSchedule(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewScheduleReader(db)
+
+	want := fake.GenSchedule(uuid.New())
+	db.schedules[want.ID] = want
+
+	got, err := r.GetSchedule(ctx, want.ID)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = r.GetSchedule(ctx, uuid.New())
+	assert.ErrorIs(t, err, test.ErrorScheduleNotFound)
+}
+
+func TestScheduleReader_ListSchedules(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewScheduleReader(db)
+
+	testID := uuid.New()
+	now := time.Now()
+
+	count := 5
+	want := make(test.ScheduleList, count)
+
+	for i := range count {
+		schedule := fake.GenSchedule(testID)
+		schedule.CreateTime = now.Add(time.Duration(i) * time.Second)
+		want[i] = schedule
+		db.schedules[schedule.ID] = schedule
+	}
+
+	other := fake.GenSchedule(uuid.New())
+	db.schedules[other.ID] = other
+
+	got, err := r.ListSchedules(ctx, testID)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestScheduleReader_ListDueSchedules(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewScheduleReader(db)
+
+	testID := uuid.New()
+	now := time.Now()
+
+	genSchedule := func(nextFireTime time.Time, paused bool) *test.Schedule {
+		schedule := fake.GenSchedule(testID)
+		schedule.NextFireTime = nextFireTime
+		schedule.Paused = paused
+		db.schedules[schedule.ID] = schedule
+		return schedule
+	}
+
+	dueOldest := genSchedule(now.Add(-time.Hour), false)
+	dueExact := genSchedule(now, false)
+	genSchedule(now.Add(time.Minute), false) // not due
+	genSchedule(now.Add(-time.Hour), true)   // paused
+
+	got, err := r.ListDueSchedules(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, test.ScheduleList{dueOldest, dueExact}, got)
+}
+
+func TestScheduleWriter_CreateSchedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		existingTest bool
+		withInput    bool
+		wantErr      error
+	}{
+		{
+			name:         "create success",
+			existingTest: true,
+		},
+		{
+			name:         "create with input success",
+			existingTest: true,
+			withInput:    true,
+		},
+		{
+			name:         "test not found error",
+			existingTest: false,
+			wantErr:      test.ErrorTestNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := NewDB()
+			w := NewScheduleWriter(db)
+
+			existing := fake.GenTest()
+			if tt.existingTest {
+				db.tests[existing.ID] = existing
+			}
+
+			def := fake.GenScheduleDefinition(existing.ID)
+			if !tt.withInput {
+				def.Input = nil
+			}
+
+			got, err := w.CreateSchedule(ctx, def)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, def.ID, got.ID)
+			assert.Equal(t, def.TestID, got.TestID)
+			assert.Equal(t, def.Cron, got.Cron)
+			assert.Equal(t, tt.withInput, got.HasInput)
+			assert.False(t, got.Paused)
+			assert.Equal(t, def.NextFireTime, got.NextFireTime)
+			assert.Nil(t, got.LastFireTime)
+
+			if tt.withInput {
+				assert.Equal(t, def.Input, db.scheduleInputs[def.ID])
+			} else {
+				assert.NotContains(t, db.scheduleInputs, def.ID)
+			}
+		})
+	}
+}
+
+func TestScheduleWriter_UpdateFiredSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		paused    bool
+		staleFire bool
+		wantErr   error
+	}{
+		{
+			name: "fire success",
+		},
+		{
+			name:    "paused schedule not due error",
+			paused:  true,
+			wantErr: test.ErrorScheduleNotDue,
+		},
+		{
+			name:      "already fired schedule not due error",
+			staleFire: true,
+			wantErr:   test.ErrorScheduleNotDue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := NewDB()
+			w := NewScheduleWriter(db)
+
+			existing := fake.GenSchedule(uuid.New())
+			existing.Paused = tt.paused
+			db.schedules[existing.ID] = existing
+
+			fired := &test.FiredSchedule{
+				ID:           existing.ID,
+				FireTime:     existing.NextFireTime,
+				NextFireTime: existing.NextFireTime.Add(time.Hour),
+			}
+			if tt.staleFire {
+				fired.FireTime = existing.NextFireTime.Add(-time.Hour)
+			}
+
+			got, err := w.UpdateFiredSchedule(ctx, fired)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, fired.FireTime, *got.LastFireTime)
+			assert.Equal(t, fired.NextFireTime, got.NextFireTime)
+		})
+	}
+}
+
+func TestScheduleWriter_UpdatePausedSchedule(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewScheduleWriter(db)
+
+	existing := fake.GenSchedule(uuid.New())
+	db.schedules[existing.ID] = existing
+
+	got, err := w.UpdatePausedSchedule(ctx, existing.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Paused)
+
+	_, err = w.UpdatePausedSchedule(ctx, uuid.New())
+	assert.ErrorIs(t, err, test.ErrorScheduleNotFound)
+}
+
+func TestScheduleWriter_UpdateResumedSchedule(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewScheduleWriter(db)
+
+	existing := fake.GenSchedule(uuid.New())
+	existing.Paused = true
+	db.schedules[existing.ID] = existing
+
+	resumed := &test.ResumedSchedule{
+		ID:           existing.ID,
+		NextFireTime: existing.NextFireTime.Add(24 * time.Hour),
+	}
+	got, err := w.UpdateResumedSchedule(ctx, resumed)
+	require.NoError(t, err)
+	assert.False(t, got.Paused)
+	assert.Equal(t, resumed.NextFireTime, got.NextFireTime)
+
+	resumed.ID = uuid.New()
+	_, err = w.UpdateResumedSchedule(ctx, resumed)
+	assert.ErrorIs(t, err, test.ErrorScheduleNotFound)
+}
+
+func TestScheduleWriter_DeleteSchedule(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewScheduleWriter(db)
+
+	existing := fake.GenSchedule(uuid.New())
+	db.schedules[existing.ID] = existing
+	db.scheduleInputs[existing.ID] = fake.GenInput()
+
+	err := w.DeleteSchedule(ctx, existing.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, db.schedules, existing.ID)
+	assert.NotContains(t, db.scheduleInputs, existing.ID)
+
+	err = w.DeleteSchedule(ctx, existing.ID)
+	assert.ErrorIs(t, err, test.ErrorScheduleNotFound)
+}
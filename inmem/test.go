@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,12 +37,21 @@ func (t *TestReader) GetTest(_ context.Context, id uuid.UUID) (*test.Test, error
 	return ptr.Copy(tt), nil
 }
 
-func (t *TestReader) ListTests(_ context.Context, contextID string, groupID string) (test.TestList, error) {
+func (t *TestReader) ListTests(_ context.Context, contextID string, groupID string, filter *test.TestListFilter) (test.TestList, error) {
 	t.db.mu.RLock()
 	defer t.db.mu.RUnlock()
 
 	var tests test.TestList
 	for _, tt := range t.db.tests {
+		if tt.Deprecated && !filter.IncludeDeprecated {
+			continue
+		}
+		if filter.Owner != "" && tt.Metadata.Owner != filter.Owner {
+			continue
+		}
+		if !containsAll(tt.Metadata.Tags, filter.Tags) {
+			continue
+		}
 		if tt.ContextID == contextID && tt.GroupID == groupID {
 			tests = append(tests, ptr.Copy(tt))
 		}
@@ -57,6 +67,74 @@ func (t *TestReader) ListTests(_ context.Context, contextID string, groupID stri
 	return tests, nil
 }
 
+func (t *TestReader) SearchTests(_ context.Context, filter *test.TestSearchFilter) (test.TestList, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	var tests test.TestList
+	for _, tt := range t.db.tests {
+		if tt.Deprecated && !filter.IncludeDeprecated {
+			continue
+		}
+		if t.db.archivedContexts.Contains(tt.ContextID) || t.db.archivedGroups.Contains(getGroupKey(tt.ContextID, tt.GroupID)) {
+			continue
+		}
+		if filter.NameContains != nil && !strings.Contains(strings.ToLower(tt.Name), strings.ToLower(*filter.NameContains)) {
+			continue
+		}
+		if filter.Owner != "" && tt.Metadata.Owner != filter.Owner {
+			continue
+		}
+		if !containsAll(tt.Metadata.Tags, filter.Tags) {
+			continue
+		}
+		if filter.LastCreateTime != nil && filter.LastTestID != nil {
+			// Skip already seen
+			if compareScheduled(tt.CreateTime, tt.ID, *filter.LastCreateTime, *filter.LastTestID) >= 0 {
+				continue
+			}
+		}
+		tests = append(tests, ptr.Copy(tt))
+	}
+
+	// Most recent first
+	slices.SortFunc(tests, func(a, b *test.Test) int {
+		return -compareScheduled(a.CreateTime, a.ID, b.CreateTime, b.ID)
+	})
+
+	if filter.PageSize > 0 && uint32(len(tests)) > filter.PageSize {
+		tests = tests[:filter.PageSize]
+	}
+
+	return tests, nil
+}
+
+func (t *TestReader) GetTestVersion(_ context.Context, testID uuid.UUID, version int32) (*test.TestVersion, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	versions := t.db.testVersions[testID]
+	if version < 1 || int(version) > len(versions) {
+		return nil, test.ErrorTestVersionNotFound
+	}
+	return decodeTestVersion(t.db.codec, versions[version-1])
+}
+
+func (t *TestReader) ListTestVersions(_ context.Context, testID uuid.UUID) (test.TestVersionList, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	versions := t.db.testVersions[testID]
+	out := make(test.TestVersionList, len(versions))
+	for i, v := range versions {
+		var err error
+		if out[i], err = decodeTestVersion(t.db.codec, v); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
 func (t *TestReader) GetTestDefaultInput(_ context.Context, testID uuid.UUID) (*test.Payload, error) {
 	t.db.mu.RLock()
 	defer t.db.mu.RUnlock()
@@ -65,7 +143,7 @@ func (t *TestReader) GetTestDefaultInput(_ context.Context, testID uuid.UUID) (*
 	if !ok {
 		return nil, errors.New("not found")
 	}
-	return ptr.Copy(p), nil
+	return test.DecodePayload(t.db.codec, ptr.Copy(p))
 }
 
 type TestWriter struct {
@@ -79,7 +157,10 @@ func NewTestWriter(db *DB) *TestWriter {
 func (t *TestWriter) CreateTest(_ context.Context, definition *test.TestDefinition) (*test.Test, error) {
 	t.db.mu.Lock()
 	defer t.db.mu.Unlock()
-	tt := t.createTestUnsafe(definition)
+	tt, err := t.createTestUnsafe(definition)
+	if err != nil {
+		return nil, err
+	}
 	return ptr.Copy(tt), nil
 }
 
@@ -90,28 +171,154 @@ func (t *TestWriter) CreateTests(_ context.Context, definitions ...*test.TestDef
 	tests := make(test.TestList, len(definitions))
 	i := 0
 	for _, def := range definitions {
-		tt := t.createTestUnsafe(def)
+		tt, err := t.createTestUnsafe(def)
+		if err != nil {
+			return nil, err
+		}
 		tests[i] = ptr.Copy(tt)
 		i++
 	}
 	return tests, nil
 }
 
-func (t *TestWriter) createTestUnsafe(definition *test.TestDefinition) *test.Test {
+func (t *TestWriter) DeprecateTests(_ context.Context, contextID string, groupID string, keepNames []string) (test.TestList, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	var deprecated test.TestList
 	for _, tt := range t.db.tests {
-		if tt.ContextID == tt.ContextID && tt.GroupID == definition.GroupID && tt.Name == definition.Name {
+		if tt.ContextID != contextID || tt.GroupID != groupID || tt.Deprecated || slices.Contains(keepNames, tt.Name) {
+			continue
+		}
+		tt.Deprecated = true
+		deprecated = append(deprecated, ptr.Copy(tt))
+	}
+	return deprecated, nil
+}
+
+func (t *TestWriter) MoveTest(_ context.Context, id uuid.UUID, groupID string) (*test.Test, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	tt, ok := t.db.tests[id]
+	if !ok {
+		return nil, test.ErrorTestNotFound
+	}
+
+	for _, other := range t.db.tests {
+		if other.ContextID == tt.ContextID && other.GroupID == groupID && other.Name == tt.Name && other.ID != id {
+			return nil, test.ErrorTestAlreadyExists
+		}
+	}
+
+	if t.db.hasActiveExecutionsUnsafe(func(other *test.Test) bool { return other.ID == id }) {
+		return nil, test.ErrorExecutionsActive
+	}
+
+	tt.GroupID = groupID
+	return ptr.Copy(tt), nil
+}
+
+func (t *TestWriter) createTestUnsafe(definition *test.TestDefinition) (*test.Test, error) {
+	defaultInput, err := test.EncodePayload(t.db.codec, definition.DefaultInput)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	createTime := now
+	executionTimeout := definition.ExecutionTimeout
+	runTimeout := definition.RunTimeout
+	inputSchema := definition.InputSchema
+	var metadata test.TestMetadata
+	if definition.Metadata != nil {
+		metadata = *definition.Metadata
+	}
+	for _, tt := range t.db.tests {
+		if tt.ContextID == definition.ContextID && tt.GroupID == definition.GroupID && tt.Name == definition.Name {
 			definition.TestID = tt.ID
+			createTime = tt.CreateTime
+			// Unset fields are kept from the previous registration
+			if executionTimeout == nil {
+				executionTimeout = tt.ExecutionTimeout
+			}
+			if runTimeout == nil {
+				runTimeout = tt.RunTimeout
+			}
+			if inputSchema == nil {
+				inputSchema = tt.InputSchema
+			}
+			if definition.Metadata == nil {
+				metadata = tt.Metadata
+			}
 		}
 	}
 	tt := &test.Test{
-		ID:         definition.TestID,
-		ContextID:  definition.ContextID,
-		GroupID:    definition.GroupID,
-		Name:       definition.Name,
-		HasInput:   definition.DefaultInput != nil,
-		CreateTime: time.Now().UTC(),
+		ID:               definition.TestID,
+		ContextID:        definition.ContextID,
+		GroupID:          definition.GroupID,
+		Name:             definition.Name,
+		HasInput:         definition.DefaultInput != nil,
+		CreateTime:       createTime,
+		ExecutionTimeout: executionTimeout,
+		RunTimeout:       runTimeout,
+		InputSchema:      inputSchema,
+		Version:          1,
+		Metadata:         metadata,
+	}
+	tt.Metadata.Tags = slices.Clone(metadata.Tags)
+
+	versions := t.db.testVersions[tt.ID]
+	if len(versions) > 0 {
+		latest, err := decodeTestVersion(t.db.codec, versions[len(versions)-1])
+		if err != nil {
+			return nil, err
+		}
+		tt.Version = latest.Version
+		effective := *definition
+		effective.ExecutionTimeout = executionTimeout
+		effective.RunTimeout = runTimeout
+		effective.InputSchema = inputSchema
+		if !latest.Matches(&effective) {
+			tt.Version++
+		}
+	}
+	if tt.Version > int32(len(versions)) {
+		t.db.testVersions[tt.ID] = append(versions, &test.TestVersion{
+			TestID:           tt.ID,
+			Version:          tt.Version,
+			DefaultInput:     defaultInput,
+			ExecutionTimeout: executionTimeout,
+			RunTimeout:       runTimeout,
+			InputSchema:      inputSchema,
+			CreateTime:       now,
+		})
 	}
+
 	t.db.tests[tt.ID] = tt
-	t.db.defaultInputs[tt.ID] = definition.DefaultInput
-	return tt
+	t.db.defaultInputs[tt.ID] = defaultInput
+	return tt, nil
+}
+
+// decodeTestVersion copies a stored test version with its default input
+// decoded.
+func decodeTestVersion(codec test.PayloadCodec, v *test.TestVersion) (*test.TestVersion, error) {
+	out := ptr.Copy(v)
+	if v.DefaultInput != nil {
+		input, err := test.DecodePayload(codec, ptr.Copy(v.DefaultInput))
+		if err != nil {
+			return nil, err
+		}
+		out.DefaultInput = input
+	}
+	return out, nil
+}
+
+func containsAll(s []string, values []string) bool {
+	for _, v := range values {
+		if !slices.Contains(s, v) {
+			return false
+		}
+	}
+	return true
 }
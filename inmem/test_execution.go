@@ -3,7 +3,10 @@ package inmem
 import (
 	"context"
 	"errors"
+	"reflect"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -33,7 +36,7 @@ func (t *TestExecutionReader) GetTestExecution(_ context.Context, id test.TestEx
 	if !ok {
 		return nil, errors.New("not found")
 	}
-	return ptr.Copy(te), nil
+	return decodeTestExec(t.db.codec, te), nil
 }
 
 func (t *TestExecutionReader) GetTestExecutionInput(_ context.Context, id test.TestExecutionID) (*test.Payload, error) {
@@ -44,9 +47,7 @@ func (t *TestExecutionReader) GetTestExecutionInput(_ context.Context, id test.T
 	if !ok {
 		return nil, errors.New("not found")
 	}
-	return &test.Payload{
-		Data: p,
-	}, nil
+	return test.DecodePayload(t.db.codec, ptr.Copy(p))
 }
 
 func (t *TestExecutionReader) ListTestExecutions(_ context.Context, testID uuid.UUID, filter *test.TestExecutionListFilter) (test.TestExecutionList, error) {
@@ -70,7 +71,7 @@ func (t *TestExecutionReader) ListTestExecutions(_ context.Context, testID uuid.
 	var filtered test.TestExecutionList
 
 	for _, te := range execs {
-		if te.TestID == testID {
+		if te.TestID == testID && te.ParentID == nil && matchesTestExecFilter(te, filter) {
 			idstr := te.ID.String()
 			if filter.LastScheduleTime != nil {
 				// Skip already seen before last schedule time
@@ -85,7 +86,7 @@ func (t *TestExecutionReader) ListTestExecutions(_ context.Context, testID uuid.
 				}
 			}
 
-			filtered = append(filtered, ptr.Copy(te))
+			filtered = append(filtered, decodeTestExec(t.db.codec, te))
 			if uint32(len(filtered)) == filter.PageSize {
 				break
 			}
@@ -95,6 +96,193 @@ func (t *TestExecutionReader) ListTestExecutions(_ context.Context, testID uuid.
 	return filtered, nil
 }
 
+func (t *TestExecutionReader) ListRecentTestExecutions(_ context.Context, contextID string, filter *test.RecentTestExecutionListFilter) (test.TestExecutionSummaryList, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	var summaries test.TestExecutionSummaryList
+
+	for _, te := range t.db.testExecs {
+		if te.ParentID != nil {
+			continue
+		}
+		tt, ok := t.db.tests[te.TestID]
+		if !ok || tt.ContextID != contextID {
+			continue
+		}
+		if filter.GroupID != nil && tt.GroupID != *filter.GroupID {
+			continue
+		}
+		summaries = append(summaries, &test.TestExecutionSummary{
+			ContextID: tt.ContextID,
+			GroupID:   tt.GroupID,
+			TestName:  tt.Name,
+			Execution: decodeTestExec(t.db.codec, te),
+		})
+	}
+
+	// Most recent first
+	slices.SortFunc(summaries, func(a, b *test.TestExecutionSummary) int {
+		return -compareScheduled(a.Execution.ScheduleTime, a.Execution.ID.UUID, b.Execution.ScheduleTime, b.Execution.ID.UUID)
+	})
+
+	var filtered test.TestExecutionSummaryList
+
+	for _, summary := range summaries {
+		if filter.LastScheduleTime != nil && filter.LastTestExecutionID != nil {
+			// Skip already seen
+			if compareScheduled(summary.Execution.ScheduleTime, summary.Execution.ID.UUID, *filter.LastScheduleTime, *filter.LastTestExecutionID) >= 0 {
+				continue
+			}
+		}
+		filtered = append(filtered, summary)
+		if uint32(len(filtered)) == filter.PageSize {
+			break
+		}
+	}
+
+	return filtered, nil
+}
+
+func compareScheduled(aTime time.Time, aID uuid.UUID, bTime time.Time, bID uuid.UUID) int {
+	if c := aTime.Compare(bTime); c != 0 {
+		return c
+	}
+	return strings.Compare(aID.String(), bID.String())
+}
+
+func matchesTestExecFilter(te *test.TestExecution, filter *test.TestExecutionListFilter) bool {
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, te.Status) {
+		return false
+	}
+	if filter.ScheduledAfter != nil && te.ScheduleTime.Before(*filter.ScheduledAfter) {
+		return false
+	}
+	if filter.ScheduledBefore != nil && !te.ScheduleTime.Before(*filter.ScheduledBefore) {
+		return false
+	}
+	if filter.ErrorContains != nil {
+		if te.Error == nil || !strings.Contains(strings.ToLower(*te.Error), strings.ToLower(*filter.ErrorContains)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *TestExecutionReader) ListExpiredTestExecutions(_ context.Context, now time.Time) (test.TestExecutionList, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	var expired test.TestExecutionList
+
+	for _, te := range t.db.testExecs {
+		if te.Status.IsFinal() {
+			continue
+		}
+		if deadline, ok := testExecDeadline(te); ok && !deadline.After(now) {
+			expired = append(expired, decodeTestExec(t.db.codec, te))
+		}
+	}
+
+	slices.SortFunc(expired, func(a, b *test.TestExecution) int {
+		return compareScheduled(a.ScheduleTime, a.ID.UUID, b.ScheduleTime, b.ID.UUID)
+	})
+
+	return expired, nil
+}
+
+// testExecDeadline gets the time the execution or run timeout of a test
+// execution ends, whichever is first. Test executions scheduled before
+// timeouts were recorded have no deadline.
+func testExecDeadline(te *test.TestExecution) (time.Time, bool) {
+	var deadline time.Time
+	for _, timeout := range []*time.Duration{te.ExecutionTimeout, te.RunTimeout} {
+		if timeout == nil {
+			continue
+		}
+		if end := te.ScheduleTime.Add(*timeout); deadline.IsZero() || end.Before(deadline) {
+			deadline = end
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
+
+func (t *TestExecutionReader) GetTestExecutionAttempt(_ context.Context, id test.TestExecutionID, attempt int32) (*test.TestExecutionAttempt, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	a, ok := getAttemptUnsafe(t.db, id, attempt)
+	if !ok {
+		return nil, test.ErrorAttemptNotFound
+	}
+	return decodeTestExecAttempt(t.db.codec, a.attempt), nil
+}
+
+func (t *TestExecutionReader) ListTestExecutionAttempts(_ context.Context, id test.TestExecutionID) (test.TestExecutionAttemptList, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	attempts := make(test.TestExecutionAttemptList, len(t.db.attempts[id]))
+	for i, a := range t.db.attempts[id] {
+		attempts[i] = decodeTestExecAttempt(t.db.codec, a.attempt)
+	}
+	return attempts, nil
+}
+
+func (t *TestExecutionReader) GetSubTestExecution(_ context.Context, childWorkflowID string) (*test.TestExecution, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	var latest *test.TestExecution
+	for _, te := range t.db.testExecs {
+		if te.ParentID == nil || te.ChildWorkflowID != childWorkflowID {
+			continue
+		}
+		if latest == nil || compareScheduled(te.ScheduleTime, te.ID.UUID, latest.ScheduleTime, latest.ID.UUID) > 0 {
+			latest = te
+		}
+	}
+	if latest == nil {
+		return nil, test.ErrorTestExecutionNotFound
+	}
+	return decodeTestExec(t.db.codec, latest), nil
+}
+
+func (t *TestExecutionReader) ListSubTestExecutions(_ context.Context, parentID test.TestExecutionID) (test.TestExecutionList, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	var subExecs test.TestExecutionList
+	for _, te := range listSubTestExecsUnsafe(t.db, parentID) {
+		subExecs = append(subExecs, decodeTestExec(t.db.codec, te))
+	}
+	return subExecs, nil
+}
+
+// listSubTestExecsUnsafe lists the sub-executions of a test execution in
+// schedule order.
+func listSubTestExecsUnsafe(db *DB, parentID test.TestExecutionID) test.TestExecutionList {
+	var subExecs test.TestExecutionList
+	for _, te := range db.testExecs {
+		if te.ParentID != nil && *te.ParentID == parentID {
+			subExecs = append(subExecs, te)
+		}
+	}
+	slices.SortFunc(subExecs, func(a, b *test.TestExecution) int {
+		return compareScheduled(a.ScheduleTime, a.ID.UUID, b.ScheduleTime, b.ID.UUID)
+	})
+	return subExecs
+}
+
+func getAttemptUnsafe(db *DB, id test.TestExecutionID, attempt int32) (*archivedAttempt, bool) {
+	for _, a := range db.attempts[id] {
+		if a.attempt.Attempt == attempt {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
 type TestExecutionWriter struct {
 	db *DB
 }
@@ -111,16 +299,66 @@ func (t *TestExecutionWriter) CreateScheduledTestExecution(_ context.Context, sc
 		return nil, test.ErrorTestNotFound
 	}
 
+	input, err := test.EncodePayload(t.db.codec, scheduled.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	te := createTestExecUnsafe(t.db, scheduled, input)
+	return decodeTestExec(t.db.codec, te), nil
+}
+
+// createTestExecUnsafe creates a scheduled test execution with its encoded
+// input. The test must exist.
+func createTestExecUnsafe(db *DB, scheduled *test.ScheduledTestExecution, input *test.Payload) *test.TestExecution {
+	te := &test.TestExecution{
+		ID:               scheduled.ID,
+		TestID:           scheduled.TestID,
+		HasInput:         scheduled.Input != nil,
+		ScheduleTime:     scheduled.ScheduleTime,
+		Status:           test.ExecutionStatusScheduled,
+		ExecutionTimeout: ptr.Get(scheduled.ExecutionTimeout),
+		RunTimeout:       scheduled.RunTimeout,
+		TestRunID:        scheduled.TestRunID,
+		Attempt:          1,
+		RerunOfID:        scheduled.RerunOfID,
+		TestVersion:      scheduled.TestVersion,
+	}
+	db.testExecs[te.ID] = te
+	if scheduled.Input != nil {
+		db.testExecPayloads[te.ID] = ptr.Copy(input)
+	}
+	db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionScheduled, decodeTestExec(db.codec, te)))
+	return te
+}
+
+func (t *TestExecutionWriter) CreateScheduledSubTestExecution(_ context.Context, scheduled *test.ScheduledSubTestExecution) (*test.TestExecution, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	if te, ok := t.db.testExecs[scheduled.ID]; ok {
+		return decodeTestExec(t.db.codec, te), nil
+	}
+
+	parent, ok := t.db.testExecs[scheduled.ParentID]
+	if !ok {
+		return nil, test.ErrorTestExecutionNotFound
+	}
+
 	te := &test.TestExecution{
-		ID:           scheduled.ID,
-		TestID:       scheduled.TestID,
-		HasInput:     scheduled.Payload != nil,
-		ScheduleTime: scheduled.ScheduleTime,
+		ID:                scheduled.ID,
+		TestID:            parent.TestID,
+		ScheduleTime:      scheduled.ScheduleTime,
+		Status:            test.ExecutionStatusScheduled,
+		Attempt:           1,
+		TestVersion:       parent.TestVersion,
+		ParentID:          &parent.ID,
+		ChildWorkflowID:   scheduled.ChildWorkflowID,
+		ChildWorkflowType: scheduled.ChildWorkflowType,
 	}
 	t.db.testExecs[te.ID] = te
-	t.db.testExecPayloads[te.ID] = scheduled.Payload
-	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionScheduled, te))
-	return ptr.Copy(te), nil
+	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionScheduled, decodeTestExec(t.db.codec, te)))
+	return decodeTestExec(t.db.codec, te), nil
 }
 
 func (t *TestExecutionWriter) UpdateStartedTestExecution(_ context.Context, started *test.StartedTestExecution) (*test.TestExecution, error) {
@@ -131,10 +369,15 @@ func (t *TestExecutionWriter) UpdateStartedTestExecution(_ context.Context, star
 	if !ok {
 		return nil, test.ErrorTestExecutionNotFound
 	}
+	// Stopped test executions are not restarted by late acks
+	if te.Status.IsStopped() {
+		return decodeTestExec(t.db.codec, te), nil
+	}
 	te.StartTime = &started.StartTime
+	te.Status = test.ExecutionStatusRunning
 	t.db.testExecs[te.ID] = te
-	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionStarted, te))
-	return ptr.Copy(te), nil
+	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionStarted, decodeTestExec(t.db.codec, te)))
+	return decodeTestExec(t.db.codec, te), nil
 }
 
 func (t *TestExecutionWriter) UpdateFinishedTestExecution(_ context.Context, finished *test.FinishedTestExecution) (*test.TestExecution, error) {
@@ -145,24 +388,126 @@ func (t *TestExecutionWriter) UpdateFinishedTestExecution(_ context.Context, fin
 	if !ok {
 		return nil, test.ErrorTestExecutionNotFound
 	}
+	// Stopped test executions keep their final status
+	if te.Status.IsStopped() {
+		return decodeTestExec(t.db.codec, te), nil
+	}
+	failure, err := test.EncodeFailure(t.db.codec, finished.Failure)
+	if err != nil {
+		return nil, err
+	}
 	te.FinishTime = &finished.FinishTime
 	te.Error = finished.Error
+	te.Failure = failure
+	te.Status = finished.Status()
 	t.db.testExecs[te.ID] = te
-	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionFinished, te))
-	return ptr.Copy(te), nil
+	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionFinished, decodeTestExec(t.db.codec, te)))
+	return decodeTestExec(t.db.codec, te), nil
+}
+
+func (t *TestExecutionWriter) UpdateCancelledTestExecution(_ context.Context, cancelled *test.CancelledTestExecution) (*test.TestExecution, error) {
+	return t.stopTestExecution(cancelled.ID, cancelled.CancelTime, test.ExecutionStatusCancelled)
 }
 
-func (t *TestExecutionWriter) ResetTestExecution(_ context.Context, reset *test.ResetTestExecution) (*test.TestExecution, test.ResetRollback, error) {
+func (t *TestExecutionWriter) UpdateTimedOutTestExecution(_ context.Context, timedOut *test.TimedOutTestExecution) (*test.TestExecution, error) {
+	return t.stopTestExecution(timedOut.ID, timedOut.TimeoutTime, test.ExecutionStatusTimedOut)
+}
+
+func (t *TestExecutionWriter) stopTestExecution(id test.TestExecutionID, stopTime time.Time, status test.ExecutionStatus) (*test.TestExecution, error) {
 	t.db.mu.Lock()
 	defer t.db.mu.Unlock()
 
+	te, ok := t.db.testExecs[id]
+	if !ok {
+		return nil, test.ErrorTestExecutionNotFound
+	}
+	if te.Status.IsFinal() {
+		return nil, test.ErrorTestExecutionFinished
+	}
+
+	stopTestExecutionUnsafe(t.db, te, stopTime, status)
+	return decodeTestExec(t.db.codec, te), nil
+}
+
+// stopTestExecutionUnsafe finishes an open test execution, and its open case
+// executions, case execution attempts and sub-executions.
+func stopTestExecutionUnsafe(db *DB, te *test.TestExecution, stopTime time.Time, status test.ExecutionStatus) {
+	caseEventType := eventservice.TypeCaseExecutionFinished
+	testEventType := eventservice.TypeTestExecutionFinished
+	if status == test.ExecutionStatusCancelled {
+		caseEventType = eventservice.TypeCaseExecutionCancelled
+		testEventType = eventservice.TypeTestExecutionCancelled
+	}
+
+	for _, ce := range db.caseExecs {
+		if ce.TestExecutionID == te.ID && !ce.Status.IsFinal() {
+			ce.FinishTime = &stopTime
+			ce.Status = status
+			db.events.Publish(eventservice.NewCaseExecutionEvent(caseEventType, decodeCaseExec(db.codec, ce), te.Attempt))
+		}
+	}
+
+	for key, attempts := range db.caseExecAttempts {
+		if key.testExecID != te.ID {
+			continue
+		}
+		for _, a := range attempts {
+			if !a.Status.IsFinal() {
+				a.FinishTime = &stopTime
+				a.Status = status
+			}
+		}
+	}
+
+	for _, subExec := range listSubTestExecsUnsafe(db, te.ID) {
+		if !subExec.Status.IsFinal() {
+			stopTestExecutionUnsafe(db, subExec, stopTime, status)
+		}
+	}
+
+	te.FinishTime = &stopTime
+	te.Status = status
+	db.testExecs[te.ID] = te
+	db.events.Publish(eventservice.NewTestExecutionEvent(testEventType, decodeTestExec(db.codec, te)))
+}
+
+func (t *TestExecutionWriter) ResetTestExecution(ctx context.Context, reset *test.ResetTestExecution, resetWorkflow func(ctx context.Context) error) (*test.TestExecution, error) {
+	t.db.mu.Lock()
 	te, ok := t.db.testExecs[reset.ID]
 	if !ok {
-		return nil, nil, test.ErrorTestExecutionNotFound
+		t.db.mu.Unlock()
+		return nil, test.ErrorTestExecutionNotFound
+	}
+	// Archive the current attempt before its stale history is removed so that
+	// earlier attempts remain viewable after a retry.
+	orig := *te
+	archived := archiveAttemptUnsafe(t.db, te)
+	t.db.mu.Unlock()
+
+	// The lock is not held while the workflow is reset. Nothing is reset if the
+	// workflow fails to reset.
+	if err := resetWorkflow(ctx); err != nil {
+		return nil, err
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	te, ok = t.db.testExecs[reset.ID]
+	if !ok {
+		return nil, test.ErrorTestExecutionNotFound
 	}
+	if !reflect.DeepEqual(orig, *te) || !reflect.DeepEqual(archived, archiveAttemptUnsafe(t.db, te)) {
+		return nil, test.ErrorTestExecutionChanged
+	}
+
+	archived.attempt.ArchiveTime = time.Now().UTC()
+	t.db.attempts[te.ID] = append(t.db.attempts[te.ID], archived)
 
 	for _, caseExecID := range reset.StaleCaseExecutions {
 		delete(t.db.caseExecs, getCaseExecKey(te.ID, caseExecID))
+		delete(t.db.caseExecPayloads, getCaseExecKey(te.ID, caseExecID))
+		delete(t.db.caseExecAttempts, getCaseExecKey(te.ID, caseExecID))
 	}
 
 	for _, logID := range reset.StaleLogs {
@@ -173,13 +518,74 @@ func (t *TestExecutionWriter) ResetTestExecution(_ context.Context, reset *test.
 	te.StartTime = nil
 	te.FinishTime = nil
 	te.Error = nil
+	te.Failure = nil
+	te.Status = test.ExecutionStatusScheduled
+	te.Attempt++
 
 	t.db.testExecs[te.ID] = te
-	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionScheduled, te))
+	t.db.events.Publish(eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionRetried, decodeTestExec(t.db.codec, te)))
+	return decodeTestExec(t.db.codec, te), nil
+}
+
+// archiveAttemptUnsafe copies the current attempt of a test execution with its
+// history. The archive time is set when the attempt is archived.
+func archiveAttemptUnsafe(db *DB, te *test.TestExecution) *archivedAttempt {
+	caseExecs := listCaseExecsUnsafe(db, te.ID)
+	caseExecAttempts := map[test.CaseExecutionID]test.CaseExecutionAttemptList{}
+	caseExecPayloads := map[test.CaseExecutionID]*test.CaseExecutionPayloads{}
+	for _, ce := range caseExecs {
+		key := getCaseExecKey(te.ID, ce.ID)
+		if attempts, ok := db.caseExecAttempts[key]; ok {
+			copied := make(test.CaseExecutionAttemptList, len(attempts))
+			for i, a := range attempts {
+				copied[i] = ptr.Copy(a)
+			}
+			caseExecAttempts[ce.ID] = copied
+		}
+		if payloads, ok := db.caseExecPayloads[key]; ok {
+			caseExecPayloads[ce.ID] = &test.CaseExecutionPayloads{
+				Input:  slices.Clone(payloads.Input),
+				Result: payloads.Result,
+			}
+		}
+	}
+	return &archivedAttempt{
+		attempt: &test.TestExecutionAttempt{
+			TestExecutionID: te.ID,
+			Attempt:         te.Attempt,
+			ScheduleTime:    te.ScheduleTime,
+			StartTime:       te.StartTime,
+			FinishTime:      te.FinishTime,
+			Error:           te.Error,
+			Failure:         te.Failure,
+			Status:          te.Status,
+		},
+		caseExecs:        caseExecs,
+		caseExecAttempts: caseExecAttempts,
+		caseExecPayloads: caseExecPayloads,
+		logs:             listLogsUnsafe(db, te.ID),
+	}
+}
+
+// decodeTestExec copies a test execution with its failure decoded. A failure
+// that cannot be decoded is treated as missing rather than failing the read of
+// its execution.
+func decodeTestExec(codec test.PayloadCodec, te *test.TestExecution) *test.TestExecution {
+	decoded := ptr.Copy(te)
+	decoded.Failure = decodeFailure(codec, te.Failure)
+	return decoded
+}
+
+func decodeTestExecAttempt(codec test.PayloadCodec, a *test.TestExecutionAttempt) *test.TestExecutionAttempt {
+	decoded := ptr.Copy(a)
+	decoded.Failure = decodeFailure(codec, a.Failure)
+	return decoded
+}
 
-	rollback := func(ctx context.Context) error {
-		// no-op since in-mem is not intended for prod use anyway
+func decodeFailure(codec test.PayloadCodec, f *test.Failure) *test.Failure {
+	decoded, err := test.DecodeFailure(codec, f)
+	if err != nil {
 		return nil
 	}
-	return ptr.Copy(te), rollback, nil
+	return decoded
 }
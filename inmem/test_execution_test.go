@@ -2,13 +2,16 @@ package inmem
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
 
+	"github.com/annexsh/annex/codec"
 	"github.com/annexsh/annex/internal/fake"
 	"github.com/annexsh/annex/internal/ptr"
 	"github.com/annexsh/annex/test"
@@ -33,12 +36,12 @@ func TestTestExecutionReader_GetTestExecutionPayload(t *testing.T) {
 	r := NewTestExecutionReader(db)
 
 	testExecID := test.NewTestExecutionID()
-	want := []byte{1, 2, 3}
+	want := fake.GenInput()
 	db.testExecPayloads[testExecID] = want
 
 	got, err := r.GetTestExecutionInput(ctx, testExecID)
 	require.NoError(t, err)
-	assert.Equal(t, want, got.Data)
+	assert.Equal(t, want, got)
 }
 
 func TestTestExecutionReader_ListTestExecutions(t *testing.T) {
@@ -81,6 +84,188 @@ func TestTestExecutionReader_ListTestExecutions(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestTestExecutionReader_ListTestExecutions_filter(t *testing.T) {
+	now := time.Now()
+	testID := uuid.New()
+
+	passed := fake.GenTestExec(testID)
+	passed.ScheduleTime = now.Add(-3 * time.Hour)
+
+	failed := fake.GenTestExec(testID)
+	failed.ScheduleTime = now.Add(-2 * time.Hour)
+	failed.Error = ptr.Get("Connection Refused")
+	failed.Status = test.ExecutionStatusFailed
+
+	running := fake.GenTestExec(testID)
+	running.ScheduleTime = now.Add(-time.Hour)
+	running.FinishTime = nil
+	running.Status = test.ExecutionStatusRunning
+
+	otherTest := fake.GenTestExec(uuid.New())
+	otherTest.Status = test.ExecutionStatusFailed
+
+	tests := []struct {
+		name   string
+		filter *test.TestExecutionListFilter
+		want   test.TestExecutionList
+	}{
+		{
+			name:   "no filter",
+			filter: &test.TestExecutionListFilter{},
+			want:   test.TestExecutionList{passed, failed, running},
+		},
+		{
+			name: "statuses",
+			filter: &test.TestExecutionListFilter{
+				Statuses: []test.ExecutionStatus{test.ExecutionStatusFailed, test.ExecutionStatusRunning},
+			},
+			want: test.TestExecutionList{failed, running},
+		},
+		{
+			name: "scheduled after inclusive",
+			filter: &test.TestExecutionListFilter{
+				ScheduledAfter: &failed.ScheduleTime,
+			},
+			want: test.TestExecutionList{failed, running},
+		},
+		{
+			name: "scheduled before exclusive",
+			filter: &test.TestExecutionListFilter{
+				ScheduledBefore: &failed.ScheduleTime,
+			},
+			want: test.TestExecutionList{passed},
+		},
+		{
+			name: "error contains case insensitive",
+			filter: &test.TestExecutionListFilter{
+				ErrorContains: ptr.Get("refused"),
+			},
+			want: test.TestExecutionList{failed},
+		},
+		{
+			name: "no match",
+			filter: &test.TestExecutionListFilter{
+				Statuses:      []test.ExecutionStatus{test.ExecutionStatusPassed},
+				ErrorContains: ptr.Get("refused"),
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := NewDB()
+			r := NewTestExecutionReader(db)
+
+			for _, te := range []*test.TestExecution{passed, failed, running, otherTest} {
+				db.testExecs[te.ID] = te
+			}
+
+			got, err := r.ListTestExecutions(ctx, testID, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTestExecutionReader_ListRecentTestExecutions(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewTestExecutionReader(db)
+
+	contextID := "foo"
+	groupID := "bar"
+
+	tests := []*test.Test{
+		fake.GenTest(fake.WithContextID(contextID), fake.WithGroupID(groupID)),
+		fake.GenTest(fake.WithContextID(contextID), fake.WithGroupID("baz")),
+		fake.GenTest(fake.WithContextID("other"), fake.WithGroupID(groupID)),
+	}
+
+	var wantContext, wantGroup test.TestExecutionSummaryList
+
+	now := time.Now()
+	for i := range 30 {
+		tt := tests[i%len(tests)]
+		db.tests[tt.ID] = tt
+
+		te := fake.GenTestExec(tt.ID)
+		te.ScheduleTime = now.Add(-time.Duration(i) * time.Minute) // most recent first
+		db.testExecs[te.ID] = te
+
+		summary := &test.TestExecutionSummary{
+			ContextID: tt.ContextID,
+			GroupID:   tt.GroupID,
+			TestName:  tt.Name,
+			Execution: te,
+		}
+		if tt.ContextID == contextID {
+			wantContext = append(wantContext, summary)
+			if tt.GroupID == groupID {
+				wantGroup = append(wantGroup, summary)
+			}
+		}
+	}
+
+	listAll := func(groupID *string) test.TestExecutionSummaryList {
+		filter := &test.RecentTestExecutionListFilter{
+			GroupID:  groupID,
+			PageSize: 3,
+		}
+		var got test.TestExecutionSummaryList
+		for {
+			page, err := r.ListRecentTestExecutions(ctx, contextID, filter)
+			require.NoError(t, err)
+			got = append(got, page...)
+			if len(page) < int(filter.PageSize) {
+				return got
+			}
+			last := page[len(page)-1].Execution
+			filter.LastScheduleTime = &last.ScheduleTime
+			filter.LastTestExecutionID = &last.ID.UUID
+		}
+	}
+
+	assert.Equal(t, wantContext, listAll(nil))
+	assert.Equal(t, wantGroup, listAll(&groupID))
+}
+
+func TestTestExecutionReader_ListExpiredTestExecutions(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewTestExecutionReader(db)
+
+	now := time.Now()
+
+	genOpen := func(scheduleTime time.Time, timeout *time.Duration, runTimeout *time.Duration) *test.TestExecution {
+		te := fake.GenTestExec(uuid.New())
+		te.ScheduleTime = scheduleTime
+		te.FinishTime = nil
+		te.Status = test.ExecutionStatusRunning
+		te.ExecutionTimeout = timeout
+		te.RunTimeout = runTimeout
+		db.testExecs[te.ID] = te
+		return te
+	}
+
+	expiredOldest := genOpen(now.Add(-2*time.Hour), ptr.Get(time.Hour), nil)
+	expiredExact := genOpen(now.Add(-time.Hour), ptr.Get(time.Hour), nil)
+	expiredRun := genOpen(now.Add(-30*time.Minute), ptr.Get(time.Hour), ptr.Get(10*time.Minute))
+	genOpen(now.Add(-time.Minute), ptr.Get(time.Hour), nil)                        // not expired
+	genOpen(now.Add(-30*time.Minute), ptr.Get(time.Hour), ptr.Get(45*time.Minute)) // run timeout not expired
+	genOpen(now.Add(-2*time.Hour), nil, nil)                                       // no timeout recorded
+
+	finished := fake.GenTestExec(uuid.New())
+	finished.ScheduleTime = now.Add(-2 * time.Hour)
+	finished.ExecutionTimeout = ptr.Get(time.Hour)
+	db.testExecs[finished.ID] = finished
+
+	got, err := r.ListExpiredTestExecutions(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, test.TestExecutionList{expiredOldest, expiredExact, expiredRun}, got)
+}
+
 func TestTestExecutionWriter_CreateScheduledTestExecution(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -103,6 +288,7 @@ func TestTestExecutionWriter_CreateScheduledTestExecution(t *testing.T) {
 			ctx := context.Background()
 			db := NewDB()
 			w := NewTestExecutionWriter(db)
+			r := NewTestExecutionReader(db)
 
 			testExec := fake.GenTest()
 			if tt.existingTest {
@@ -119,25 +305,63 @@ func TestTestExecutionWriter_CreateScheduledTestExecution(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, sched.ID, got.ID)
 			assert.Equal(t, sched.TestID, got.TestID)
-			assert.Equal(t, sched.Payload != nil, got.HasInput)
+			assert.Equal(t, sched.Input != nil, got.HasInput)
 			assert.Equal(t, sched.ScheduleTime, got.ScheduleTime)
 			assert.Nil(t, got.StartTime)
 			assert.Nil(t, got.FinishTime)
 			assert.Nil(t, got.Error)
+			assert.Equal(t, test.ExecutionStatusScheduled, got.Status)
+			assert.Equal(t, &sched.ExecutionTimeout, got.ExecutionTimeout)
+			assert.Equal(t, sched.RunTimeout, got.RunTimeout)
+
+			input, err := r.GetTestExecutionInput(ctx, got.ID)
+			require.NoError(t, err)
+			assert.Equal(t, sched.Input, input)
 		})
 	}
 }
 
+func TestTestExecutionWriter_CreateScheduledTestExecution_payloadCodec(t *testing.T) {
+	ctx := context.Background()
+	aesgcm, err := codec.NewAESGCM("key", map[string][]byte{"key": make([]byte, 32)})
+	require.NoError(t, err)
+
+	db := NewDB(WithPayloadCodec(aesgcm))
+	w := NewTestExecutionWriter(db)
+	r := NewTestExecutionReader(db)
+
+	tt := fake.GenTest()
+	db.tests[tt.ID] = tt
+
+	sched := fake.GenScheduledTestExec(tt.ID)
+	got, err := w.CreateScheduledTestExecution(ctx, sched)
+	require.NoError(t, err)
+
+	stored := db.testExecPayloads[got.ID]
+	assert.Equal(t, codec.MetadataEncodingEncrypted, string(stored.Metadata[converter.MetadataEncoding]))
+	assert.NotEqual(t, sched.Input.Data, stored.Data)
+
+	input, err := r.GetTestExecutionInput(ctx, got.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sched.Input, input)
+}
+
 func TestTestExecutionWriter_UpdateStartedTestExecution(t *testing.T) {
 	tests := []struct {
 		name         string
 		existingTest bool
+		stopped      bool
 		wantErr      error
 	}{
 		{
 			name:         "update success",
 			existingTest: true,
 		},
+		{
+			name:         "stopped test execution unchanged",
+			existingTest: true,
+			stopped:      true,
+		},
 		{
 			name:         "test execution not found error",
 			existingTest: false,
@@ -155,6 +379,11 @@ func TestTestExecutionWriter_UpdateStartedTestExecution(t *testing.T) {
 			existing.StartTime = nil
 			existing.FinishTime = nil
 			existing.Error = nil
+			existing.Status = test.ExecutionStatusScheduled
+			if tt.stopped {
+				existing.FinishTime = ptr.Get(time.Now().UTC())
+				existing.Status = test.ExecutionStatusCancelled
+			}
 
 			if tt.existingTest {
 				db.testExecs[existing.ID] = existing
@@ -168,6 +397,10 @@ func TestTestExecutionWriter_UpdateStartedTestExecution(t *testing.T) {
 			}
 
 			require.NoError(t, err)
+			if tt.stopped {
+				assert.Equal(t, existing, got)
+				return
+			}
 			assert.Equal(t, started.ID, got.ID)
 			assert.Equal(t, existing.TestID, got.TestID)
 			assert.Equal(t, existing.HasInput, got.HasInput)
@@ -175,6 +408,7 @@ func TestTestExecutionWriter_UpdateStartedTestExecution(t *testing.T) {
 			assert.Equal(t, started.StartTime, *got.StartTime)
 			assert.Nil(t, got.FinishTime)
 			assert.Nil(t, got.Error)
+			assert.Equal(t, test.ExecutionStatusRunning, got.Status)
 		})
 	}
 }
@@ -205,6 +439,7 @@ func TestTestExecutionWriter_UpdateFinishedTestExecution(t *testing.T) {
 			existing := fake.GenTestExec(uuid.New())
 			existing.FinishTime = nil
 			existing.Error = nil
+			existing.Status = test.ExecutionStatusRunning
 
 			if tt.existingTest {
 				db.testExecs[existing.ID] = existing
@@ -224,6 +459,153 @@ func TestTestExecutionWriter_UpdateFinishedTestExecution(t *testing.T) {
 			assert.Equal(t, existing.ScheduleTime, got.ScheduleTime)
 			assert.Equal(t, finished.FinishTime, *got.FinishTime)
 			assert.Equal(t, finished.Error, got.Error)
+			assert.Equal(t, test.ExecutionStatusFailed, got.Status)
+		})
+	}
+}
+
+func TestTestExecutionWriter_UpdateCancelledTestExecution(t *testing.T) {
+	tests := []struct {
+		name             string
+		existingTestExec bool
+		finished         bool
+		wantErr          error
+	}{
+		{
+			name:             "cancel success",
+			existingTestExec: true,
+		},
+		{
+			name:             "test execution not found error",
+			existingTestExec: false,
+			wantErr:          test.ErrorTestExecutionNotFound,
+		},
+		{
+			name:             "test execution finished error",
+			existingTestExec: true,
+			finished:         true,
+			wantErr:          test.ErrorTestExecutionFinished,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := NewDB()
+			w := NewTestExecutionWriter(db)
+
+			existing := fake.GenTestExec(uuid.New())
+			if !tt.finished {
+				existing.FinishTime = nil
+				existing.Status = test.ExecutionStatusRunning
+			}
+
+			finishedCase := fake.GenCaseExec(existing.ID)
+			openCase := fake.GenCaseExec(existing.ID)
+			openCase.FinishTime = nil
+			openCase.Status = test.ExecutionStatusRunning
+
+			if tt.existingTestExec {
+				db.testExecs[existing.ID] = existing
+				db.caseExecs[getCaseExecKey(existing.ID, finishedCase.ID)] = finishedCase
+				db.caseExecs[getCaseExecKey(existing.ID, openCase.ID)] = openCase
+			}
+
+			cancelled := &test.CancelledTestExecution{
+				ID:         existing.ID,
+				CancelTime: time.Now(),
+			}
+			got, err := w.UpdateCancelledTestExecution(ctx, cancelled)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, existing.ID, got.ID)
+			assert.Equal(t, test.ExecutionStatusCancelled, got.Status)
+			assert.Equal(t, cancelled.CancelTime, *got.FinishTime)
+			assert.Nil(t, got.Error)
+
+			gotOpenCase := db.caseExecs[getCaseExecKey(existing.ID, openCase.ID)]
+			assert.Equal(t, test.ExecutionStatusCancelled, gotOpenCase.Status)
+			assert.Equal(t, cancelled.CancelTime, *gotOpenCase.FinishTime)
+
+			gotFinishedCase := db.caseExecs[getCaseExecKey(existing.ID, finishedCase.ID)]
+			assert.Equal(t, test.ExecutionStatusPassed, gotFinishedCase.Status)
+		})
+	}
+}
+
+func TestTestExecutionWriter_UpdateTimedOutTestExecution(t *testing.T) {
+	tests := []struct {
+		name             string
+		existingTestExec bool
+		finished         bool
+		wantErr          error
+	}{
+		{
+			name:             "timeout success",
+			existingTestExec: true,
+		},
+		{
+			name:             "test execution not found error",
+			existingTestExec: false,
+			wantErr:          test.ErrorTestExecutionNotFound,
+		},
+		{
+			name:             "test execution finished error",
+			existingTestExec: true,
+			finished:         true,
+			wantErr:          test.ErrorTestExecutionFinished,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := NewDB()
+			w := NewTestExecutionWriter(db)
+
+			existing := fake.GenTestExec(uuid.New())
+			if !tt.finished {
+				existing.FinishTime = nil
+				existing.Status = test.ExecutionStatusRunning
+			}
+
+			finishedCase := fake.GenCaseExec(existing.ID)
+			openCase := fake.GenCaseExec(existing.ID)
+			openCase.FinishTime = nil
+			openCase.Status = test.ExecutionStatusRunning
+
+			if tt.existingTestExec {
+				db.testExecs[existing.ID] = existing
+				db.caseExecs[getCaseExecKey(existing.ID, finishedCase.ID)] = finishedCase
+				db.caseExecs[getCaseExecKey(existing.ID, openCase.ID)] = openCase
+			}
+
+			timedOut := &test.TimedOutTestExecution{
+				ID:          existing.ID,
+				TimeoutTime: time.Now(),
+			}
+			got, err := w.UpdateTimedOutTestExecution(ctx, timedOut)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, existing.ID, got.ID)
+			assert.Equal(t, test.ExecutionStatusTimedOut, got.Status)
+			assert.Equal(t, timedOut.TimeoutTime, *got.FinishTime)
+			assert.Nil(t, got.Error)
+
+			gotOpenCase := db.caseExecs[getCaseExecKey(existing.ID, openCase.ID)]
+			assert.Equal(t, test.ExecutionStatusTimedOut, gotOpenCase.Status)
+			assert.Equal(t, timedOut.TimeoutTime, *gotOpenCase.FinishTime)
+
+			gotFinishedCase := db.caseExecs[getCaseExecKey(existing.ID, finishedCase.ID)]
+			assert.Equal(t, test.ExecutionStatusPassed, gotFinishedCase.Status)
 		})
 	}
 }
@@ -316,13 +698,21 @@ func TestTestExecutionWriter_ResetTestExecution(t *testing.T) {
 				StaleLogs:           staleLogIDs,
 			}
 
-			got, rollback, err := w.ResetTestExecution(ctx, reset)
+			prev := ptr.Copy(existing)
+			numCaseExecs := len(db.caseExecs)
+			numLogs := len(db.execLogs)
+
+			var workflowReset bool
+			got, err := w.ResetTestExecution(ctx, reset, func(context.Context) error {
+				workflowReset = true
+				return nil
+			})
 			if tt.wantErr != nil {
 				require.ErrorIs(t, err, tt.wantErr)
 				return
 			}
 			require.NoError(t, err)
-			assert.NotNil(t, rollback)
+			assert.True(t, workflowReset)
 			assert.Equal(t, reset.ID, got.ID)
 			assert.Equal(t, existing.TestID, got.TestID)
 			assert.Equal(t, existing.HasInput, got.HasInput)
@@ -330,6 +720,18 @@ func TestTestExecutionWriter_ResetTestExecution(t *testing.T) {
 			assert.Nil(t, got.StartTime)
 			assert.Nil(t, got.FinishTime)
 			assert.Nil(t, got.Error)
+			assert.Equal(t, test.ExecutionStatusScheduled, got.Status)
+			assert.Equal(t, prev.Attempt+1, got.Attempt)
+
+			require.Len(t, db.attempts[existing.ID], 1)
+			archived := db.attempts[existing.ID][0]
+			assert.Equal(t, prev.Attempt, archived.attempt.Attempt)
+			assert.Equal(t, prev.ScheduleTime, archived.attempt.ScheduleTime)
+			assert.Equal(t, prev.StartTime, archived.attempt.StartTime)
+			assert.Equal(t, prev.FinishTime, archived.attempt.FinishTime)
+			assert.Equal(t, prev.Status, archived.attempt.Status)
+			assert.Len(t, archived.caseExecs, numCaseExecs)
+			assert.Len(t, archived.logs, numLogs)
 
 			assert.Len(t, db.caseExecs, numValidCaseExecs)
 			for _, staleID := range staleCaseExecIDs {
@@ -344,3 +746,146 @@ func TestTestExecutionWriter_ResetTestExecution(t *testing.T) {
 		})
 	}
 }
+
+func TestTestExecutionWriter_ResetTestExecution_workflowResetFailed(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewTestExecutionWriter(db)
+
+	existing := fake.GenTestExec(uuid.New())
+	db.testExecs[existing.ID] = existing
+	caseExec := fake.GenCaseExec(existing.ID)
+	db.caseExecs[getCaseExecKey(existing.ID, caseExec.ID)] = caseExec
+	prev := ptr.Copy(existing)
+
+	resetErr := errors.New("bang")
+	_, err := w.ResetTestExecution(ctx, &test.ResetTestExecution{
+		ID:                  existing.ID,
+		ResetTime:           time.Now(),
+		StaleCaseExecutions: []test.CaseExecutionID{caseExec.ID},
+	}, func(context.Context) error {
+		return resetErr
+	})
+	require.ErrorIs(t, err, resetErr)
+
+	assert.Equal(t, prev, db.testExecs[existing.ID])
+	assert.Empty(t, db.attempts[existing.ID])
+	assert.Contains(t, db.caseExecs, getCaseExecKey(existing.ID, caseExec.ID))
+}
+
+func TestTestExecutionWriter_ResetTestExecution_changedDuringReset(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewTestExecutionReader(db)
+	w := NewTestExecutionWriter(db)
+	lw := NewLogWriter(db)
+
+	existing := fake.GenTestExec(uuid.New())
+	db.testExecs[existing.ID] = existing
+	prev := ptr.Copy(existing)
+
+	// The workflow is reset without holding the lock
+	_, err := w.ResetTestExecution(ctx, &test.ResetTestExecution{
+		ID:        existing.ID,
+		ResetTime: time.Now(),
+	}, func(ctx context.Context) error {
+		if _, err := r.GetTestExecution(ctx, existing.ID); err != nil {
+			return err
+		}
+		return lw.CreateLog(ctx, fake.GenTestExecLog(existing.ID))
+	})
+	require.ErrorIs(t, err, test.ErrorTestExecutionChanged)
+
+	assert.Equal(t, prev, db.testExecs[existing.ID])
+	assert.Empty(t, db.attempts[existing.ID])
+}
+
+func TestTestExecutionWriter_CreateScheduledSubTestExecution(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewTestExecutionReader(db)
+	w := NewTestExecutionWriter(db)
+
+	parent := fake.GenTestExec(uuid.New())
+	parent.TestVersion = ptr.Get(int32(2))
+	db.testExecs[parent.ID] = parent
+
+	scheduled := &test.ScheduledSubTestExecution{
+		ID:                test.NewSubTestExecutionID(parent.ID, "run", "child"),
+		ParentID:          test.NewTestExecutionID(),
+		ChildWorkflowID:   "child",
+		ChildWorkflowType: "ChildWorkflow",
+		ScheduleTime:      time.Now().UTC(),
+	}
+	_, err := w.CreateScheduledSubTestExecution(ctx, scheduled)
+	require.ErrorIs(t, err, test.ErrorTestExecutionNotFound)
+
+	scheduled.ParentID = parent.ID
+	got, err := w.CreateScheduledSubTestExecution(ctx, scheduled)
+	require.NoError(t, err)
+	assert.Equal(t, scheduled.ID, got.ID)
+	assert.Equal(t, parent.TestID, got.TestID)
+	assert.Equal(t, parent.TestVersion, got.TestVersion)
+	assert.Equal(t, parent.ID, *got.ParentID)
+	assert.Equal(t, scheduled.ChildWorkflowID, got.ChildWorkflowID)
+	assert.Equal(t, scheduled.ChildWorkflowType, got.ChildWorkflowType)
+	assert.Equal(t, test.ExecutionStatusScheduled, got.Status)
+
+	// Acknowledging the child workflow again leaves the sub-execution unchanged
+	_, err = w.UpdateStartedTestExecution(ctx, &test.StartedTestExecution{ID: got.ID, StartTime: time.Now().UTC()})
+	require.NoError(t, err)
+	again, err := w.CreateScheduledSubTestExecution(ctx, scheduled)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusRunning, again.Status)
+
+	sub, err := r.GetSubTestExecution(ctx, scheduled.ChildWorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, scheduled.ID, sub.ID)
+
+	_, err = r.GetSubTestExecution(ctx, "unknown")
+	require.ErrorIs(t, err, test.ErrorTestExecutionNotFound)
+
+	subs, err := r.ListSubTestExecutions(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, scheduled.ID, subs[0].ID)
+
+	// Sub-executions are not listed as executions of their test
+	execs, err := r.ListTestExecutions(ctx, parent.TestID, &test.TestExecutionListFilter{})
+	require.NoError(t, err)
+	require.Len(t, execs, 1)
+	assert.Equal(t, parent.ID, execs[0].ID)
+}
+
+func TestTestExecutionWriter_UpdateCancelledTestExecution_subExecutions(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewTestExecutionWriter(db)
+
+	parent := fake.GenTestExec(uuid.New())
+	parent.FinishTime = nil
+	parent.Status = test.ExecutionStatusRunning
+	db.testExecs[parent.ID] = parent
+
+	sub, err := w.CreateScheduledSubTestExecution(ctx, &test.ScheduledSubTestExecution{
+		ID:           test.NewSubTestExecutionID(parent.ID, "run", "child"),
+		ParentID:     parent.ID,
+		ScheduleTime: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+	nested, err := w.CreateScheduledSubTestExecution(ctx, &test.ScheduledSubTestExecution{
+		ID:           test.NewSubTestExecutionID(sub.ID, "run", "grandchild"),
+		ParentID:     sub.ID,
+		ScheduleTime: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	_, err = w.UpdateCancelledTestExecution(ctx, &test.CancelledTestExecution{
+		ID:         parent.ID,
+		CancelTime: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, test.ExecutionStatusCancelled, db.testExecs[sub.ID].Status)
+	assert.Equal(t, test.ExecutionStatusCancelled, db.testExecs[nested.ID].Status)
+}
@@ -0,0 +1,105 @@
+package inmem
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/annexsh/annex/internal/ptr"
+	"github.com/annexsh/annex/test"
+)
+
+var (
+	_ test.TestRunReader = (*TestRunReader)(nil)
+	_ test.TestRunWriter = (*TestRunWriter)(nil)
+)
+
+type TestRunReader struct {
+	db *DB
+}
+
+func NewTestRunReader(db *DB) *TestRunReader {
+	return &TestRunReader{db: db}
+}
+
+func (t *TestRunReader) GetTestRun(_ context.Context, id uuid.UUID) (*test.TestRun, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	run, ok := t.db.testRuns[id]
+	if !ok {
+		return nil, test.ErrorTestRunNotFound
+	}
+
+	out := ptr.Copy(run)
+	out.Aggregate(listTestRunExecsUnsafe(t.db, id))
+	return out, nil
+}
+
+func (t *TestRunReader) ListTestRunExecutions(_ context.Context, id uuid.UUID) (test.TestExecutionList, error) {
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	if _, ok := t.db.testRuns[id]; !ok {
+		return nil, test.ErrorTestRunNotFound
+	}
+	return listTestRunExecsUnsafe(t.db, id), nil
+}
+
+func listTestRunExecsUnsafe(db *DB, id uuid.UUID) test.TestExecutionList {
+	var execs test.TestExecutionList
+	for _, te := range db.testExecs {
+		if te.TestRunID != nil && *te.TestRunID == id {
+			execs = append(execs, decodeTestExec(db.codec, te))
+		}
+	}
+	slices.SortFunc(execs, func(a, b *test.TestExecution) int {
+		return compareScheduled(a.ScheduleTime, a.ID.UUID, b.ScheduleTime, b.ID.UUID)
+	})
+	return execs
+}
+
+type TestRunWriter struct {
+	db *DB
+}
+
+func NewTestRunWriter(db *DB) *TestRunWriter {
+	return &TestRunWriter{db: db}
+}
+
+func (t *TestRunWriter) CreateTestRun(_ context.Context, definition *test.TestRunDefinition) (*test.TestRun, error) {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	// Nothing is created unless all test executions can be created
+	inputs := make([]*test.Payload, len(definition.Executions))
+	for i, scheduled := range definition.Executions {
+		if _, ok := t.db.tests[scheduled.TestID]; !ok {
+			return nil, test.ErrorTestNotFound
+		}
+		var err error
+		if inputs[i], err = test.EncodePayload(t.db.codec, scheduled.Input); err != nil {
+			return nil, err
+		}
+	}
+
+	run := &test.TestRun{
+		ID:         definition.ID,
+		ContextID:  definition.ContextID,
+		GroupID:    definition.GroupID,
+		CreateTime: time.Now().UTC(),
+	}
+	t.db.testRuns[run.ID] = run
+
+	for i, scheduled := range definition.Executions {
+		scheduled := *scheduled
+		scheduled.TestRunID = &run.ID
+		createTestExecUnsafe(t.db, &scheduled, inputs[i])
+	}
+
+	out := ptr.Copy(run)
+	out.Aggregate(listTestRunExecsUnsafe(t.db, run.ID))
+	return out, nil
+}
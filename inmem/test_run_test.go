@@ -0,0 +1,174 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/internal/ptr"
+	"github.com/annexsh/annex/test"
+)
+
+func TestTestRunReader_GetTestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []test.ExecutionStatus
+		wantStatus test.ExecutionStatus
+		wantCounts test.TestRunCounts
+	}{
+		{
+			name:       "scheduled",
+			statuses:   []test.ExecutionStatus{test.ExecutionStatusScheduled, test.ExecutionStatusScheduled},
+			wantStatus: test.ExecutionStatusScheduled,
+			wantCounts: test.TestRunCounts{Total: 2, Scheduled: 2},
+		},
+		{
+			name:       "running",
+			statuses:   []test.ExecutionStatus{test.ExecutionStatusScheduled, test.ExecutionStatusPassed},
+			wantStatus: test.ExecutionStatusRunning,
+			wantCounts: test.TestRunCounts{Total: 2, Scheduled: 1, Passed: 1},
+		},
+		{
+			name:       "passed",
+			statuses:   []test.ExecutionStatus{test.ExecutionStatusPassed, test.ExecutionStatusPassed},
+			wantStatus: test.ExecutionStatusPassed,
+			wantCounts: test.TestRunCounts{Total: 2, Passed: 2},
+		},
+		{
+			name:       "failed",
+			statuses:   []test.ExecutionStatus{test.ExecutionStatusPassed, test.ExecutionStatusTimedOut, test.ExecutionStatusCancelled},
+			wantStatus: test.ExecutionStatusFailed,
+			wantCounts: test.TestRunCounts{Total: 3, Passed: 1, TimedOut: 1, Cancelled: 1},
+		},
+		{
+			name:       "cancelled",
+			statuses:   []test.ExecutionStatus{test.ExecutionStatusPassed, test.ExecutionStatusCancelled},
+			wantStatus: test.ExecutionStatusCancelled,
+			wantCounts: test.TestRunCounts{Total: 2, Passed: 1, Cancelled: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := NewDB()
+			r := NewTestRunReader(db)
+
+			run := &test.TestRun{
+				ID:         uuid.New(),
+				ContextID:  "default-context",
+				CreateTime: time.Now(),
+			}
+			db.testRuns[run.ID] = run
+
+			start := time.Now()
+			var wantStart, wantFinish time.Time
+
+			for i, status := range tt.statuses {
+				te := fake.GenTestExec(uuid.New())
+				te.TestRunID = &run.ID
+				te.Status = status
+				te.StartTime = ptr.Get(start.Add(time.Duration(i) * time.Second))
+				te.FinishTime = ptr.Get(start.Add(time.Duration(i+1) * time.Second))
+				if !status.IsFinal() {
+					te.StartTime = nil
+					te.FinishTime = nil
+				}
+				if te.StartTime != nil && (wantStart.IsZero() || te.StartTime.Before(wantStart)) {
+					wantStart = *te.StartTime
+				}
+				if te.FinishTime != nil {
+					wantFinish = *te.FinishTime
+				}
+				db.testExecs[te.ID] = te
+			}
+
+			other := fake.GenTestExec(uuid.New())
+			db.testExecs[other.ID] = other
+
+			got, err := r.GetTestRun(ctx, run.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantCounts, got.Counts)
+
+			if wantStart.IsZero() {
+				assert.Nil(t, got.StartTime)
+			} else {
+				assert.Equal(t, wantStart, *got.StartTime)
+			}
+
+			if tt.wantStatus.IsFinal() {
+				assert.Equal(t, wantFinish, *got.FinishTime)
+				assert.Equal(t, wantFinish.Sub(wantStart), got.Duration())
+			} else {
+				assert.Nil(t, got.FinishTime)
+			}
+
+			execs, err := r.ListTestRunExecutions(ctx, run.ID)
+			require.NoError(t, err)
+			assert.Len(t, execs, len(tt.statuses))
+		})
+	}
+}
+
+func TestTestRunReader_GetTestRun_notFound(t *testing.T) {
+	r := NewTestRunReader(NewDB())
+	_, err := r.GetTestRun(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, test.ErrorTestRunNotFound)
+}
+
+func TestTestRunWriter_CreateTestRun(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewTestRunWriter(db)
+
+	def := &test.TestRunDefinition{
+		ID:        uuid.New(),
+		ContextID: "foo",
+		GroupID:   ptr.Get("bar"),
+	}
+
+	got, err := w.CreateTestRun(ctx, def)
+	require.NoError(t, err)
+	assert.Equal(t, def.ID, got.ID)
+	assert.Equal(t, def.ContextID, got.ContextID)
+	assert.Equal(t, def.GroupID, got.GroupID)
+	assert.Equal(t, test.ExecutionStatusScheduled, got.Status)
+	assert.Zero(t, got.Counts.Total)
+	assert.Contains(t, db.testRuns, def.ID)
+}
+
+func TestTestRunWriter_CreateTestRun_executions(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewTestRunWriter(db)
+
+	testID := uuid.New()
+	db.tests[testID] = fake.GenTest()
+
+	def := &test.TestRunDefinition{
+		ID:         uuid.New(),
+		ContextID:  "foo",
+		Executions: []*test.ScheduledTestExecution{fake.GenScheduledTestExec(testID), fake.GenScheduledTestExec(uuid.New())},
+	}
+
+	// Nothing is created when a test execution cannot be created
+	_, err := w.CreateTestRun(ctx, def)
+	require.ErrorIs(t, err, test.ErrorTestNotFound)
+	assert.Empty(t, db.testRuns)
+	assert.Empty(t, db.testExecs)
+
+	def.Executions = def.Executions[:1]
+	got, err := w.CreateTestRun(ctx, def)
+	require.NoError(t, err)
+	assert.Equal(t, test.TestRunCounts{Total: 1, Scheduled: 1}, got.Counts)
+
+	te, ok := db.testExecs[def.Executions[0].ID]
+	require.True(t, ok)
+	assert.Equal(t, &def.ID, te.TestRunID)
+}
@@ -3,12 +3,14 @@ package inmem
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/internal/ptr"
 	"github.com/annexsh/annex/test"
 )
 
@@ -56,10 +58,153 @@ func TestTestReader_ListTests(t *testing.T) {
 		db.tests[tt.ID] = tt
 	}
 
-	got, err := r.ListTests(ctx, contextID, groupID)
+	deprecated := fake.GenTest(fake.WithContextID(contextID), fake.WithGroupID(groupID))
+	deprecated.Deprecated = true
+	db.tests[deprecated.ID] = deprecated
+
+	got, err := r.ListTests(ctx, contextID, groupID, &test.TestListFilter{})
 	require.NoError(t, err)
 	assert.Len(t, got, count)
 	require.Equal(t, want, got)
+
+	got, err = r.ListTests(ctx, contextID, groupID, &test.TestListFilter{IncludeDeprecated: true})
+	require.NoError(t, err)
+	assert.Len(t, got, count+1)
+	assert.Contains(t, got, deprecated)
+}
+
+func TestTestReader_ListTests_metadata(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewTestReader(db)
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+
+	genTest := func(owner string, tags ...string) *test.Test {
+		tt := fake.GenTest(fake.WithContextID(contextID), fake.WithGroupID(groupID))
+		tt.Metadata = test.TestMetadata{Owner: owner, Tags: tags}
+		db.tests[tt.ID] = tt
+		return tt
+	}
+
+	payments := genTest("payments", "smoke", "api")
+	paymentsUI := genTest("payments", "ui")
+	search := genTest("search", "smoke")
+
+	tests := []struct {
+		name   string
+		filter *test.TestListFilter
+		want   test.TestList
+	}{
+		{
+			name:   "no filter",
+			filter: &test.TestListFilter{},
+			want:   test.TestList{payments, paymentsUI, search},
+		},
+		{
+			name:   "owner",
+			filter: &test.TestListFilter{Owner: "payments"},
+			want:   test.TestList{payments, paymentsUI},
+		},
+		{
+			name:   "tag",
+			filter: &test.TestListFilter{Tags: []string{"smoke"}},
+			want:   test.TestList{payments, search},
+		},
+		{
+			name:   "all tags",
+			filter: &test.TestListFilter{Tags: []string{"smoke", "api"}},
+			want:   test.TestList{payments},
+		},
+		{
+			name:   "owner and tag",
+			filter: &test.TestListFilter{Owner: "search", Tags: []string{"ui"}},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.ListTests(ctx, contextID, groupID, tt.filter)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestTestReader_SearchTests(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewTestReader(db)
+
+	now := time.Now()
+
+	genTest := func(name string, owner string, tags ...string) *test.Test {
+		tt := fake.GenTest()
+		tt.ContextID = uuid.NewString()
+		tt.GroupID = uuid.NewString()
+		tt.Name = name
+		tt.Metadata = test.TestMetadata{Owner: owner, Tags: tags}
+		tt.CreateTime = now.Add(time.Duration(len(db.tests)) * time.Second)
+		db.tests[tt.ID] = tt
+		return tt
+	}
+
+	checkout := genTest("TestCheckout", "payments", "smoke")
+	refund := genTest("TestRefund", "payments")
+	checkoutUI := genTest("TestCheckoutUI", "web", "smoke")
+	deprecated := genTest("TestCheckout_old", "payments")
+	deprecated.Deprecated = true
+	archivedGroup := genTest("TestCheckoutArchivedGroup", "payments")
+	db.archivedGroups.Add(getGroupKey(archivedGroup.ContextID, archivedGroup.GroupID))
+	archivedContext := genTest("TestCheckoutArchivedContext", "payments")
+	db.archivedContexts.Add(archivedContext.ContextID)
+
+	tests := []struct {
+		name   string
+		filter *test.TestSearchFilter
+		want   test.TestList
+	}{
+		{
+			name:   "no filter",
+			filter: &test.TestSearchFilter{},
+			want:   test.TestList{checkoutUI, refund, checkout},
+		},
+		{
+			name:   "name contains",
+			filter: &test.TestSearchFilter{NameContains: ptr.Get("checkout")},
+			want:   test.TestList{checkoutUI, checkout},
+		},
+		{
+			name:   "include deprecated",
+			filter: &test.TestSearchFilter{NameContains: ptr.Get("checkout"), IncludeDeprecated: true},
+			want:   test.TestList{deprecated, checkoutUI, checkout},
+		},
+		{
+			name:   "tag and owner",
+			filter: &test.TestSearchFilter{Tags: []string{"smoke"}, Owner: "payments"},
+			want:   test.TestList{checkout},
+		},
+		{
+			name:   "page size",
+			filter: &test.TestSearchFilter{PageSize: 2},
+			want:   test.TestList{checkoutUI, refund},
+		},
+		{
+			name:   "next page",
+			filter: &test.TestSearchFilter{LastCreateTime: &refund.CreateTime, LastTestID: &refund.ID},
+			want:   test.TestList{checkout},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.SearchTests(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
 }
 
 func TestTestWriter_CreateTest(t *testing.T) {
@@ -92,9 +237,145 @@ func TestTestWriter_CreateTests(t *testing.T) {
 	}
 }
 
+func TestTestWriter_CreateTest_versions(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	r := NewTestReader(db)
+	w := NewTestWriter(db)
+
+	def := fake.GenTestDefinition()
+	created, err := w.CreateTest(ctx, def)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), created.Version)
+
+	createTime := created.CreateTime
+
+	// Registering the same definition again does not record a version.
+	created, err = w.CreateTest(ctx, def)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), created.Version)
+	assert.Equal(t, createTime, created.CreateTime)
+
+	changed := *def
+	changed.DefaultInput = fake.GenInput()
+	changed.InputSchema = []byte(`{"type": "object"}`)
+	created, err = w.CreateTest(ctx, &changed)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), created.Version)
+	assert.Equal(t, createTime, created.CreateTime)
+
+	// Registering again without a schema keeps the schema and the version.
+	unset := changed
+	unset.InputSchema = nil
+	created, err = w.CreateTest(ctx, &unset)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), created.Version)
+	assert.Equal(t, changed.InputSchema, created.InputSchema)
+
+	// Registering again without metadata keeps the metadata and the version.
+	withMetadata := unset
+	withMetadata.Metadata = &test.TestMetadata{Owner: "payments-team", Tags: []string{"smoke"}}
+	_, err = w.CreateTest(ctx, &withMetadata)
+	require.NoError(t, err)
+	created, err = w.CreateTest(ctx, &unset)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), created.Version)
+	assert.Equal(t, *withMetadata.Metadata, created.Metadata)
+
+	versions, err := r.ListTestVersions(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, def.DefaultInput, versions[0].DefaultInput)
+	assert.Nil(t, versions[0].InputSchema)
+	assert.Equal(t, changed.DefaultInput, versions[1].DefaultInput)
+	assert.Equal(t, changed.InputSchema, versions[1].InputSchema)
+
+	got, err := r.GetTestVersion(ctx, created.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, versions[0], got)
+
+	_, err = r.GetTestVersion(ctx, created.ID, 3)
+	require.ErrorIs(t, err, test.ErrorTestVersionNotFound)
+}
+
+func TestTestWriter_MoveTest(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewTestRepository(db)
+
+	contextID := uuid.NewString()
+
+	def := fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID("a"))
+	tt, err := repo.CreateTest(ctx, def)
+	require.NoError(t, err)
+
+	conflict := fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID("b"))
+	conflict.Name = def.Name
+	_, err = repo.CreateTest(ctx, conflict)
+	require.NoError(t, err)
+
+	_, err = repo.MoveTest(ctx, tt.ID, "b")
+	require.ErrorIs(t, err, test.ErrorTestAlreadyExists)
+
+	te, err := repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+
+	_, err = repo.MoveTest(ctx, tt.ID, "c")
+	require.ErrorIs(t, err, test.ErrorExecutionsActive)
+
+	_, err = repo.UpdateCancelledTestExecution(ctx, &test.CancelledTestExecution{ID: te.ID, CancelTime: time.Now()})
+	require.NoError(t, err)
+
+	moved, err := repo.MoveTest(ctx, tt.ID, "c")
+	require.NoError(t, err)
+	assert.Equal(t, tt.ID, moved.ID)
+	assert.Equal(t, "c", moved.GroupID)
+
+	got, err := repo.ListTests(ctx, contextID, "c", &test.TestListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, test.TestList{moved}, got)
+
+	_, err = repo.MoveTest(ctx, uuid.New(), "c")
+	require.ErrorIs(t, err, test.ErrorTestNotFound)
+}
+
+func TestTestWriter_DeprecateTests(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	w := NewTestWriter(db)
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+
+	kept := fake.GenTest(fake.WithContextID(contextID), fake.WithGroupID(groupID))
+	removed := fake.GenTest(fake.WithContextID(contextID), fake.WithGroupID(groupID))
+	otherGroup := fake.GenTest(fake.WithContextID(contextID))
+	for _, tt := range []*test.Test{kept, removed, otherGroup} {
+		db.tests[tt.ID] = tt
+	}
+
+	got, err := w.DeprecateTests(ctx, contextID, groupID, []string{kept.Name})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, removed.ID, got[0].ID)
+	assert.True(t, got[0].Deprecated)
+
+	assert.False(t, db.tests[kept.ID].Deprecated)
+	assert.True(t, db.tests[removed.ID].Deprecated)
+	assert.False(t, db.tests[otherGroup.ID].Deprecated)
+
+	// Tests already deprecated are not deprecated again.
+	got, err = w.DeprecateTests(ctx, contextID, groupID, []string{kept.Name})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
 func assertCreatedTest(t *testing.T, def *test.TestDefinition, got *test.Test) {
 	assert.Equal(t, def.TestID, got.ID)
 	assert.Equal(t, def.Name, got.Name)
 	assert.Equal(t, def.GroupID, got.GroupID)
 	assert.Equal(t, def.DefaultInput != nil, got.HasInput)
+	if def.Metadata != nil {
+		assert.Equal(t, *def.Metadata, got.Metadata)
+	}
 }
@@ -44,7 +44,8 @@ func GenDefaultInput() *test.Payload {
 		panic(err)
 	}
 	return &test.Payload{
-		Data: p.Data,
+		Metadata: p.Metadata,
+		Data:     p.Data,
 	}
 }
 
@@ -73,10 +74,11 @@ func GenTest(opts ...TestOptions) *test.Test {
 
 func GenScheduledTestExec(testID uuid.UUID) *test.ScheduledTestExecution {
 	return &test.ScheduledTestExecution{
-		ID:           test.NewTestExecutionID(),
-		TestID:       testID,
-		Payload:      GenInput().Data,
-		ScheduleTime: time.Now(),
+		ID:               test.NewTestExecutionID(),
+		TestID:           testID,
+		Input:            GenInput(),
+		ScheduleTime:     time.Now(),
+		ExecutionTimeout: time.Hour,
 	}
 }
 
@@ -104,6 +106,8 @@ func GenTestExec(testID uuid.UUID) *test.TestExecution {
 		StartTime:    ptr.Get(time.Now().Add(-time.Millisecond)),
 		FinishTime:   ptr.Get(time.Now()),
 		Error:        nil,
+		Status:       test.ExecutionStatusPassed,
+		Attempt:      1,
 	}
 }
 
@@ -149,6 +153,28 @@ func GenCaseExec(testExecID test.TestExecutionID) *test.CaseExecution {
 		StartTime:       ptr.Get(time.Now().Add(-time.Millisecond)),
 		FinishTime:      ptr.Get(time.Now()),
 		Error:           nil,
+		Status:          test.ExecutionStatusPassed,
+	}
+}
+
+func GenScheduleDefinition(testID uuid.UUID) *test.ScheduleDefinition {
+	return &test.ScheduleDefinition{
+		ID:           uuid.New(),
+		TestID:       testID,
+		Cron:         "@hourly",
+		Input:        GenInput(),
+		NextFireTime: time.Now().Add(time.Hour).Truncate(time.Hour),
+	}
+}
+
+func GenSchedule(testID uuid.UUID) *test.Schedule {
+	return &test.Schedule{
+		ID:           uuid.New(),
+		TestID:       testID,
+		Cron:         "@hourly",
+		HasInput:     false,
+		CreateTime:   time.Now(),
+		NextFireTime: time.Now().Add(time.Hour).Truncate(time.Hour),
 	}
 }
 
@@ -62,6 +62,7 @@ func parseTime(timeStr string) *timestamppb.Timestamp {
 // - Test execution finish - error (case 2)
 func GenCaseFailureHistory(
 	testExecID test.TestExecutionID,
+	taskQueue string,
 	testExecLogID uuid.UUID, // log published by event 7 local activity
 	successCaseExecID test.CaseExecutionID,
 	failureCaseExecID test.CaseExecutionID,
@@ -84,7 +85,7 @@ func GenCaseFailureHistory(
 				Attributes: &history.HistoryEvent_WorkflowExecutionStartedEventAttributes{
 					WorkflowExecutionStartedEventAttributes: &history.WorkflowExecutionStartedEventAttributes{
 						WorkflowType:                    &common.WorkflowType{Name: "FakeTest"},
-						TaskQueue:                       &taskqueue.TaskQueue{Name: "default", Kind: enums.TASK_QUEUE_KIND_NORMAL},
+						TaskQueue:                       &taskqueue.TaskQueue{Name: taskQueue, Kind: enums.TASK_QUEUE_KIND_NORMAL},
 						WorkflowExecutionTimeout:        durationpb.New(1000 * time.Second),
 						WorkflowRunTimeout:              durationpb.New(1000 * time.Second),
 						WorkflowTaskTimeout:             durationpb.New(10 * time.Second),
@@ -104,7 +105,7 @@ func GenCaseFailureHistory(
 				TaskId:    1048606,
 				Attributes: &history.HistoryEvent_WorkflowTaskScheduledEventAttributes{
 					WorkflowTaskScheduledEventAttributes: &history.WorkflowTaskScheduledEventAttributes{
-						TaskQueue:           &taskqueue.TaskQueue{Name: "default", Kind: enums.TASK_QUEUE_KIND_NORMAL},
+						TaskQueue:           &taskqueue.TaskQueue{Name: taskQueue, Kind: enums.TASK_QUEUE_KIND_NORMAL},
 						StartToCloseTimeout: durationpb.New(10 * time.Second),
 						Attempt:             1,
 					},
@@ -181,7 +182,7 @@ func GenCaseFailureHistory(
 							Name: "SuccessCase",
 						},
 						TaskQueue: &taskqueue.TaskQueue{
-							Name: "default",
+							Name: taskQueue,
 							Kind: enums.TASK_QUEUE_KIND_NORMAL,
 						},
 						Header:                       &common.Header{},
@@ -245,7 +246,7 @@ func GenCaseFailureHistory(
 						TaskQueue: &taskqueue.TaskQueue{
 							Name:       "hidden",
 							Kind:       enums.TASK_QUEUE_KIND_STICKY,
-							NormalName: "default",
+							NormalName: taskQueue,
 						},
 						StartToCloseTimeout: durationpb.New(10 * time.Second),
 						Attempt:             1,
@@ -285,7 +286,7 @@ func GenCaseFailureHistory(
 				},
 			},
 			{
-				EventId:   14,
+				EventId:   12,
 				EventTime: parseTime("2024-05-28T10:04:11.666520Z"),
 				EventType: enums.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED,
 				TaskId:    1048640,
@@ -296,7 +297,7 @@ func GenCaseFailureHistory(
 							Name: "FailureCase",
 						},
 						TaskQueue: &taskqueue.TaskQueue{
-							Name: "default",
+							Name: taskQueue,
 							Kind: enums.TASK_QUEUE_KIND_NORMAL,
 						},
 						Header:                       &common.Header{},
@@ -369,7 +370,7 @@ func GenCaseFailureHistory(
 						TaskQueue: &taskqueue.TaskQueue{
 							Name:       "hidden",
 							Kind:       enums.TASK_QUEUE_KIND_STICKY,
-							NormalName: "default",
+							NormalName: taskQueue,
 						},
 						StartToCloseTimeout: durationpb.New(10 * time.Second),
 						Attempt:             1,
@@ -2,29 +2,41 @@ package fake
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/client"
 )
 
 var _ client.WorkflowRun = (*WorkflowRun)(nil)
 
 type WorkflowRun struct {
-	id     string
-	runID  string
-	result any
-	err    error
+	id        string
+	runID     string
+	options   client.StartWorkflowOptions
+	status    enums.WorkflowExecutionStatus
+	closeTime *time.Time
+	result    any
+	err       error
 }
 
-func newWorkflowRun(id string, result any, err error) *WorkflowRun {
+func newWorkflowRun(options client.StartWorkflowOptions, result any, err error) *WorkflowRun {
 	return &WorkflowRun{
-		id:     id,
-		runID:  uuid.NewString(),
-		result: result,
-		err:    err,
+		id:      options.ID,
+		runID:   uuid.NewString(),
+		options: options,
+		status:  enums.WORKFLOW_EXECUTION_STATUS_RUNNING,
+		result:  result,
+		err:     err,
 	}
 }
 
+// Options returns the options the workflow run was started with.
+func (f *WorkflowRun) Options() client.StartWorkflowOptions {
+	return f.options
+}
+
 func (f *WorkflowRun) GetID() string {
 	return f.id
 }
@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.temporal.io/api/common/v1"
 	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/history/v1"
 	"go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/workflow/v1"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/client"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -33,6 +36,8 @@ type Workflower struct {
 	workflows map[string]*WorkflowRun // map keys generated by workflowsKey
 	latest    map[string]string       // latest run id for a workflow
 	history   *history.History
+	resets    []*workflowservice.ResetWorkflowExecutionRequest
+	pollers   map[string][]*taskqueue.PollerInfo // task queue pollers set by SetPollers
 }
 
 func NewWorkflower(opts ...WorkflowerOption) *Workflower {
@@ -40,6 +45,7 @@ func NewWorkflower(opts ...WorkflowerOption) *Workflower {
 		mu:        new(sync.RWMutex),
 		workflows: map[string]*WorkflowRun{},
 		latest:    map[string]string{},
+		pollers:   map[string][]*taskqueue.PollerInfo{},
 	}
 	for _, opt := range opts {
 		opt(w)
@@ -59,7 +65,7 @@ func (w *Workflower) ExecuteWorkflow(_ context.Context, options client.StartWork
 		return nil, errors.New("fake workflow execution id is required in start workflow options")
 	}
 
-	wr := newWorkflowRun(options.ID, nil, nil)
+	wr := newWorkflowRun(options, nil, nil)
 
 	w.mu.Lock()
 	w.workflows[workflowsKey(wr.GetID(), wr.GetRunID())] = wr
@@ -106,6 +112,9 @@ func (w *Workflower) ResetWorkflowExecution(_ context.Context, req *workflowserv
 	}
 	for _, event := range w.history.Events {
 		if event.EventId == req.WorkflowTaskFinishEventId && isResettableEvent(event.EventType) {
+			w.mu.Lock()
+			w.resets = append(w.resets, req)
+			w.mu.Unlock()
 			return &workflowservice.ResetWorkflowExecutionResponse{
 				RunId: uuid.New().String(),
 			}, nil
@@ -116,17 +125,87 @@ func (w *Workflower) ResetWorkflowExecution(_ context.Context, req *workflowserv
 	)
 }
 
+// ResetRequests returns the requests of all successful workflow resets.
+func (w *Workflower) ResetRequests() []*workflowservice.ResetWorkflowExecutionRequest {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Clone(w.resets)
+}
+
 func (w *Workflower) CancelWorkflow(_ context.Context, workflowID string, runID string) error {
-	if _, err := w.getWorkflowRun(workflowID, runID); err != nil {
+	wr, err := w.getWorkflowRun(workflowID, runID)
+	if err != nil {
 		return err
 	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.workflows, workflowsKey(workflowID, wr.GetRunID()))
+	return nil
+}
+
+func (w *Workflower) DescribeWorkflowExecution(_ context.Context, workflowID string, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	wr, err := w.getWorkflowRun(workflowID, runID)
+	if err != nil {
+		return nil, err
+	}
+
 	w.mu.RLock()
 	defer w.mu.RUnlock()
-	delete(w.workflows, workflowsKey(workflowID, runID))
+
+	run := wr.(*WorkflowRun)
+	info := &workflow.WorkflowExecutionInfo{
+		Execution: &common.WorkflowExecution{
+			WorkflowId: run.GetID(),
+			RunId:      run.GetRunID(),
+		},
+		Type:   &common.WorkflowType{Name: WorkflowName},
+		Status: run.status,
+	}
+	if run.closeTime != nil {
+		info.CloseTime = timestamppb.New(*run.closeTime)
+	}
+	return &workflowservice.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: info,
+	}, nil
+}
+
+// SetWorkflowStatus simulates the latest run of a workflow transitioning to
+// the given status. Statuses other than running also set the close time.
+func (w *Workflower) SetWorkflowStatus(workflowID string, status enums.WorkflowExecutionStatus) error {
+	wr, err := w.getWorkflowRun(workflowID, "")
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	run := wr.(*WorkflowRun)
+	run.status = status
+	run.closeTime = nil
+	if status != enums.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		now := time.Now()
+		run.closeTime = &now
+	}
 	return nil
 }
 
-func (w *Workflower) DescribeTaskQueue(_ context.Context, _ string, _ enums.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
+// SetPollers sets the pollers of a task queue. Task queues without pollers set
+// are described with a single active poller.
+func (w *Workflower) SetPollers(taskQueue string, pollers ...*taskqueue.PollerInfo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pollers[taskQueue] = pollers
+}
+
+func (w *Workflower) DescribeTaskQueue(_ context.Context, taskQueue string, _ enums.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
+	w.mu.RLock()
+	pollers, ok := w.pollers[taskQueue]
+	w.mu.RUnlock()
+	if ok {
+		return &workflowservice.DescribeTaskQueueResponse{Pollers: pollers}, nil
+	}
+
 	return &workflowservice.DescribeTaskQueueResponse{
 		Pollers: []*taskqueue.PollerInfo{
 			{
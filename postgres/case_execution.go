@@ -2,6 +2,10 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/annexsh/annex/postgres/sqlc"
 
@@ -29,7 +33,7 @@ func (c *CaseExecutionReader) GetCaseExecution(ctx context.Context, testExecID t
 	if err != nil {
 		return nil, err
 	}
-	return marshalCaseExec(caseExec), nil
+	return marshalCaseExec(c.db.codec, caseExec), nil
 }
 
 func (c *CaseExecutionReader) ListCaseExecutions(ctx context.Context, testExecID test.TestExecutionID) (test.CaseExecutionList, error) {
@@ -38,7 +42,134 @@ func (c *CaseExecutionReader) ListCaseExecutions(ctx context.Context, testExecID
 	if err != nil {
 		return nil, err
 	}
-	return marshalCaseExecs(execs), nil
+	return marshalCaseExecs(c.db.codec, execs), nil
+}
+
+func (c *CaseExecutionReader) ListAttemptCaseExecutions(ctx context.Context, testExecID test.TestExecutionID, attempt int32) (test.CaseExecutionList, error) {
+	execs, err := c.db.ListAttemptCaseExecutions(ctx, sqlc.ListAttemptCaseExecutionsParams{
+		TestExecutionID: testExecID,
+		Attempt:         attempt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalAttemptCaseExecs(c.db.codec, execs), nil
+}
+
+func (c *CaseExecutionReader) ListCaseExecutionAttempts(ctx context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID) (test.CaseExecutionAttemptList, error) {
+	attempts, err := c.db.ListCaseExecutionAttempts(ctx, sqlc.ListCaseExecutionAttemptsParams{
+		TestExecutionID: testExecID,
+		CaseExecutionID: caseExecID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalCaseExecAttempts(c.db.codec, attempts), nil
+}
+
+func (c *CaseExecutionReader) GetCaseExecutionPayloads(ctx context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID) (*test.CaseExecutionPayloads, error) {
+	inputs, err := c.db.ListCaseExecutionInputs(ctx, sqlc.ListCaseExecutionInputsParams{
+		TestExecutionID: testExecID,
+		CaseExecutionID: caseExecID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	input, err := marshalCaseExecInputs(inputs)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range input {
+		if input[i], err = test.DecodePayload(c.db.codec, p); err != nil {
+			return nil, err
+		}
+	}
+
+	payloads := &test.CaseExecutionPayloads{
+		Input: input,
+	}
+
+	res, err := c.db.GetCaseExecutionResult(ctx, sqlc.GetCaseExecutionResultParams{
+		TestExecutionID: testExecID,
+		CaseExecutionID: caseExecID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return payloads, nil
+		}
+		return nil, err
+	}
+	result, err := marshalCaseExecResult(res)
+	if err != nil {
+		return nil, err
+	}
+	if payloads.Result, err = test.DecodePayload(c.db.codec, result); err != nil {
+		return nil, err
+	}
+
+	return payloads, nil
+}
+
+func (c *CaseExecutionReader) ListAttemptCaseExecutionAttempts(ctx context.Context, testExecID test.TestExecutionID, attempt int32, caseExecID test.CaseExecutionID) (test.CaseExecutionAttemptList, error) {
+	attempts, err := c.db.ListAttemptCaseExecutionAttempts(ctx, sqlc.ListAttemptCaseExecutionAttemptsParams{
+		TestExecutionID: testExecID,
+		Attempt:         attempt,
+		CaseExecutionID: caseExecID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalAttemptCaseExecAttempts(c.db.codec, attempts), nil
+}
+
+func (c *CaseExecutionReader) GetAttemptCaseExecutionPayloads(ctx context.Context, testExecID test.TestExecutionID, attempt int32, caseExecID test.CaseExecutionID) (*test.CaseExecutionPayloads, error) {
+	inputs, err := c.db.ListAttemptCaseExecutionInputs(ctx, sqlc.ListAttemptCaseExecutionInputsParams{
+		TestExecutionID: testExecID,
+		Attempt:         attempt,
+		CaseExecutionID: caseExecID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	input, err := marshalAttemptCaseExecInputs(inputs)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range input {
+		if input[i], err = test.DecodePayload(c.db.codec, p); err != nil {
+			return nil, err
+		}
+	}
+
+	payloads := &test.CaseExecutionPayloads{
+		Input: input,
+	}
+
+	res, err := c.db.GetAttemptCaseExecutionResult(ctx, sqlc.GetAttemptCaseExecutionResultParams{
+		TestExecutionID: testExecID,
+		Attempt:         attempt,
+		CaseExecutionID: caseExecID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return payloads, nil
+		}
+		return nil, err
+	}
+	result, err := marshalCaseExecResult(&sqlc.CaseExecutionResult{
+		TestExecutionID: res.TestExecutionID,
+		CaseExecutionID: res.CaseExecutionID,
+		Data:            res.Data,
+		Metadata:        res.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if payloads.Result, err = test.DecodePayload(c.db.codec, result); err != nil {
+		return nil, err
+	}
+
+	return payloads, nil
 }
 
 type CaseExecutionWriter struct {
@@ -59,7 +190,7 @@ func (c *CaseExecutionWriter) CreateScheduledCaseExecution(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	return marshalCaseExec(exec), nil
+	return marshalCaseExec(c.db.codec, exec), nil
 }
 
 func (c *CaseExecutionWriter) UpdateStartedCaseExecution(ctx context.Context, started *test.StartedCaseExecution) (*test.CaseExecution, error) {
@@ -68,23 +199,67 @@ func (c *CaseExecutionWriter) UpdateStartedCaseExecution(ctx context.Context, st
 		TestExecutionID: started.TestExecutionID,
 		StartTime:       sqlc.NewTimestamp(started.StartTime),
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Stopped case executions are not restarted by late acks
+		exec, err = c.db.GetCaseExecution(ctx, sqlc.GetCaseExecutionParams{
+			ID:              started.ID,
+			TestExecutionID: started.TestExecutionID,
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
-	return marshalCaseExec(exec), nil
+	return marshalCaseExec(c.db.codec, exec), nil
 }
 
 func (c *CaseExecutionWriter) UpdateFinishedCaseExecution(ctx context.Context, finished *test.FinishedCaseExecution) (*test.CaseExecution, error) {
+	failure, err := encodeFailure(c.db.codec, finished.Failure)
+	if err != nil {
+		return nil, err
+	}
 	exec, err := c.db.UpdateCaseExecutionFinished(ctx, sqlc.UpdateCaseExecutionFinishedParams{
 		ID:              finished.ID,
 		TestExecutionID: finished.TestExecutionID,
 		FinishTime:      sqlc.NewTimestamp(finished.FinishTime),
 		Error:           finished.Error,
+		Status:          finished.Status(),
+		Failure:         failure,
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Stopped case executions keep their final status
+		exec, err = c.db.GetCaseExecution(ctx, sqlc.GetCaseExecutionParams{
+			ID:              finished.ID,
+			TestExecutionID: finished.TestExecutionID,
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
-	return marshalCaseExec(exec), nil
+	return marshalCaseExec(c.db.codec, exec), nil
+}
+
+func (c *CaseExecutionWriter) UpdateCaseExecutionHeartbeat(ctx context.Context, heartbeat *test.CaseExecutionHeartbeat) (*test.CaseExecution, error) {
+	progress, err := encodeProgress(c.db.codec, heartbeat.Progress)
+	if err != nil {
+		return nil, err
+	}
+	exec, err := c.db.UpdateCaseExecutionHeartbeat(ctx, sqlc.UpdateCaseExecutionHeartbeatParams{
+		ID:              heartbeat.ID,
+		TestExecutionID: heartbeat.TestExecutionID,
+		HeartbeatTime:   sqlc.NewTimestamp(heartbeat.HeartbeatTime),
+		Progress:        progress,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Stopped case executions keep their final heartbeat and progress
+		exec, err = c.db.GetCaseExecution(ctx, sqlc.GetCaseExecutionParams{
+			ID:              heartbeat.ID,
+			TestExecutionID: heartbeat.TestExecutionID,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return marshalCaseExec(c.db.codec, exec), nil
 }
 
 func (c *CaseExecutionWriter) DeleteCaseExecution(ctx context.Context, testExecID test.TestExecutionID, id test.CaseExecutionID) error {
@@ -93,3 +268,101 @@ func (c *CaseExecutionWriter) DeleteCaseExecution(ctx context.Context, testExecI
 		TestExecutionID: testExecID,
 	})
 }
+
+func (c *CaseExecutionWriter) UpdateCaseExecutionInput(ctx context.Context, testExecID test.TestExecutionID, id test.CaseExecutionID, input []*test.Payload) error {
+	return c.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		if err := querier.DeleteCaseExecutionInputs(ctx, sqlc.DeleteCaseExecutionInputsParams{
+			TestExecutionID: testExecID,
+			CaseExecutionID: id,
+		}); err != nil {
+			return err
+		}
+		for i, p := range input {
+			encoded, err := test.EncodePayload(c.db.codec, p)
+			if err != nil {
+				return err
+			}
+			metadata, err := json.Marshal(encoded.Metadata)
+			if err != nil {
+				return err
+			}
+			if err = querier.CreateCaseExecutionInput(ctx, sqlc.CreateCaseExecutionInputParams{
+				TestExecutionID: testExecID,
+				CaseExecutionID: id,
+				Index:           int32(i),
+				Data:            encoded.Data,
+				Metadata:        metadata,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *CaseExecutionWriter) UpdateCaseExecutionResult(ctx context.Context, testExecID test.TestExecutionID, id test.CaseExecutionID, result *test.Payload) error {
+	encoded, err := test.EncodePayload(c.db.codec, result)
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(encoded.Metadata)
+	if err != nil {
+		return err
+	}
+	return c.db.CreateCaseExecutionResult(ctx, sqlc.CreateCaseExecutionResultParams{
+		TestExecutionID: testExecID,
+		CaseExecutionID: id,
+		Data:            encoded.Data,
+		Metadata:        metadata,
+	})
+}
+
+func (c *CaseExecutionWriter) CreateStartedCaseExecutionAttempt(ctx context.Context, started *test.StartedCaseExecutionAttempt) (*test.CaseExecutionAttempt, error) {
+	var attempt *sqlc.CaseExecutionAttempt
+
+	err := c.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		// Previous attempts that never reported finishing timed out
+		if err := querier.UpdatePreviousCaseExecutionAttemptsTimedOut(ctx, sqlc.UpdatePreviousCaseExecutionAttemptsTimedOutParams{
+			TestExecutionID: started.TestExecutionID,
+			CaseExecutionID: started.CaseExecutionID,
+			Attempt:         started.Attempt,
+			FinishTime:      sqlc.NewTimestamp(started.StartTime),
+		}); err != nil {
+			return err
+		}
+
+		var err error
+		attempt, err = querier.CreateCaseExecutionAttempt(ctx, sqlc.CreateCaseExecutionAttemptParams{
+			TestExecutionID: started.TestExecutionID,
+			CaseExecutionID: started.CaseExecutionID,
+			Attempt:         started.Attempt,
+			StartTime:       sqlc.NewTimestamp(started.StartTime),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalCaseExecAttempt(c.db.codec, attempt), nil
+}
+
+func (c *CaseExecutionWriter) UpdateFinishedCaseExecutionAttempt(ctx context.Context, finished *test.FinishedCaseExecutionAttempt) (*test.CaseExecutionAttempt, error) {
+	failure, err := encodeFailure(c.db.codec, finished.Failure)
+	if err != nil {
+		return nil, err
+	}
+	attempt, err := c.db.UpdateCaseExecutionAttemptFinished(ctx, sqlc.UpdateCaseExecutionAttemptFinishedParams{
+		TestExecutionID: finished.TestExecutionID,
+		CaseExecutionID: finished.CaseExecutionID,
+		Attempt:         finished.Attempt,
+		FinishTime:      sqlc.NewTimestamp(finished.FinishTime),
+		Error:           finished.Error,
+		Status:          finished.Status(),
+		Failure:         failure,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalCaseExecAttempt(c.db.codec, attempt), nil
+}
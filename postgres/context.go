@@ -5,9 +5,9 @@ import (
 	"errors"
 
 	"github.com/jackc/pgerrcode"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 
+	"github.com/annexsh/annex/postgres/sqlc"
 	"github.com/annexsh/annex/test"
 )
 
@@ -29,13 +29,11 @@ func NewContextReader(db *DB) *ContextReader {
 }
 
 func (c *ContextReader) ContextExists(ctx context.Context, id string) (bool, error) {
-	if err := c.db.ContextExists(ctx, id); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return false, nil
-		}
-		return false, err
-	}
-	return true, nil
+	return c.db.ContextExists(ctx, id)
+}
+
+func (c *ContextReader) IsContextArchived(ctx context.Context, id string) (bool, error) {
+	return c.db.IsContextArchived(ctx, id)
 }
 
 type ContextWriter struct {
@@ -48,13 +46,35 @@ func NewContextWriter(db *DB) *ContextWriter {
 
 func (c *ContextWriter) CreateContext(ctx context.Context, id string) error {
 	err := c.db.CreateContext(ctx, id)
+	if id == "default" && isUniqueViolation(err) {
+		return nil
+	}
+	return err
+}
 
-	var pgErr *pgconn.PgError
-	if id == "default" && errors.As(err, &pgErr) {
-		if pgErr.Code == pgerrcode.UniqueViolation {
-			return nil
+func (c *ContextWriter) UpdateContextArchived(ctx context.Context, id string, archived bool) error {
+	return c.db.UpdateContextArchived(ctx, sqlc.UpdateContextArchivedParams{
+		ID:       id,
+		Archived: archived,
+	})
+}
+
+func (c *ContextWriter) DeleteContext(ctx context.Context, id string) error {
+	return c.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		active, err := querier.HasActiveTestExecutions(ctx, sqlc.HasActiveTestExecutionsParams{
+			ContextID: id,
+		})
+		if err != nil {
+			return err
 		}
-	}
+		if active {
+			return test.ErrorExecutionsActive
+		}
+		return querier.DeleteContext(ctx, id)
+	})
+}
 
-	return err
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation
 }
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextReader_ContextExists(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewContextReader(db)
+
+	contextID := uuid.NewString()
+	require.NoError(t, NewContextWriter(db).CreateContext(ctx, contextID))
+
+	exists, err := r.ContextExists(ctx, contextID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = r.ContextExists(ctx, uuid.NewString())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
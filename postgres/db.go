@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	"github.com/annexsh/annex/postgres/sqlc"
+	"github.com/annexsh/annex/test"
 )
 
 type DBTX interface {
@@ -15,15 +16,33 @@ type DBTX interface {
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 }
 
+type DBOption func(opts *dbOptions)
+
+// WithPayloadCodec encodes test inputs, failure details and progress with the
+// codec before they are stored and decodes them when they are read, e.g. to
+// encrypt them at rest.
+func WithPayloadCodec(codec test.PayloadCodec) DBOption {
+	return func(opts *dbOptions) {
+		opts.codec = codec
+	}
+}
+
 type DB struct {
 	*sqlc.Queries
 	beginTx func(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	codec   test.PayloadCodec
 }
 
-func NewDB(dbtx DBTX) *DB {
+func NewDB(dbtx DBTX, opts ...DBOption) *DB {
+	var options dbOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &DB{
 		Queries: sqlc.New(dbtx),
 		beginTx: dbtx.BeginTx,
+		codec:   options.codec,
 	}
 }
 
@@ -53,3 +72,7 @@ func (d *DB) ExecuteTx(ctx context.Context, query func(querier sqlc.Querier) err
 
 	return tx.Commit(ctx)
 }
+
+type dbOptions struct {
+	codec test.PayloadCodec
+}
@@ -23,21 +23,43 @@ func NewGroupReader(db *DB) *GroupReader {
 	return &GroupReader{db: db}
 }
 
-func (g *GroupReader) ListGroups(ctx context.Context, contextID string) ([]string, error) {
-	return g.db.ListGroups(ctx, contextID)
+func (g *GroupReader) ListGroups(ctx context.Context, contextID string, filter *test.GroupListFilter) ([]string, error) {
+	return g.db.ListGroups(ctx, sqlc.ListGroupsParams{
+		ContextID:       contextID,
+		IncludeArchived: filter.IncludeArchived,
+	})
 }
 
 func (g *GroupReader) GroupExists(ctx context.Context, contextID string, groupID string) (bool, error) {
-	if err := g.db.GroupExists(ctx, sqlc.GroupExistsParams{
+	return g.db.GroupExists(ctx, sqlc.GroupExistsParams{
 		ContextID: contextID,
 		ID:        groupID,
-	}); err != nil {
+	})
+}
+
+func (g *GroupReader) GetGroupTaskQueue(ctx context.Context, contextID string, groupID string) (string, error) {
+	taskQueue, err := g.db.GetGroupTaskQueue(ctx, sqlc.GetGroupTaskQueueParams{
+		ContextID: contextID,
+		ID:        groupID,
+	})
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return false, nil
+			return "", test.ErrorGroupNotFound
 		}
-		return false, err
+		return "", err
 	}
-	return true, nil
+	return taskQueue, nil
+}
+
+func (g *GroupReader) TaskQueueExists(ctx context.Context, taskQueue string) (bool, error) {
+	return g.db.TaskQueueExists(ctx, taskQueue)
+}
+
+func (g *GroupReader) IsGroupArchived(ctx context.Context, contextID string, groupID string) (bool, error) {
+	return g.db.IsGroupArchived(ctx, sqlc.IsGroupArchivedParams{
+		ContextID: contextID,
+		ID:        groupID,
+	})
 }
 
 type GroupWriter struct {
@@ -48,9 +70,77 @@ func NewGroupWriter(db *DB) *GroupWriter {
 	return &GroupWriter{db: db}
 }
 
-func (g *GroupWriter) CreateGroup(ctx context.Context, contextID string, groupID string) error {
+func (g *GroupWriter) CreateGroup(ctx context.Context, contextID string, groupID string, taskQueue string) error {
 	return g.db.CreateGroup(ctx, sqlc.CreateGroupParams{
 		ContextID: contextID,
 		ID:        groupID,
+		TaskQueue: taskQueue,
+	})
+}
+
+func (g *GroupWriter) UpdateGroupArchived(ctx context.Context, contextID string, groupID string, archived bool) error {
+	return g.db.UpdateGroupArchived(ctx, sqlc.UpdateGroupArchivedParams{
+		ContextID: contextID,
+		ID:        groupID,
+		Archived:  archived,
+	})
+}
+
+func (g *GroupWriter) DeleteGroup(ctx context.Context, contextID string, groupID string) error {
+	return g.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		active, err := querier.HasActiveTestExecutions(ctx, sqlc.HasActiveTestExecutionsParams{
+			ContextID: contextID,
+			GroupID:   &groupID,
+		})
+		if err != nil {
+			return err
+		}
+		if active {
+			return test.ErrorExecutionsActive
+		}
+		// Runs of the group have no foreign key to it
+		if err = querier.DeleteGroupTestRuns(ctx, sqlc.DeleteGroupTestRunsParams{
+			ContextID: contextID,
+			GroupID:   &groupID,
+		}); err != nil {
+			return err
+		}
+		return querier.DeleteGroup(ctx, sqlc.DeleteGroupParams{
+			ContextID: contextID,
+			ID:        groupID,
+		})
+	})
+}
+
+func (g *GroupWriter) RenameGroup(ctx context.Context, contextID string, groupID string, newGroupID string) error {
+	return g.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		active, err := querier.HasActiveTestExecutions(ctx, sqlc.HasActiveTestExecutionsParams{
+			ContextID: contextID,
+			GroupID:   &groupID,
+		})
+		if err != nil {
+			return err
+		}
+		if active {
+			return test.ErrorExecutionsActive
+		}
+
+		// Tests follow the group through the cascading foreign key
+		if err = querier.RenameGroup(ctx, sqlc.RenameGroupParams{
+			NewID:     newGroupID,
+			ContextID: contextID,
+			ID:        groupID,
+		}); err != nil {
+			if isUniqueViolation(err) {
+				return test.ErrorGroupAlreadyExists
+			}
+			return err
+		}
+
+		return querier.RenameTestRunsGroup(ctx, sqlc.RenameTestRunsGroupParams{
+			NewGroupID: &newGroupID,
+			ContextID:  contextID,
+			GroupID:    &groupID,
+		})
 	})
 }
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupReader_GroupExists(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewGroupReader(db)
+	w := NewGroupWriter(db)
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+
+	require.NoError(t, NewContextWriter(db).CreateContext(ctx, contextID))
+	require.NoError(t, w.CreateGroup(ctx, contextID, groupID, uuid.NewString()))
+
+	exists, err := r.GroupExists(ctx, contextID, groupID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = r.GroupExists(ctx, contextID, uuid.NewString())
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = r.GroupExists(ctx, uuid.NewString(), groupID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
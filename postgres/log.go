@@ -40,6 +40,17 @@ func (e *LogReader) ListLogs(ctx context.Context, testExecID test.TestExecutionI
 	return marshalExecLogs(logs), nil
 }
 
+func (e *LogReader) ListAttemptLogs(ctx context.Context, testExecID test.TestExecutionID, attempt int32) (test.LogList, error) {
+	logs, err := e.db.ListAttemptLogs(ctx, sqlc.ListAttemptLogsParams{
+		TestExecutionID: testExecID,
+		Attempt:         attempt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalAttemptLogs(logs), nil
+}
+
 type LogWriter struct {
 	db *DB
 }
@@ -1,6 +1,9 @@
 package postgres
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/annexsh/annex/postgres/sqlc"
 
 	"github.com/annexsh/annex/test"
@@ -8,12 +11,23 @@ import (
 
 func marshalTest(t *sqlc.Test) *test.Test {
 	return &test.Test{
-		ContextID:  t.ContextID,
-		GroupID:    t.GroupID,
-		ID:         t.ID,
-		Name:       t.Name,
-		HasInput:   t.HasInput,
-		CreateTime: t.CreateTime.Time,
+		ContextID:        t.ContextID,
+		GroupID:          t.GroupID,
+		ID:               t.ID,
+		Name:             t.Name,
+		HasInput:         t.HasInput,
+		CreateTime:       t.CreateTime.Time,
+		ExecutionTimeout: t.ExecutionTimeout.Pointer(),
+		RunTimeout:       t.RunTimeout.Pointer(),
+		InputSchema:      t.InputSchema,
+		Deprecated:       t.Deprecated,
+		Version:          t.Version,
+		Metadata: test.TestMetadata{
+			Description:    t.Description,
+			Tags:           t.Tags,
+			Owner:          t.Owner,
+			SourceLocation: t.SourceLocation,
+		},
 	}
 }
 
@@ -25,25 +39,191 @@ func marshalTests(tests []*sqlc.Test) []*test.Test {
 	return testspb
 }
 
-func marshalTestDefaultInput(input *sqlc.TestDefaultInput) *test.Payload {
+func marshalTestDefaultInput(input *sqlc.TestDefaultInput) (*test.Payload, error) {
+	var metadata map[string][]byte
+	if err := json.Unmarshal(input.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid test default input metadata: %w", err)
+	}
 	return &test.Payload{
-		Data: input.Data,
+		Metadata: metadata,
+		Data:     input.Data,
+	}, nil
+}
+
+func marshalTestVersion(codec test.PayloadCodec, v *sqlc.TestVersion) (*test.TestVersion, error) {
+	out := &test.TestVersion{
+		TestID:           v.TestID,
+		Version:          v.Version,
+		ExecutionTimeout: v.ExecutionTimeout.Pointer(),
+		RunTimeout:       v.RunTimeout.Pointer(),
+		InputSchema:      v.InputSchema,
+		CreateTime:       v.CreateTime.Time,
+	}
+	if v.HasInput {
+		var metadata map[string][]byte
+		if err := json.Unmarshal(v.DefaultInputMetadata, &metadata); err != nil {
+			return nil, fmt.Errorf("invalid test version default input metadata: %w", err)
+		}
+		input, err := test.DecodePayload(codec, &test.Payload{
+			Metadata: metadata,
+			Data:     v.DefaultInputData,
+		})
+		if err != nil {
+			return nil, err
+		}
+		out.DefaultInput = input
+	}
+	return out, nil
+}
+
+func marshalTestVersions(codec test.PayloadCodec, versions []*sqlc.TestVersion) (test.TestVersionList, error) {
+	out := make(test.TestVersionList, len(versions))
+	for i, v := range versions {
+		var err error
+		if out[i], err = marshalTestVersion(codec, v); err != nil {
+			return nil, err
+		}
 	}
+	return out, nil
 }
 
-func marshalTestExecPayload(input *sqlc.TestExecutionInput) *test.Payload {
+func marshalTestExecPayload(input *sqlc.TestExecutionInput) (*test.Payload, error) {
+	var metadata map[string][]byte
+	if err := json.Unmarshal(input.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid test execution input metadata: %w", err)
+	}
 	return &test.Payload{
-		Data: input.Data,
+		Metadata: metadata,
+		Data:     input.Data,
+	}, nil
+}
+
+func marshalCaseExecAttempt(codec test.PayloadCodec, attempt *sqlc.CaseExecutionAttempt) *test.CaseExecutionAttempt {
+	a := &test.CaseExecutionAttempt{
+		TestExecutionID: attempt.TestExecutionID,
+		CaseExecutionID: attempt.CaseExecutionID,
+		Attempt:         attempt.Attempt,
+		Error:           attempt.Error,
+		Failure:         marshalFailure(codec, attempt.Failure),
+		Status:          attempt.Status,
+	}
+	if attempt.StartTime.Valid {
+		a.StartTime = &attempt.StartTime.Time
 	}
+	if attempt.FinishTime.Valid {
+		a.FinishTime = &attempt.FinishTime.Time
+	}
+	return a
+}
+
+func marshalCaseExecAttempts(codec test.PayloadCodec, attempts []*sqlc.CaseExecutionAttempt) test.CaseExecutionAttemptList {
+	a := make(test.CaseExecutionAttemptList, len(attempts))
+	for i, attempt := range attempts {
+		a[i] = marshalCaseExecAttempt(codec, attempt)
+	}
+	return a
+}
+
+func marshalCaseExecInputs(inputs []*sqlc.CaseExecutionInput) ([]*test.Payload, error) {
+	payloads := make([]*test.Payload, len(inputs))
+	for i, input := range inputs {
+		var metadata map[string][]byte
+		if err := json.Unmarshal(input.Metadata, &metadata); err != nil {
+			return nil, fmt.Errorf("invalid case execution input metadata: %w", err)
+		}
+		payloads[i] = &test.Payload{
+			Metadata: metadata,
+			Data:     input.Data,
+		}
+	}
+	return payloads, nil
+}
+
+func marshalCaseExecResult(result *sqlc.CaseExecutionResult) (*test.Payload, error) {
+	var metadata map[string][]byte
+	if err := json.Unmarshal(result.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid case execution result metadata: %w", err)
+	}
+	return &test.Payload{
+		Metadata: metadata,
+		Data:     result.Data,
+	}, nil
 }
 
-func marshalTestExec(testExec *sqlc.TestExecution) *test.TestExecution {
+// marshalFailure unmarshals and decodes a failure stored by encodeFailure. A
+// failure that cannot be unmarshalled or decoded is treated as missing rather
+// than failing the read of its execution.
+func marshalFailure(codec test.PayloadCodec, raw json.RawMessage) *test.Failure {
+	if len(raw) == 0 {
+		return nil
+	}
+	var failure test.Failure
+	if err := json.Unmarshal(raw, &failure); err != nil {
+		return nil
+	}
+	decoded, err := test.DecodeFailure(codec, &failure)
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
+
+func encodeFailure(codec test.PayloadCodec, failure *test.Failure) (json.RawMessage, error) {
+	if failure == nil {
+		return nil, nil
+	}
+	encoded, err := test.EncodeFailure(codec, failure)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encoded)
+}
+
+// marshalProgress unmarshals and decodes progress stored by encodeProgress.
+// Like failures, progress that cannot be unmarshalled or decoded is treated as
+// missing.
+func marshalProgress(codec test.PayloadCodec, raw json.RawMessage) []*test.Payload {
+	if len(raw) == 0 {
+		return nil
+	}
+	var progress []*test.Payload
+	if err := json.Unmarshal(raw, &progress); err != nil {
+		return nil
+	}
+	decoded, err := test.DecodePayloads(codec, progress)
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
+
+func encodeProgress(codec test.PayloadCodec, progress []*test.Payload) (json.RawMessage, error) {
+	if len(progress) == 0 {
+		return nil, nil
+	}
+	encoded, err := test.EncodePayloads(codec, progress)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encoded)
+}
+
+func marshalTestExec(codec test.PayloadCodec, testExec *sqlc.TestExecution) *test.TestExecution {
 	t := &test.TestExecution{
-		ID:           testExec.ID,
-		TestID:       testExec.TestID,
-		HasInput:     testExec.HasInput,
-		ScheduleTime: testExec.ScheduleTime.Time,
-		Error:        testExec.Error,
+		ID:               testExec.ID,
+		TestID:           testExec.TestID,
+		HasInput:         testExec.HasInput,
+		ScheduleTime:     testExec.ScheduleTime.Time,
+		Error:            testExec.Error,
+		Status:           testExec.Status,
+		ExecutionTimeout: testExec.ExecutionTimeout.Pointer(),
+		RunTimeout:       testExec.RunTimeout.Pointer(),
+		TestRunID:        testExec.TestRunID,
+		Attempt:          testExec.Attempt,
+		RerunOfID:        testExec.RerunOfID,
+		TestVersion:      testExec.TestVersion,
+		Failure:          marshalFailure(codec, testExec.Failure),
+		ParentID:         testExec.ParentID,
 	}
 	if testExec.StartTime.Valid {
 		t.StartTime = &testExec.StartTime.Time
@@ -51,24 +231,69 @@ func marshalTestExec(testExec *sqlc.TestExecution) *test.TestExecution {
 	if testExec.FinishTime.Valid {
 		t.FinishTime = &testExec.FinishTime.Time
 	}
+	if testExec.ChildWorkflowID != nil {
+		t.ChildWorkflowID = *testExec.ChildWorkflowID
+	}
+	if testExec.ChildWorkflowType != nil {
+		t.ChildWorkflowType = *testExec.ChildWorkflowType
+	}
 	return t
 }
 
-func marshalTestExecs(testExecs []*sqlc.TestExecution) []*test.TestExecution {
+func marshalTestExecs(codec test.PayloadCodec, testExecs []*sqlc.TestExecution) []*test.TestExecution {
 	te := make([]*test.TestExecution, len(testExecs))
 	for i, testExec := range testExecs {
-		te[i] = marshalTestExec(testExec)
+		te[i] = marshalTestExec(codec, testExec)
 	}
 	return te
 }
 
-func marshalCaseExec(caseExec *sqlc.CaseExecution) *test.CaseExecution {
+func marshalTestExecSummary(codec test.PayloadCodec, row *sqlc.ListRecentTestExecutionsRow) *test.TestExecutionSummary {
+	return &test.TestExecutionSummary{
+		ContextID: row.ContextID,
+		GroupID:   row.GroupID,
+		TestName:  row.TestName,
+		Execution: marshalTestExec(codec, &sqlc.TestExecution{
+			ID:                row.ID,
+			TestID:            row.TestID,
+			HasInput:          row.HasInput,
+			ScheduleTime:      row.ScheduleTime,
+			StartTime:         row.StartTime,
+			FinishTime:        row.FinishTime,
+			Error:             row.Error,
+			Status:            row.Status,
+			ExecutionTimeout:  row.ExecutionTimeout,
+			RunTimeout:        row.RunTimeout,
+			TestRunID:         row.TestRunID,
+			Attempt:           row.Attempt,
+			RerunOfID:         row.RerunOfID,
+			TestVersion:       row.TestVersion,
+			Failure:           row.Failure,
+			ParentID:          row.ParentID,
+			ChildWorkflowID:   row.ChildWorkflowID,
+			ChildWorkflowType: row.ChildWorkflowType,
+		}),
+	}
+}
+
+func marshalTestExecSummaries(codec test.PayloadCodec, rows []*sqlc.ListRecentTestExecutionsRow) []*test.TestExecutionSummary {
+	summaries := make([]*test.TestExecutionSummary, len(rows))
+	for i, row := range rows {
+		summaries[i] = marshalTestExecSummary(codec, row)
+	}
+	return summaries
+}
+
+func marshalCaseExec(codec test.PayloadCodec, caseExec *sqlc.CaseExecution) *test.CaseExecution {
 	c := &test.CaseExecution{
 		ID:              caseExec.ID,
 		TestExecutionID: caseExec.TestExecutionID,
 		CaseName:        caseExec.CaseName,
 		ScheduleTime:    caseExec.ScheduleTime.Time,
 		Error:           caseExec.Error,
+		Failure:         marshalFailure(codec, caseExec.Failure),
+		Status:          caseExec.Status,
+		Progress:        marshalProgress(codec, caseExec.Progress),
 	}
 	if caseExec.StartTime.Valid {
 		c.StartTime = &caseExec.StartTime.Time
@@ -76,13 +301,16 @@ func marshalCaseExec(caseExec *sqlc.CaseExecution) *test.CaseExecution {
 	if caseExec.FinishTime.Valid {
 		c.FinishTime = &caseExec.FinishTime.Time
 	}
+	if caseExec.HeartbeatTime.Valid {
+		c.HeartbeatTime = &caseExec.HeartbeatTime.Time
+	}
 	return c
 }
 
-func marshalCaseExecs(caseExecs []*sqlc.CaseExecution) []*test.CaseExecution {
+func marshalCaseExecs(codec test.PayloadCodec, caseExecs []*sqlc.CaseExecution) []*test.CaseExecution {
 	ce := make([]*test.CaseExecution, len(caseExecs))
 	for i, caseExec := range caseExecs {
-		ce[i] = marshalCaseExec(caseExec)
+		ce[i] = marshalCaseExec(codec, caseExec)
 	}
 	return ce
 }
@@ -105,3 +333,142 @@ func marshalExecLogs(logs []*sqlc.Log) []*test.Log {
 	}
 	return execLogs
 }
+
+func marshalTestExecAttempt(codec test.PayloadCodec, attempt *sqlc.TestExecutionAttempt) *test.TestExecutionAttempt {
+	a := &test.TestExecutionAttempt{
+		TestExecutionID: attempt.TestExecutionID,
+		Attempt:         attempt.Attempt,
+		ScheduleTime:    attempt.ScheduleTime.Time,
+		Error:           attempt.Error,
+		Failure:         marshalFailure(codec, attempt.Failure),
+		Status:          attempt.Status,
+		ArchiveTime:     attempt.ArchiveTime.Time,
+	}
+	if attempt.StartTime.Valid {
+		a.StartTime = &attempt.StartTime.Time
+	}
+	if attempt.FinishTime.Valid {
+		a.FinishTime = &attempt.FinishTime.Time
+	}
+	return a
+}
+
+func marshalTestExecAttempts(codec test.PayloadCodec, attempts []*sqlc.TestExecutionAttempt) []*test.TestExecutionAttempt {
+	a := make([]*test.TestExecutionAttempt, len(attempts))
+	for i, attempt := range attempts {
+		a[i] = marshalTestExecAttempt(codec, attempt)
+	}
+	return a
+}
+
+func marshalAttemptCaseExecs(codec test.PayloadCodec, caseExecs []*sqlc.AttemptCaseExecution) []*test.CaseExecution {
+	ce := make([]*test.CaseExecution, len(caseExecs))
+	for i, caseExec := range caseExecs {
+		ce[i] = marshalCaseExec(codec, &sqlc.CaseExecution{
+			ID:              caseExec.ID,
+			TestExecutionID: caseExec.TestExecutionID,
+			CaseName:        caseExec.CaseName,
+			ScheduleTime:    caseExec.ScheduleTime,
+			StartTime:       caseExec.StartTime,
+			FinishTime:      caseExec.FinishTime,
+			Error:           caseExec.Error,
+			Status:          caseExec.Status,
+			Failure:         caseExec.Failure,
+			HeartbeatTime:   caseExec.HeartbeatTime,
+			Progress:        caseExec.Progress,
+		})
+	}
+	return ce
+}
+
+func marshalAttemptCaseExecAttempts(codec test.PayloadCodec, attempts []*sqlc.AttemptCaseExecutionAttempt) test.CaseExecutionAttemptList {
+	a := make(test.CaseExecutionAttemptList, len(attempts))
+	for i, attempt := range attempts {
+		a[i] = marshalCaseExecAttempt(codec, &sqlc.CaseExecutionAttempt{
+			TestExecutionID: attempt.TestExecutionID,
+			CaseExecutionID: attempt.CaseExecutionID,
+			Attempt:         attempt.CaseAttempt,
+			StartTime:       attempt.StartTime,
+			FinishTime:      attempt.FinishTime,
+			Error:           attempt.Error,
+			Status:          attempt.Status,
+			Failure:         attempt.Failure,
+		})
+	}
+	return a
+}
+
+func marshalAttemptCaseExecInputs(inputs []*sqlc.AttemptCaseExecutionInput) ([]*test.Payload, error) {
+	converted := make([]*sqlc.CaseExecutionInput, len(inputs))
+	for i, input := range inputs {
+		converted[i] = &sqlc.CaseExecutionInput{
+			TestExecutionID: input.TestExecutionID,
+			CaseExecutionID: input.CaseExecutionID,
+			Index:           input.Index,
+			Data:            input.Data,
+			Metadata:        input.Metadata,
+		}
+	}
+	return marshalCaseExecInputs(converted)
+}
+
+func marshalAttemptLogs(logs []*sqlc.AttemptLog) []*test.Log {
+	execLogs := make([]*test.Log, len(logs))
+	for i, log := range logs {
+		execLogs[i] = marshalLog(&sqlc.Log{
+			ID:              log.ID,
+			TestExecutionID: log.TestExecutionID,
+			CaseExecutionID: log.CaseExecutionID,
+			Level:           log.Level,
+			Message:         log.Message,
+			CreateTime:      log.CreateTime,
+		})
+	}
+	return execLogs
+}
+
+func marshalSchedule(schedule *sqlc.Schedule) *test.Schedule {
+	s := &test.Schedule{
+		ID:           schedule.ID,
+		TestID:       schedule.TestID,
+		Cron:         schedule.Cron,
+		HasInput:     schedule.HasInput,
+		Paused:       schedule.Paused,
+		CreateTime:   schedule.CreateTime.Time,
+		NextFireTime: schedule.NextFireTime.Time,
+	}
+	if schedule.LastFireTime.Valid {
+		s.LastFireTime = &schedule.LastFireTime.Time
+	}
+	return s
+}
+
+func marshalSchedules(schedules []*sqlc.Schedule) []*test.Schedule {
+	out := make([]*test.Schedule, len(schedules))
+	for i, schedule := range schedules {
+		out[i] = marshalSchedule(schedule)
+	}
+	return out
+}
+
+func marshalScheduleInput(input *sqlc.ScheduleInput) (*test.Payload, error) {
+	var metadata map[string][]byte
+	if err := json.Unmarshal(input.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid schedule input metadata: %w", err)
+	}
+	return &test.Payload{
+		Metadata: metadata,
+		Data:     input.Data,
+	}, nil
+}
+
+func marshalTestRun(codec test.PayloadCodec, run *sqlc.TestRun, execs []*sqlc.TestExecution) *test.TestRun {
+	r := &test.TestRun{
+		ID:         run.ID,
+		ContextID:  run.ContextID,
+		GroupID:    run.GroupID,
+		CreateTime: run.CreateTime.Time,
+	}
+	r.Aggregate(marshalTestExecs(codec, execs))
+	return r
+}
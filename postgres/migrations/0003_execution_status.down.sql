@@ -0,0 +1,5 @@
+ALTER TABLE test_executions
+    DROP COLUMN IF EXISTS status;
+
+ALTER TABLE case_executions
+    DROP COLUMN IF EXISTS status;
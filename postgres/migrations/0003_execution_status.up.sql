@@ -0,0 +1,23 @@
+ALTER TABLE test_executions
+    ADD COLUMN status TEXT DEFAULT 'scheduled' NOT NULL
+        CHECK (status IN ('scheduled', 'running', 'passed', 'failed', 'cancelled', 'timed_out'));
+
+UPDATE test_executions
+SET status = CASE
+                 WHEN finish_time IS NOT NULL AND error IS NOT NULL THEN 'failed'
+                 WHEN finish_time IS NOT NULL THEN 'passed'
+                 WHEN start_time IS NOT NULL THEN 'running'
+                 ELSE 'scheduled'
+    END;
+
+ALTER TABLE case_executions
+    ADD COLUMN status TEXT DEFAULT 'scheduled' NOT NULL
+        CHECK (status IN ('scheduled', 'running', 'passed', 'failed', 'cancelled', 'timed_out'));
+
+UPDATE case_executions
+SET status = CASE
+                 WHEN finish_time IS NOT NULL AND error IS NOT NULL THEN 'failed'
+                 WHEN finish_time IS NOT NULL THEN 'passed'
+                 WHEN start_time IS NOT NULL THEN 'running'
+                 ELSE 'scheduled'
+    END;
@@ -0,0 +1,7 @@
+ALTER TABLE test_executions
+    DROP COLUMN IF EXISTS execution_timeout,
+    DROP COLUMN IF EXISTS run_timeout;
+
+ALTER TABLE tests
+    DROP COLUMN IF EXISTS execution_timeout,
+    DROP COLUMN IF EXISTS run_timeout;
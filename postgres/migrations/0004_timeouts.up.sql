@@ -0,0 +1,7 @@
+ALTER TABLE tests
+    ADD COLUMN execution_timeout INTERVAL,
+    ADD COLUMN run_timeout       INTERVAL;
+
+ALTER TABLE test_executions
+    ADD COLUMN execution_timeout INTERVAL,
+    ADD COLUMN run_timeout       INTERVAL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS schedule_inputs CASCADE;
+DROP TABLE IF EXISTS schedules CASCADE;
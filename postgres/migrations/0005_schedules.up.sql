@@ -0,0 +1,20 @@
+CREATE TABLE schedules
+(
+    id             UUID                    NOT NULL PRIMARY KEY,
+    test_id        UUID                    NOT NULL REFERENCES tests (id) ON DELETE CASCADE,
+    cron           TEXT                    NOT NULL,
+    has_input      BOOLEAN                 NOT NULL,
+    paused         BOOLEAN                 NOT NULL DEFAULT FALSE,
+    create_time    TIMESTAMP DEFAULT now() NOT NULL,
+    next_fire_time TIMESTAMP               NOT NULL,
+    last_fire_time TIMESTAMP
+);
+
+CREATE INDEX schedules_next_fire_time_idx ON schedules (next_fire_time) WHERE NOT paused;
+
+CREATE TABLE schedule_inputs
+(
+    schedule_id UUID  NOT NULL REFERENCES schedules (id) ON DELETE CASCADE DEFERRABLE PRIMARY KEY,
+    metadata    JSONB NOT NULL,
+    data        BYTEA NOT NULL
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS test_executions_test_run_id_idx;
+
+ALTER TABLE test_executions
+    DROP COLUMN IF EXISTS test_run_id;
+
+DROP TABLE IF EXISTS test_runs CASCADE;
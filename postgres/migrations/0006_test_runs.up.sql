@@ -0,0 +1,12 @@
+CREATE TABLE test_runs
+(
+    id          UUID                    NOT NULL PRIMARY KEY,
+    context_id  TEXT                    NOT NULL REFERENCES contexts (id),
+    group_id    TEXT,
+    create_time TIMESTAMP DEFAULT now() NOT NULL
+);
+
+ALTER TABLE test_executions
+    ADD COLUMN test_run_id UUID REFERENCES test_runs (id);
+
+CREATE INDEX test_executions_test_run_id_idx ON test_executions (test_run_id);
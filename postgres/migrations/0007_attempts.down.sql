@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS attempt_logs;
+
+DROP TABLE IF EXISTS attempt_case_executions;
+
+DROP TABLE IF EXISTS test_execution_attempts;
+
+ALTER TABLE test_executions
+    DROP COLUMN IF EXISTS attempt;
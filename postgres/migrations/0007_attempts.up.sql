@@ -0,0 +1,45 @@
+ALTER TABLE test_executions
+    ADD COLUMN attempt INTEGER DEFAULT 1 NOT NULL;
+
+CREATE TABLE test_execution_attempts
+(
+    test_execution_id UUID                    NOT NULL REFERENCES test_executions (id) ON DELETE CASCADE,
+    attempt           INTEGER                 NOT NULL,
+    schedule_time     TIMESTAMP               NOT NULL,
+    start_time        TIMESTAMP,
+    finish_time       TIMESTAMP,
+    error             TEXT,
+    status            TEXT                    NOT NULL,
+    archive_time      TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (test_execution_id, attempt)
+);
+
+CREATE TABLE attempt_case_executions
+(
+    test_execution_id UUID      NOT NULL,
+    attempt           INTEGER   NOT NULL,
+    FOREIGN KEY (test_execution_id, attempt) REFERENCES test_execution_attempts (test_execution_id, attempt) ON DELETE CASCADE,
+    --
+    id                INTEGER   NOT NULL,
+    case_name         TEXT      NOT NULL,
+    schedule_time     TIMESTAMP NOT NULL,
+    start_time        TIMESTAMP,
+    finish_time       TIMESTAMP,
+    error             TEXT,
+    status            TEXT      NOT NULL,
+    PRIMARY KEY (test_execution_id, attempt, id)
+);
+
+CREATE TABLE attempt_logs
+(
+    test_execution_id UUID      NOT NULL,
+    attempt           INTEGER   NOT NULL,
+    FOREIGN KEY (test_execution_id, attempt) REFERENCES test_execution_attempts (test_execution_id, attempt) ON DELETE CASCADE,
+    --
+    id                UUID      NOT NULL,
+    case_execution_id INTEGER,
+    level             TEXT      NOT NULL,
+    message           TEXT      NOT NULL,
+    create_time       TIMESTAMP NOT NULL,
+    PRIMARY KEY (test_execution_id, attempt, id)
+);
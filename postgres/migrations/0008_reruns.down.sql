@@ -0,0 +1,2 @@
+ALTER TABLE test_executions
+    DROP COLUMN IF EXISTS rerun_of_id;
@@ -0,0 +1,2 @@
+ALTER TABLE test_executions
+    ADD COLUMN rerun_of_id UUID REFERENCES test_executions (id) ON DELETE SET NULL;
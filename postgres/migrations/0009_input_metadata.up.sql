@@ -0,0 +1,13 @@
+-- Inputs stored before metadata was recorded were JSON encoded by the SDKs.
+-- Metadata values are base64 encoded: {"encoding": "json/plain"}
+ALTER TABLE test_default_inputs
+    ADD COLUMN metadata JSONB DEFAULT '{"encoding": "anNvbi9wbGFpbg=="}' NOT NULL;
+
+ALTER TABLE test_default_inputs
+    ALTER COLUMN metadata DROP DEFAULT;
+
+ALTER TABLE test_execution_inputs
+    ADD COLUMN metadata JSONB DEFAULT '{"encoding": "anNvbi9wbGFpbg=="}' NOT NULL;
+
+ALTER TABLE test_execution_inputs
+    ALTER COLUMN metadata DROP DEFAULT;
@@ -0,0 +1,2 @@
+ALTER TABLE tests
+    ADD COLUMN input_schema JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE tests
+    DROP COLUMN IF EXISTS deprecated;
@@ -0,0 +1,2 @@
+ALTER TABLE tests
+    ADD COLUMN deprecated BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+ALTER TABLE test_executions
+    DROP COLUMN IF EXISTS test_version;
+
+DROP TABLE IF EXISTS test_versions;
+
+ALTER TABLE tests
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,28 @@
+ALTER TABLE tests
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE test_versions
+(
+    test_id                UUID                    NOT NULL REFERENCES tests (id) ON DELETE CASCADE,
+    version                INTEGER                 NOT NULL,
+    has_input              BOOLEAN                 NOT NULL,
+    default_input_metadata JSONB,
+    default_input_data     BYTEA,
+    execution_timeout      INTERVAL,
+    run_timeout            INTERVAL,
+    input_schema           JSONB,
+    create_time            TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (test_id, version)
+);
+
+-- The current definitions of existing tests become their first version.
+INSERT INTO test_versions (test_id, version, has_input, default_input_metadata, default_input_data,
+                           execution_timeout, run_timeout, input_schema, create_time)
+SELECT t.id, 1, t.has_input, i.metadata, i.data, t.execution_timeout, t.run_timeout, t.input_schema, t.create_time
+FROM tests t
+         LEFT JOIN test_default_inputs i ON i.test_id = t.id AND t.has_input;
+
+-- Executions scheduled before tests were versioned have no version.
+ALTER TABLE test_executions
+    ADD COLUMN test_version INTEGER,
+    ADD FOREIGN KEY (test_id, test_version) REFERENCES test_versions (test_id, version);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tests_tags_idx;
+
+ALTER TABLE tests
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS owner,
+    DROP COLUMN IF EXISTS source_location;
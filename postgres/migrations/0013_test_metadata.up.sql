@@ -0,0 +1,7 @@
+ALTER TABLE tests
+    ADD COLUMN description     TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN tags            TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN owner           TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN source_location TEXT   NOT NULL DEFAULT '';
+
+CREATE INDEX tests_tags_idx ON tests USING GIN (tags);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tests_create_time_idx;
+DROP INDEX IF EXISTS tests_owner_idx;
+DROP INDEX IF EXISTS tests_name_trgm_idx;
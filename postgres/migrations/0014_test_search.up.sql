@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX tests_name_trgm_idx ON tests USING GIN (name gin_trgm_ops);
+CREATE INDEX tests_owner_idx ON tests (owner);
+CREATE INDEX tests_create_time_idx ON tests (create_time DESC, id DESC);
@@ -0,0 +1,53 @@
+ALTER TABLE contexts
+    ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE groups
+    ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Deleting a context or group deletes its tests and their executions.
+
+ALTER TABLE groups
+    DROP CONSTRAINT groups_context_id_fkey,
+    ADD CONSTRAINT groups_context_id_fkey FOREIGN KEY (context_id)
+        REFERENCES contexts (id) ON DELETE CASCADE;
+
+ALTER TABLE tests
+    DROP CONSTRAINT tests_context_id_group_id_fkey,
+    ADD CONSTRAINT tests_context_id_group_id_fkey FOREIGN KEY (context_id, group_id)
+        REFERENCES groups (context_id, id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE test_default_inputs
+    DROP CONSTRAINT test_default_inputs_test_id_fkey,
+    ADD CONSTRAINT test_default_inputs_test_id_fkey FOREIGN KEY (test_id)
+        REFERENCES tests (id) ON DELETE CASCADE DEFERRABLE;
+
+ALTER TABLE test_runs
+    DROP CONSTRAINT test_runs_context_id_fkey,
+    ADD CONSTRAINT test_runs_context_id_fkey FOREIGN KEY (context_id)
+        REFERENCES contexts (id) ON DELETE CASCADE;
+
+ALTER TABLE test_executions
+    DROP CONSTRAINT test_executions_test_id_fkey,
+    ADD CONSTRAINT test_executions_test_id_fkey FOREIGN KEY (test_id)
+        REFERENCES tests (id) ON DELETE CASCADE,
+    DROP CONSTRAINT test_executions_test_run_id_fkey,
+    ADD CONSTRAINT test_executions_test_run_id_fkey FOREIGN KEY (test_run_id)
+        REFERENCES test_runs (id) ON DELETE SET NULL,
+    DROP CONSTRAINT test_executions_test_id_test_version_fkey,
+    ADD CONSTRAINT test_executions_test_id_test_version_fkey FOREIGN KEY (test_id, test_version)
+        REFERENCES test_versions (test_id, version) ON DELETE CASCADE;
+
+ALTER TABLE test_execution_inputs
+    DROP CONSTRAINT test_execution_inputs_test_execution_id_fkey,
+    ADD CONSTRAINT test_execution_inputs_test_execution_id_fkey FOREIGN KEY (test_execution_id)
+        REFERENCES test_executions (id) ON DELETE CASCADE DEFERRABLE;
+
+ALTER TABLE case_executions
+    DROP CONSTRAINT case_executions_test_execution_id_fkey,
+    ADD CONSTRAINT case_executions_test_execution_id_fkey FOREIGN KEY (test_execution_id)
+        REFERENCES test_executions (id) ON DELETE CASCADE;
+
+ALTER TABLE logs
+    DROP CONSTRAINT logs_test_execution_id_fkey,
+    ADD CONSTRAINT logs_test_execution_id_fkey FOREIGN KEY (test_execution_id)
+        REFERENCES test_executions (id) ON DELETE CASCADE;
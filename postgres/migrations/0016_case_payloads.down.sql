@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS case_execution_results;
+
+DROP TABLE IF EXISTS case_execution_inputs;
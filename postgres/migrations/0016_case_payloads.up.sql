@@ -0,0 +1,22 @@
+CREATE TABLE case_execution_inputs
+(
+    test_execution_id UUID    NOT NULL,
+    case_execution_id INTEGER NOT NULL,
+    FOREIGN KEY (case_execution_id, test_execution_id) REFERENCES case_executions (id, test_execution_id) ON DELETE CASCADE,
+    --
+    index             INTEGER NOT NULL,
+    data              BYTEA   NOT NULL,
+    metadata          JSONB   NOT NULL,
+    PRIMARY KEY (test_execution_id, case_execution_id, index)
+);
+
+CREATE TABLE case_execution_results
+(
+    test_execution_id UUID    NOT NULL,
+    case_execution_id INTEGER NOT NULL,
+    FOREIGN KEY (case_execution_id, test_execution_id) REFERENCES case_executions (id, test_execution_id) ON DELETE CASCADE,
+    --
+    data              BYTEA   NOT NULL,
+    metadata          JSONB   NOT NULL,
+    PRIMARY KEY (test_execution_id, case_execution_id)
+);
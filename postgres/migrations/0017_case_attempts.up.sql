@@ -0,0 +1,14 @@
+CREATE TABLE case_execution_attempts
+(
+    test_execution_id UUID      NOT NULL,
+    case_execution_id INTEGER   NOT NULL,
+    FOREIGN KEY (case_execution_id, test_execution_id) REFERENCES case_executions (id, test_execution_id) ON DELETE CASCADE,
+    --
+    attempt           INTEGER   NOT NULL,
+    start_time        TIMESTAMP, -- null when the attempt started before attempts were recorded
+    finish_time       TIMESTAMP,
+    error             TEXT,
+    status            TEXT      NOT NULL
+        CHECK (status IN ('running', 'passed', 'failed', 'cancelled', 'timed_out')),
+    PRIMARY KEY (test_execution_id, case_execution_id, attempt)
+);
@@ -0,0 +1,38 @@
+CREATE OR REPLACE FUNCTION notify_event() RETURNS TRIGGER AS
+$$
+
+DECLARE
+    data         jsonb;
+    notification json;
+
+BEGIN
+
+    -- Convert the old or new row to JSON, based on the kind of action.
+    -- Action = DELETE?             -> OLD row
+    -- Action = INSERT or UPDATE?   -> NEW row
+    IF (TG_OP = 'DELETE') THEN
+        data = to_jsonb(OLD) - 'failure';
+    ELSE
+        data = to_jsonb(NEW) - 'failure';
+    END IF;
+
+    -- Construct the notification as a JSON string.
+    notification = json_build_object(
+            'table', TG_TABLE_NAME,
+            'action', TG_OP,
+            'data', data
+                   );
+
+
+    -- Execute pg_notify(channel, notification)
+    PERFORM pg_notify('execution_events', notification::text);
+
+    -- Result is ignored since this is an AFTER trigger
+    RETURN NULL;
+END;
+
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE case_executions
+    DROP COLUMN IF EXISTS heartbeat_time,
+    DROP COLUMN IF EXISTS progress;
@@ -0,0 +1,11 @@
+DELETE
+FROM test_executions
+WHERE parent_id IS NOT NULL;
+
+DROP INDEX IF EXISTS test_executions_child_workflow_id_idx;
+DROP INDEX IF EXISTS test_executions_parent_id_idx;
+
+ALTER TABLE test_executions
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS child_workflow_id,
+    DROP COLUMN IF EXISTS child_workflow_type;
@@ -0,0 +1,7 @@
+ALTER TABLE test_executions
+    ADD COLUMN parent_id           UUID REFERENCES test_executions (id) ON DELETE CASCADE,
+    ADD COLUMN child_workflow_id   TEXT,
+    ADD COLUMN child_workflow_type TEXT;
+
+CREATE INDEX test_executions_parent_id_idx ON test_executions (parent_id);
+CREATE INDEX test_executions_child_workflow_id_idx ON test_executions (child_workflow_id);
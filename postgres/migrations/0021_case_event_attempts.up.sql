@@ -0,0 +1,43 @@
+-- Case executions are notified with the attempt of their test execution since
+-- retried case executions repeat the events of earlier attempts.
+CREATE OR REPLACE FUNCTION notify_event() RETURNS TRIGGER AS
+$$
+
+DECLARE
+    data         jsonb;
+    notification jsonb;
+
+BEGIN
+
+    -- Convert the old or new row to JSON, based on the kind of action.
+    -- Action = DELETE?             -> OLD row
+    -- Action = INSERT or UPDATE?   -> NEW row
+    IF (TG_OP = 'DELETE') THEN
+        data = to_jsonb(OLD) - 'failure' - 'progress';
+    ELSE
+        data = to_jsonb(NEW) - 'failure' - 'progress';
+    END IF;
+
+    -- Construct the notification as a JSON string.
+    notification = jsonb_build_object(
+            'table', TG_TABLE_NAME,
+            'action', TG_OP,
+            'data', data
+                   );
+
+    IF (TG_TABLE_NAME = 'case_executions') THEN
+        notification = notification || jsonb_build_object(
+                'test_execution_attempt',
+                (SELECT attempt FROM test_executions WHERE id = (data ->> 'test_execution_id')::uuid)
+                                       );
+    END IF;
+
+
+    -- Execute pg_notify(channel, notification)
+    PERFORM pg_notify('execution_events', notification::text);
+
+    -- Result is ignored since this is an AFTER trigger
+    RETURN NULL;
+END;
+
+$$ LANGUAGE plpgsql;
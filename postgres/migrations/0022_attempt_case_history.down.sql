@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS attempt_case_execution_results;
+
+DROP TABLE IF EXISTS attempt_case_execution_inputs;
+
+DROP TABLE IF EXISTS attempt_case_execution_attempts;
@@ -0,0 +1,42 @@
+-- Retries delete stale case executions along with their attempts and payloads,
+-- so they are archived with the case executions of the retried attempt.
+CREATE TABLE attempt_case_execution_attempts
+(
+    test_execution_id UUID    NOT NULL,
+    attempt           INTEGER NOT NULL,
+    case_execution_id INTEGER NOT NULL,
+    FOREIGN KEY (test_execution_id, attempt, case_execution_id) REFERENCES attempt_case_executions (test_execution_id, attempt, id) ON DELETE CASCADE,
+    --
+    case_attempt      INTEGER NOT NULL,
+    start_time        TIMESTAMP,
+    finish_time       TIMESTAMP,
+    error             TEXT,
+    status            TEXT    NOT NULL,
+    failure           JSONB,
+    PRIMARY KEY (test_execution_id, attempt, case_execution_id, case_attempt)
+);
+
+CREATE TABLE attempt_case_execution_inputs
+(
+    test_execution_id UUID    NOT NULL,
+    attempt           INTEGER NOT NULL,
+    case_execution_id INTEGER NOT NULL,
+    FOREIGN KEY (test_execution_id, attempt, case_execution_id) REFERENCES attempt_case_executions (test_execution_id, attempt, id) ON DELETE CASCADE,
+    --
+    index             INTEGER NOT NULL,
+    data              BYTEA   NOT NULL,
+    metadata          JSONB   NOT NULL,
+    PRIMARY KEY (test_execution_id, attempt, case_execution_id, index)
+);
+
+CREATE TABLE attempt_case_execution_results
+(
+    test_execution_id UUID    NOT NULL,
+    attempt           INTEGER NOT NULL,
+    case_execution_id INTEGER NOT NULL,
+    FOREIGN KEY (test_execution_id, attempt, case_execution_id) REFERENCES attempt_case_executions (test_execution_id, attempt, id) ON DELETE CASCADE,
+    --
+    data              BYTEA   NOT NULL,
+    metadata          JSONB   NOT NULL,
+    PRIMARY KEY (test_execution_id, attempt, case_execution_id)
+);
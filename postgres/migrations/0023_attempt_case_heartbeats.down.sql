@@ -0,0 +1,3 @@
+ALTER TABLE attempt_case_executions
+    DROP COLUMN IF EXISTS heartbeat_time,
+    DROP COLUMN IF EXISTS progress;
@@ -0,0 +1,3 @@
+ALTER TABLE attempt_case_executions
+    ADD COLUMN heartbeat_time TIMESTAMP,
+    ADD COLUMN progress       JSONB;
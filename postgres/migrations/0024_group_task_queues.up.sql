@@ -0,0 +1,23 @@
+-- Groups keep the task queue they were created with. Existing groups keep
+-- their legacy task queue, so that workflows already started on it are still
+-- polled by their runners.
+ALTER TABLE groups
+    ADD COLUMN task_queue TEXT;
+
+UPDATE groups
+SET task_queue = context_id || '-' || id;
+
+-- Groups sharing a legacy task queue would receive each other's tests, so they
+-- are given distinct escaped task queues instead.
+UPDATE groups
+SET task_queue = replace(replace(context_id, '%', '%25'), '-', '%2D') || '-' ||
+                 replace(replace(id, '%', '%25'), '-', '%2D')
+WHERE task_queue IN (SELECT task_queue
+                     FROM groups
+                     GROUP BY task_queue
+                     HAVING count(*) > 1);
+
+ALTER TABLE groups
+    ALTER COLUMN task_queue SET NOT NULL;
+
+CREATE INDEX groups_task_queue_idx ON groups (task_queue);
@@ -0,0 +1,127 @@
+-- name: CreateTestExecutionAttempt :one
+INSERT INTO test_execution_attempts (test_execution_id, attempt, schedule_time, start_time, finish_time, error, status,
+                                     failure)
+SELECT id, attempt, schedule_time, start_time, finish_time, error, status, failure
+FROM test_executions
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateAttemptCaseExecutions :exec
+INSERT INTO attempt_case_executions (test_execution_id, attempt, id, case_name, schedule_time, start_time,
+                                     finish_time, error, status, failure, heartbeat_time, progress)
+SELECT ce.test_execution_id,
+       te.attempt,
+       ce.id,
+       ce.case_name,
+       ce.schedule_time,
+       ce.start_time,
+       ce.finish_time,
+       ce.error,
+       ce.status,
+       ce.failure,
+       ce.heartbeat_time,
+       ce.progress
+FROM case_executions ce
+         JOIN test_executions te ON te.id = ce.test_execution_id
+WHERE ce.test_execution_id = $1;
+
+-- name: CreateAttemptCaseExecutionAttempts :exec
+INSERT INTO attempt_case_execution_attempts (test_execution_id, attempt, case_execution_id, case_attempt, start_time,
+                                             finish_time, error, status, failure)
+SELECT a.test_execution_id,
+       te.attempt,
+       a.case_execution_id,
+       a.attempt,
+       a.start_time,
+       a.finish_time,
+       a.error,
+       a.status,
+       a.failure
+FROM case_execution_attempts a
+         JOIN test_executions te ON te.id = a.test_execution_id
+WHERE a.test_execution_id = $1;
+
+-- name: CreateAttemptCaseExecutionInputs :exec
+INSERT INTO attempt_case_execution_inputs (test_execution_id, attempt, case_execution_id, index, data, metadata)
+SELECT i.test_execution_id,
+       te.attempt,
+       i.case_execution_id,
+       i.index,
+       i.data,
+       i.metadata
+FROM case_execution_inputs i
+         JOIN test_executions te ON te.id = i.test_execution_id
+WHERE i.test_execution_id = $1;
+
+-- name: CreateAttemptCaseExecutionResults :exec
+INSERT INTO attempt_case_execution_results (test_execution_id, attempt, case_execution_id, data, metadata)
+SELECT r.test_execution_id,
+       te.attempt,
+       r.case_execution_id,
+       r.data,
+       r.metadata
+FROM case_execution_results r
+         JOIN test_executions te ON te.id = r.test_execution_id
+WHERE r.test_execution_id = $1;
+
+-- name: CreateAttemptLogs :exec
+INSERT INTO attempt_logs (test_execution_id, attempt, id, case_execution_id, level, message, create_time)
+SELECT l.test_execution_id,
+       te.attempt,
+       l.id,
+       l.case_execution_id,
+       l.level,
+       l.message,
+       l.create_time
+FROM logs l
+         JOIN test_executions te ON te.id = l.test_execution_id
+WHERE l.test_execution_id = $1;
+
+-- name: GetTestExecutionAttempt :one
+SELECT *
+FROM test_execution_attempts
+WHERE test_execution_id = $1
+  AND attempt = $2;
+
+-- name: ListTestExecutionAttempts :many
+SELECT *
+FROM test_execution_attempts
+WHERE test_execution_id = $1
+ORDER BY attempt;
+
+-- name: ListAttemptCaseExecutions :many
+SELECT *
+FROM attempt_case_executions
+WHERE test_execution_id = $1
+  AND attempt = $2
+ORDER BY id;
+
+-- name: ListAttemptCaseExecutionAttempts :many
+SELECT *
+FROM attempt_case_execution_attempts
+WHERE test_execution_id = $1
+  AND attempt = $2
+  AND case_execution_id = $3
+ORDER BY case_attempt;
+
+-- name: ListAttemptCaseExecutionInputs :many
+SELECT *
+FROM attempt_case_execution_inputs
+WHERE test_execution_id = $1
+  AND attempt = $2
+  AND case_execution_id = $3
+ORDER BY index;
+
+-- name: GetAttemptCaseExecutionResult :one
+SELECT *
+FROM attempt_case_execution_results
+WHERE test_execution_id = $1
+  AND attempt = $2
+  AND case_execution_id = $3;
+
+-- name: ListAttemptLogs :many
+SELECT *
+FROM attempt_logs
+WHERE test_execution_id = $1
+  AND attempt = $2
+ORDER BY create_time;
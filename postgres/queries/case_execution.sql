@@ -1,39 +1,73 @@
 -- name: CreateCaseExecution :one
-INSERT INTO case_executions (id, test_execution_id, case_name, schedule_time)
-VALUES ($1, $2, $3, $4)
+INSERT INTO case_executions (id, test_execution_id, case_name, schedule_time, status)
+VALUES ($1, $2, $3, $4, 'scheduled')
 ON CONFLICT (id, test_execution_id) DO UPDATE -- safeguard: shouldn't occur in theory
-    SET case_name    = excluded.case_name,
-        schedule_time = excluded.schedule_time,
-        start_time   = null,
-        finish_time  = null,
-        error        = null
+    SET case_name      = excluded.case_name,
+        schedule_time  = excluded.schedule_time,
+        start_time     = null,
+        finish_time    = null,
+        error          = null,
+        failure        = null,
+        heartbeat_time = null,
+        progress       = null,
+        status         = excluded.status
 RETURNING *;
 
 -- name: ResetCaseExecution :one
 UPDATE case_executions
-SET schedule_time = null,
-    start_time   = null,
-    finish_time  = null,
-    error        = null
+SET schedule_time  = null,
+    start_time     = null,
+    finish_time    = null,
+    error          = null,
+    failure        = null,
+    heartbeat_time = null,
+    progress       = null,
+    status         = 'scheduled'
 WHERE id = $1
   AND test_execution_id = $2
 RETURNING *;
 
 -- name: UpdateCaseExecutionStarted :one
 UPDATE case_executions
-SET start_time = $3
+SET start_time     = $3,
+    finish_time    = null,
+    error          = null,
+    failure        = null,
+    heartbeat_time = null,
+    progress       = null,
+    status         = 'running'
 WHERE id = $1
   AND test_execution_id = $2
+  AND status NOT IN ('cancelled', 'timed_out')
+RETURNING *;
+
+-- name: UpdateCaseExecutionHeartbeat :one
+UPDATE case_executions
+SET heartbeat_time = $3,
+    progress       = COALESCE($4, progress)
+WHERE id = $1
+  AND test_execution_id = $2
+  AND status NOT IN ('cancelled', 'timed_out')
 RETURNING *;
 
 -- name: UpdateCaseExecutionFinished :one
 UPDATE case_executions
 SET finish_time = $3,
-    error       = $4
+    error       = $4,
+    status      = $5,
+    failure     = $6
 WHERE id = $1
   AND test_execution_id = $2
+  AND status NOT IN ('cancelled', 'timed_out')
 RETURNING *;
 
+-- name: UpdateOpenCaseExecutionsStopped :exec
+UPDATE case_executions
+SET finish_time = $2,
+    status      = $3
+WHERE test_execution_id = $1
+  AND status IN ('scheduled', 'running');
+
 -- name: DeleteCaseExecution :exec
 DELETE
 FROM case_executions
@@ -51,3 +85,78 @@ SELECT *
 FROM case_executions
 WHERE test_execution_id = $1;
 
+
+-- name: CreateCaseExecutionInput :exec
+INSERT INTO case_execution_inputs (test_execution_id, case_execution_id, index, data, metadata)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: DeleteCaseExecutionInputs :exec
+DELETE
+FROM case_execution_inputs
+WHERE test_execution_id = $1
+  AND case_execution_id = $2;
+
+-- name: ListCaseExecutionInputs :many
+SELECT *
+FROM case_execution_inputs
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+ORDER BY index;
+
+-- name: CreateCaseExecutionResult :exec
+INSERT INTO case_execution_results (test_execution_id, case_execution_id, data, metadata)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (test_execution_id, case_execution_id) DO UPDATE
+    SET data     = excluded.data,
+        metadata = excluded.metadata;
+
+-- name: GetCaseExecutionResult :one
+SELECT *
+FROM case_execution_results
+WHERE test_execution_id = $1
+  AND case_execution_id = $2;
+
+-- name: CreateCaseExecutionAttempt :one
+INSERT INTO case_execution_attempts (test_execution_id, case_execution_id, attempt, start_time, status)
+VALUES ($1, $2, $3, $4, 'running')
+ON CONFLICT (test_execution_id, case_execution_id, attempt) DO UPDATE
+    SET start_time  = excluded.start_time,
+        finish_time = null,
+        error       = null,
+        failure     = null,
+        status      = excluded.status
+RETURNING *;
+
+-- name: UpdatePreviousCaseExecutionAttemptsTimedOut :exec
+UPDATE case_execution_attempts
+SET finish_time = $4,
+    status      = 'timed_out'
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+  AND attempt < $3
+  AND status = 'running';
+
+-- name: UpdateCaseExecutionAttemptFinished :one
+INSERT INTO case_execution_attempts (test_execution_id, case_execution_id, attempt, finish_time, error, status,
+                                     failure)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (test_execution_id, case_execution_id, attempt) DO UPDATE
+    SET finish_time = excluded.finish_time,
+        error       = excluded.error,
+        status      = excluded.status,
+        failure     = excluded.failure
+RETURNING *;
+
+-- name: UpdateOpenCaseExecutionAttemptsStopped :exec
+UPDATE case_execution_attempts
+SET finish_time = $2,
+    status      = $3
+WHERE test_execution_id = $1
+  AND status = 'running';
+
+-- name: ListCaseExecutionAttempts :many
+SELECT *
+FROM case_execution_attempts
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+ORDER BY attempt;
@@ -3,11 +3,27 @@ INSERT INTO contexts (id)
 VALUES ($1);
 
 -- name: ListContexts :many
-SELECT *
-FROM contexts;
+SELECT id
+FROM contexts
+WHERE NOT archived;
+
+
+-- name: ContextExists :one
+SELECT EXISTS (SELECT 1
+               FROM contexts
+               WHERE id = $1);
+
+-- name: IsContextArchived :one
+SELECT archived
+FROM contexts
+WHERE id = $1;
 
+-- name: UpdateContextArchived :exec
+UPDATE contexts
+SET archived = $2
+WHERE id = $1;
 
--- name: ContextExists :exec
-SELECT *
+-- name: DeleteContext :exec
+DELETE
 FROM contexts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
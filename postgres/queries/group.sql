@@ -1,15 +1,61 @@
 -- name: CreateGroup :exec
-INSERT INTO groups (context_id, id)
-VALUES ($1, $2)
-ON CONFLICT (context_id, id) DO NOTHING;
+INSERT INTO groups (context_id, id, task_queue)
+VALUES ($1, $2, $3)
+ON CONFLICT (context_id, id) DO UPDATE
+    SET archived = FALSE;
 
 -- name: ListGroups :many
 SELECT id
 FROM groups
-WHERE context_id = $1;
+WHERE context_id = $1
+  AND (NOT archived OR @include_archived::bool);
+
+-- name: GroupExists :one
+SELECT EXISTS (SELECT 1
+               FROM groups
+               WHERE context_id = $1
+                 AND id = $2);
+
+-- name: GetGroupTaskQueue :one
+SELECT task_queue
+FROM groups
+WHERE context_id = $1
+  AND id = $2;
+
+-- name: TaskQueueExists :one
+SELECT EXISTS (SELECT 1
+               FROM groups
+               WHERE task_queue = $1);
+
+-- name: IsGroupArchived :one
+SELECT archived
+FROM groups
+WHERE context_id = $1
+  AND id = $2;
+
+-- name: UpdateGroupArchived :exec
+UPDATE groups
+SET archived = $3
+WHERE context_id = $1
+  AND id = $2;
 
--- name: GroupExists :exec
-SELECT *
+-- name: DeleteGroup :exec
+DELETE
 FROM groups
 WHERE context_id = $1
   AND id = $2;
+
+-- name: RenameGroup :exec
+UPDATE groups
+SET id = @new_id
+WHERE context_id = @context_id
+  AND id = @id;
+
+-- name: HasActiveTestExecutions :one
+SELECT EXISTS (SELECT 1
+               FROM test_executions te
+                        JOIN tests t ON t.id = te.test_id
+               WHERE t.context_id = @context_id
+                 AND (sqlc.narg('group_id')::text IS NULL OR t.group_id = sqlc.narg('group_id')::text)
+                 AND (sqlc.narg('test_id')::uuid IS NULL OR t.id = sqlc.narg('test_id')::uuid)
+                 AND te.status IN ('scheduled', 'running'));
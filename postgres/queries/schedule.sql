@@ -0,0 +1,58 @@
+-- name: CreateSchedule :one
+INSERT INTO schedules (id, test_id, cron, has_input, next_fire_time)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateScheduleInput :exec
+INSERT INTO schedule_inputs (schedule_id, metadata, data)
+VALUES ($1, $2, $3);
+
+-- name: GetSchedule :one
+SELECT *
+FROM schedules
+WHERE id = $1;
+
+-- name: GetScheduleInput :one
+SELECT *
+FROM schedule_inputs
+WHERE schedule_id = $1;
+
+-- name: ListSchedules :many
+SELECT *
+FROM schedules
+WHERE test_id = $1
+ORDER BY create_time, id;
+
+-- name: ListDueSchedules :many
+SELECT *
+FROM schedules
+WHERE NOT paused
+  AND next_fire_time <= @now::timestamp
+ORDER BY next_fire_time;
+
+-- name: UpdateScheduleFired :one
+UPDATE schedules
+SET last_fire_time = @fire_time,
+    next_fire_time = @next_fire_time
+WHERE id = @id
+  AND NOT paused
+  AND next_fire_time = @fire_time
+RETURNING *;
+
+-- name: UpdateSchedulePaused :one
+UPDATE schedules
+SET paused = TRUE
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateScheduleResumed :one
+UPDATE schedules
+SET paused         = FALSE,
+    next_fire_time = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSchedule :execrows
+DELETE
+FROM schedules
+WHERE id = $1;
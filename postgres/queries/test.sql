@@ -1,9 +1,18 @@
 -- name: CreateTest :one
-INSERT INTO tests (context_id, group_id, id, name, has_input)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO tests (context_id, group_id, id, name, has_input, execution_timeout, run_timeout, input_schema,
+                   description, tags, owner, source_location)
+VALUES (@context_id, @group_id, @id, @name, @has_input, @execution_timeout, @run_timeout, @input_schema,
+        @description, @tags, @owner, @source_location)
 ON CONFLICT (context_id, group_id, name) DO UPDATE
-    SET has_input   = excluded.has_input,
-        create_time = now()
+    SET has_input         = excluded.has_input,
+        execution_timeout = COALESCE(excluded.execution_timeout, tests.execution_timeout),
+        run_timeout       = COALESCE(excluded.run_timeout, tests.run_timeout),
+        input_schema      = COALESCE(excluded.input_schema, tests.input_schema),
+        description       = CASE WHEN @keep_metadata::bool THEN tests.description ELSE excluded.description END,
+        tags              = CASE WHEN @keep_metadata::bool THEN tests.tags ELSE excluded.tags END,
+        owner             = CASE WHEN @keep_metadata::bool THEN tests.owner ELSE excluded.owner END,
+        source_location   = CASE WHEN @keep_metadata::bool THEN tests.source_location ELSE excluded.source_location END,
+        deprecated        = FALSE
 RETURNING *;
 
 -- name: GetTest :one
@@ -20,13 +29,51 @@ WHERE name = $1
 -- name: ListTests :many
 SELECT *
 FROM tests
-WHERE context_id = $1 AND group_id = $2;
+WHERE context_id = $1 AND group_id = $2
+  AND (NOT deprecated OR @include_deprecated::bool)
+  AND tags @> coalesce(@tags::text[], '{}')
+  AND (@owner::text = '' OR owner = @owner);
+
+-- name: SearchTests :many
+SELECT *
+FROM tests
+WHERE (sqlc.narg('name_pattern')::text IS NULL OR name ILIKE sqlc.narg('name_pattern')::text)
+  AND tags @> coalesce(@tags::text[], '{}')
+  AND (@owner::text = '' OR owner = @owner)
+  AND (NOT deprecated OR @include_deprecated::bool)
+  AND (context_id, group_id) IN (SELECT g.context_id, g.id
+                                 FROM groups g
+                                          JOIN contexts c ON c.id = g.context_id
+                                 WHERE NOT g.archived
+                                   AND NOT c.archived)
+  AND (
+    (sqlc.narg('last_create_time')::timestamp IS NULL AND sqlc.narg('last_test_id')::uuid IS NULL)
+        OR (create_time, id) < (sqlc.narg('last_create_time')::timestamp, sqlc.narg('last_test_id')::uuid)
+    )
+ORDER BY create_time DESC, id DESC
+LIMIT (sqlc.narg('page_size')::integer);
+
+-- name: MoveTest :one
+UPDATE tests
+SET group_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeprecateTests :many
+UPDATE tests
+SET deprecated = TRUE
+WHERE context_id = @context_id
+  AND group_id = @group_id
+  AND NOT deprecated
+  AND NOT (name = ANY (@keep_names::text[]))
+RETURNING *;
 
 -- name: CreateTestDefaultInput :exec
-INSERT INTO test_default_inputs (test_id, data)
-VALUES ($1, $2)
+INSERT INTO test_default_inputs (test_id, data, metadata)
+VALUES ($1, $2, $3)
 ON CONFLICT (test_id) DO UPDATE
-    SET data = excluded.data;
+    SET data     = excluded.data,
+        metadata = excluded.metadata;
 
 -- name: GetTestDefaultInput :one
 SELECT *
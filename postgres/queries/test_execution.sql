@@ -1,20 +1,39 @@
 -- name: CreateTestExecution :one
-INSERT INTO test_executions (id, test_id, has_input, schedule_time)
-VALUES ($1, $2, $3, $4)
+INSERT INTO test_executions (id, test_id, has_input, schedule_time, status, execution_timeout, run_timeout,
+                             test_run_id, rerun_of_id, test_version)
+VALUES ($1, $2, $3, $4, 'scheduled', $5, $6, $7, $8, $9)
 ON CONFLICT (id) DO UPDATE
-    SET test_id       = excluded.test_id,
-        has_input     = excluded.has_input,
-        schedule_time = excluded.schedule_time,
-        start_time    = null,
-        finish_time   = null,
-        error         = null
+    SET test_id           = excluded.test_id,
+        has_input         = excluded.has_input,
+        schedule_time     = excluded.schedule_time,
+        start_time        = null,
+        finish_time       = null,
+        error             = null,
+        failure           = null,
+        status            = excluded.status,
+        execution_timeout = excluded.execution_timeout,
+        run_timeout       = excluded.run_timeout,
+        test_run_id       = excluded.test_run_id,
+        rerun_of_id       = excluded.rerun_of_id,
+        test_version      = excluded.test_version,
+        attempt           = test_executions.attempt + 1
 RETURNING *;
 
+-- name: CreateSubTestExecution :exec
+INSERT INTO test_executions (id, test_id, has_input, schedule_time, status, test_version, parent_id,
+                             child_workflow_id, child_workflow_type)
+SELECT @id, p.test_id, false, @schedule_time, 'scheduled', p.test_version, p.id, @child_workflow_id,
+       @child_workflow_type
+FROM test_executions p
+WHERE p.id = @parent_id
+ON CONFLICT (id) DO NOTHING;
+
 -- name: CreateTestExecutionInput :exec
-INSERT INTO test_execution_inputs (test_execution_id, data)
-VALUES ($1, $2)
+INSERT INTO test_execution_inputs (test_execution_id, data, metadata)
+VALUES ($1, $2, $3)
 ON CONFLICT (test_execution_id) DO UPDATE
-    SET data = excluded.data;
+    SET data     = excluded.data,
+        metadata = excluded.metadata;
 
 -- name: GetTestExecutionInput :one
 SELECT *
@@ -25,15 +44,29 @@ WHERE test_execution_id = $1;
 UPDATE test_executions
 SET start_time  = $2,
     finish_time = null,
-    error       = null
+    error       = null,
+    failure     = null,
+    status      = 'running'
 WHERE id = $1
+  AND status NOT IN ('cancelled', 'timed_out')
 RETURNING *;
 
 -- name: UpdateTestExecutionFinished :one
 UPDATE test_executions
 SET finish_time = $2,
-    error       = $3
+    error       = $3,
+    status      = $4,
+    failure     = $5
+WHERE id = $1
+  AND status NOT IN ('cancelled', 'timed_out')
+RETURNING *;
+
+-- name: UpdateTestExecutionStopped :one
+UPDATE test_executions
+SET finish_time = $2,
+    status      = $3
 WHERE id = $1
+  AND status IN ('scheduled', 'running')
 RETURNING *;
 
 -- name: GetTestExecution :one
@@ -45,9 +78,55 @@ WHERE id = $1;
 SELECT *
 FROM test_executions
 WHERE (@test_id = test_id)
+  AND parent_id IS NULL
   AND (
     (sqlc.narg('last_schedule_time')::timestamp IS NULL AND sqlc.narg('last_exec_id')::uuid IS NULL)
         OR (schedule_time, id) < (@last_schedule_time::timestamp, @last_test_execution_id::uuid)
     )
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY (sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('scheduled_after')::timestamp IS NULL OR schedule_time >= sqlc.narg('scheduled_after')::timestamp)
+  AND (sqlc.narg('scheduled_before')::timestamp IS NULL OR schedule_time < sqlc.narg('scheduled_before')::timestamp)
+  AND (sqlc.narg('error_contains')::text IS NULL OR strpos(lower(error), lower(sqlc.narg('error_contains')::text)) > 0)
 ORDER BY schedule_time DESC, id DESC
 LIMIT (sqlc.narg('page_size')::integer);
+
+-- name: ListExpiredTestExecutions :many
+SELECT *
+FROM test_executions
+WHERE status IN ('scheduled', 'running')
+  AND LEAST(schedule_time + execution_timeout, schedule_time + run_timeout) <= @now::timestamp
+ORDER BY schedule_time;
+
+-- name: ListRecentTestExecutions :many
+SELECT te.*, t.context_id, t.group_id, t.name AS test_name
+FROM test_executions te
+         INNER JOIN tests t ON t.id = te.test_id
+WHERE t.context_id = @context_id
+  AND te.parent_id IS NULL
+  AND (sqlc.narg('group_id')::text IS NULL OR t.group_id = sqlc.narg('group_id')::text)
+  AND (
+    (sqlc.narg('last_schedule_time')::timestamp IS NULL AND sqlc.narg('last_test_execution_id')::uuid IS NULL)
+        OR (te.schedule_time, te.id) <
+           (sqlc.narg('last_schedule_time')::timestamp, sqlc.narg('last_test_execution_id')::uuid)
+    )
+ORDER BY te.schedule_time DESC, te.id DESC
+LIMIT (sqlc.narg('page_size')::integer);
+
+-- name: ListTestRunExecutions :many
+SELECT *
+FROM test_executions
+WHERE test_run_id = $1
+ORDER BY schedule_time, id;
+
+-- name: GetSubTestExecution :one
+SELECT *
+FROM test_executions
+WHERE child_workflow_id = $1
+ORDER BY schedule_time DESC, id DESC
+LIMIT 1;
+
+-- name: ListSubTestExecutions :many
+SELECT *
+FROM test_executions
+WHERE parent_id = $1
+ORDER BY schedule_time, id;
@@ -0,0 +1,21 @@
+-- name: CreateTestRun :one
+INSERT INTO test_runs (id, context_id, group_id)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetTestRun :one
+SELECT *
+FROM test_runs
+WHERE id = $1;
+
+-- name: DeleteGroupTestRuns :exec
+DELETE
+FROM test_runs
+WHERE context_id = $1
+  AND group_id = $2;
+
+-- name: RenameTestRunsGroup :exec
+UPDATE test_runs
+SET group_id = @new_group_id
+WHERE context_id = @context_id
+  AND group_id = @group_id;
@@ -0,0 +1,30 @@
+-- name: CreateTestVersion :one
+INSERT INTO test_versions (test_id, version, has_input, default_input_metadata, default_input_data,
+                           execution_timeout, run_timeout, input_schema)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetTestVersion :one
+SELECT *
+FROM test_versions
+WHERE test_id = $1
+  AND version = $2;
+
+-- name: GetLatestTestVersion :one
+SELECT *
+FROM test_versions
+WHERE test_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: ListTestVersions :many
+SELECT *
+FROM test_versions
+WHERE test_id = $1
+ORDER BY version;
+
+-- name: UpdateTestVersion :one
+UPDATE tests
+SET version = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/annexsh/annex/postgres/sqlc"
+
+	"github.com/annexsh/annex/test"
+)
+
+var (
+	_ test.ScheduleReader = (*ScheduleReader)(nil)
+	_ test.ScheduleWriter = (*ScheduleWriter)(nil)
+)
+
+type ScheduleReader struct {
+	db *DB
+}
+
+func NewScheduleReader(db *DB) *ScheduleReader {
+	return &ScheduleReader{db: db}
+}
+
+func (s *ScheduleReader) GetSchedule(ctx context.Context, id uuid.UUID) (*test.Schedule, error) {
+	schedule, err := s.db.GetSchedule(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorScheduleNotFound
+		}
+		return nil, err
+	}
+	return marshalSchedule(schedule), nil
+}
+
+func (s *ScheduleReader) GetScheduleInput(ctx context.Context, id uuid.UUID) (*test.Payload, error) {
+	input, err := s.db.GetScheduleInput(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorScheduleNotFound
+		}
+		return nil, err
+	}
+	payload, err := marshalScheduleInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return test.DecodePayload(s.db.codec, payload)
+}
+
+func (s *ScheduleReader) ListSchedules(ctx context.Context, testID uuid.UUID) (test.ScheduleList, error) {
+	schedules, err := s.db.ListSchedules(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	return marshalSchedules(schedules), nil
+}
+
+func (s *ScheduleReader) ListDueSchedules(ctx context.Context, now time.Time) (test.ScheduleList, error) {
+	schedules, err := s.db.ListDueSchedules(ctx, sqlc.NewTimestamp(now))
+	if err != nil {
+		return nil, err
+	}
+	return marshalSchedules(schedules), nil
+}
+
+type ScheduleWriter struct {
+	db *DB
+}
+
+func NewScheduleWriter(db *DB) *ScheduleWriter {
+	return &ScheduleWriter{db: db}
+}
+
+func (s *ScheduleWriter) CreateSchedule(ctx context.Context, definition *test.ScheduleDefinition) (*test.Schedule, error) {
+	var schedule *sqlc.Schedule
+
+	err := s.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		var err error
+		schedule, err = querier.CreateSchedule(ctx, sqlc.CreateScheduleParams{
+			ID:           definition.ID,
+			TestID:       definition.TestID,
+			Cron:         definition.Cron,
+			HasInput:     definition.Input != nil,
+			NextFireTime: sqlc.NewTimestamp(definition.NextFireTime),
+		})
+		if err != nil {
+			return err
+		}
+
+		if definition.Input != nil {
+			input, err := test.EncodePayload(s.db.codec, definition.Input)
+			if err != nil {
+				return err
+			}
+			metadata, err := json.Marshal(input.Metadata)
+			if err != nil {
+				return err
+			}
+			return querier.CreateScheduleInput(ctx, sqlc.CreateScheduleInputParams{
+				ScheduleID: definition.ID,
+				Metadata:   metadata,
+				Data:       input.Data,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalSchedule(schedule), nil
+}
+
+func (s *ScheduleWriter) UpdateFiredSchedule(ctx context.Context, fired *test.FiredSchedule) (*test.Schedule, error) {
+	schedule, err := s.db.UpdateScheduleFired(ctx, sqlc.UpdateScheduleFiredParams{
+		ID:           fired.ID,
+		FireTime:     sqlc.NewTimestamp(fired.FireTime),
+		NextFireTime: sqlc.NewTimestamp(fired.NextFireTime),
+	})
+	if err != nil {
+		// Paused, deleted or already fired by another server
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorScheduleNotDue
+		}
+		return nil, err
+	}
+	return marshalSchedule(schedule), nil
+}
+
+func (s *ScheduleWriter) UpdatePausedSchedule(ctx context.Context, id uuid.UUID) (*test.Schedule, error) {
+	schedule, err := s.db.UpdateSchedulePaused(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorScheduleNotFound
+		}
+		return nil, err
+	}
+	return marshalSchedule(schedule), nil
+}
+
+func (s *ScheduleWriter) UpdateResumedSchedule(ctx context.Context, resumed *test.ResumedSchedule) (*test.Schedule, error) {
+	schedule, err := s.db.UpdateScheduleResumed(ctx, sqlc.UpdateScheduleResumedParams{
+		ID:           resumed.ID,
+		NextFireTime: sqlc.NewTimestamp(resumed.NextFireTime),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorScheduleNotFound
+		}
+		return nil, err
+	}
+	return marshalSchedule(schedule), nil
+}
+
+func (s *ScheduleWriter) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.db.DeleteSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return test.ErrorScheduleNotFound
+	}
+	return nil
+}
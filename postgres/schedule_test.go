@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/test"
+)
+
+func TestSchedule_notFound(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewScheduleReader(db)
+	w := NewScheduleWriter(db)
+
+	id := uuid.New()
+
+	_, err := r.GetSchedule(ctx, id)
+	require.ErrorIs(t, err, test.ErrorScheduleNotFound)
+
+	_, err = r.GetScheduleInput(ctx, id)
+	require.ErrorIs(t, err, test.ErrorScheduleNotFound)
+
+	_, err = w.UpdatePausedSchedule(ctx, id)
+	require.ErrorIs(t, err, test.ErrorScheduleNotFound)
+
+	_, err = w.UpdateResumedSchedule(ctx, &test.ResumedSchedule{
+		ID:           id,
+		NextFireTime: time.Now().UTC(),
+	})
+	require.ErrorIs(t, err, test.ErrorScheduleNotFound)
+
+	err = w.DeleteSchedule(ctx, id)
+	require.ErrorIs(t, err, test.ErrorScheduleNotFound)
+}
@@ -7,20 +7,25 @@ package sqlc
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/annexsh/annex/test"
 )
 
 const createCaseExecution = `-- name: CreateCaseExecution :one
-INSERT INTO case_executions (id, test_execution_id, case_name, schedule_time)
-VALUES ($1, $2, $3, $4)
+INSERT INTO case_executions (id, test_execution_id, case_name, schedule_time, status)
+VALUES ($1, $2, $3, $4, 'scheduled')
 ON CONFLICT (id, test_execution_id) DO UPDATE -- safeguard: shouldn't occur in theory
-    SET case_name    = excluded.case_name,
-        schedule_time = excluded.schedule_time,
-        start_time   = null,
-        finish_time  = null,
-        error        = null
-RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error
+    SET case_name      = excluded.case_name,
+        schedule_time  = excluded.schedule_time,
+        start_time     = null,
+        finish_time    = null,
+        error          = null,
+        failure        = null,
+        heartbeat_time = null,
+        progress       = null,
+        status         = excluded.status
+RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error, status, failure, heartbeat_time, progress
 `
 
 type CreateCaseExecutionParams struct {
@@ -46,10 +51,103 @@ func (q *Queries) CreateCaseExecution(ctx context.Context, arg CreateCaseExecuti
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.Failure,
+		&i.HeartbeatTime,
+		&i.Progress,
+	)
+	return &i, err
+}
+
+const createCaseExecutionAttempt = `-- name: CreateCaseExecutionAttempt :one
+INSERT INTO case_execution_attempts (test_execution_id, case_execution_id, attempt, start_time, status)
+VALUES ($1, $2, $3, $4, 'running')
+ON CONFLICT (test_execution_id, case_execution_id, attempt) DO UPDATE
+    SET start_time  = excluded.start_time,
+        finish_time = null,
+        error       = null,
+        failure     = null,
+        status      = excluded.status
+RETURNING test_execution_id, case_execution_id, attempt, start_time, finish_time, error, status, failure
+`
+
+type CreateCaseExecutionAttemptParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	StartTime       Timestamp            `json:"start_time"`
+}
+
+func (q *Queries) CreateCaseExecutionAttempt(ctx context.Context, arg CreateCaseExecutionAttemptParams) (*CaseExecutionAttempt, error) {
+	row := q.db.QueryRow(ctx, createCaseExecutionAttempt,
+		arg.TestExecutionID,
+		arg.CaseExecutionID,
+		arg.Attempt,
+		arg.StartTime,
+	)
+	var i CaseExecutionAttempt
+	err := row.Scan(
+		&i.TestExecutionID,
+		&i.CaseExecutionID,
+		&i.Attempt,
+		&i.StartTime,
+		&i.FinishTime,
+		&i.Error,
+		&i.Status,
+		&i.Failure,
 	)
 	return &i, err
 }
 
+const createCaseExecutionInput = `-- name: CreateCaseExecutionInput :exec
+INSERT INTO case_execution_inputs (test_execution_id, case_execution_id, index, data, metadata)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateCaseExecutionInputParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Index           int32                `json:"index"`
+	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
+}
+
+func (q *Queries) CreateCaseExecutionInput(ctx context.Context, arg CreateCaseExecutionInputParams) error {
+	_, err := q.db.Exec(ctx, createCaseExecutionInput,
+		arg.TestExecutionID,
+		arg.CaseExecutionID,
+		arg.Index,
+		arg.Data,
+		arg.Metadata,
+	)
+	return err
+}
+
+const createCaseExecutionResult = `-- name: CreateCaseExecutionResult :exec
+INSERT INTO case_execution_results (test_execution_id, case_execution_id, data, metadata)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (test_execution_id, case_execution_id) DO UPDATE
+    SET data     = excluded.data,
+        metadata = excluded.metadata
+`
+
+type CreateCaseExecutionResultParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
+}
+
+func (q *Queries) CreateCaseExecutionResult(ctx context.Context, arg CreateCaseExecutionResultParams) error {
+	_, err := q.db.Exec(ctx, createCaseExecutionResult,
+		arg.TestExecutionID,
+		arg.CaseExecutionID,
+		arg.Data,
+		arg.Metadata,
+	)
+	return err
+}
+
 const deleteCaseExecution = `-- name: DeleteCaseExecution :exec
 DELETE
 FROM case_executions
@@ -67,8 +165,25 @@ func (q *Queries) DeleteCaseExecution(ctx context.Context, arg DeleteCaseExecuti
 	return err
 }
 
+const deleteCaseExecutionInputs = `-- name: DeleteCaseExecutionInputs :exec
+DELETE
+FROM case_execution_inputs
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+`
+
+type DeleteCaseExecutionInputsParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+}
+
+func (q *Queries) DeleteCaseExecutionInputs(ctx context.Context, arg DeleteCaseExecutionInputsParams) error {
+	_, err := q.db.Exec(ctx, deleteCaseExecutionInputs, arg.TestExecutionID, arg.CaseExecutionID)
+	return err
+}
+
 const getCaseExecution = `-- name: GetCaseExecution :one
-SELECT id, test_execution_id, case_name, schedule_time, start_time, finish_time, error
+SELECT id, test_execution_id, case_name, schedule_time, start_time, finish_time, error, status, failure, heartbeat_time, progress
 FROM case_executions
 WHERE id = $1
   AND test_execution_id = $2
@@ -90,12 +205,121 @@ func (q *Queries) GetCaseExecution(ctx context.Context, arg GetCaseExecutionPara
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.Failure,
+		&i.HeartbeatTime,
+		&i.Progress,
+	)
+	return &i, err
+}
+
+const getCaseExecutionResult = `-- name: GetCaseExecutionResult :one
+SELECT test_execution_id, case_execution_id, data, metadata
+FROM case_execution_results
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+`
+
+type GetCaseExecutionResultParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+}
+
+func (q *Queries) GetCaseExecutionResult(ctx context.Context, arg GetCaseExecutionResultParams) (*CaseExecutionResult, error) {
+	row := q.db.QueryRow(ctx, getCaseExecutionResult, arg.TestExecutionID, arg.CaseExecutionID)
+	var i CaseExecutionResult
+	err := row.Scan(
+		&i.TestExecutionID,
+		&i.CaseExecutionID,
+		&i.Data,
+		&i.Metadata,
 	)
 	return &i, err
 }
 
+const listCaseExecutionAttempts = `-- name: ListCaseExecutionAttempts :many
+SELECT test_execution_id, case_execution_id, attempt, start_time, finish_time, error, status, failure
+FROM case_execution_attempts
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+ORDER BY attempt
+`
+
+type ListCaseExecutionAttemptsParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+}
+
+func (q *Queries) ListCaseExecutionAttempts(ctx context.Context, arg ListCaseExecutionAttemptsParams) ([]*CaseExecutionAttempt, error) {
+	rows, err := q.db.Query(ctx, listCaseExecutionAttempts, arg.TestExecutionID, arg.CaseExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CaseExecutionAttempt
+	for rows.Next() {
+		var i CaseExecutionAttempt
+		if err := rows.Scan(
+			&i.TestExecutionID,
+			&i.CaseExecutionID,
+			&i.Attempt,
+			&i.StartTime,
+			&i.FinishTime,
+			&i.Error,
+			&i.Status,
+			&i.Failure,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCaseExecutionInputs = `-- name: ListCaseExecutionInputs :many
+SELECT test_execution_id, case_execution_id, index, data, metadata
+FROM case_execution_inputs
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+ORDER BY index
+`
+
+type ListCaseExecutionInputsParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+}
+
+func (q *Queries) ListCaseExecutionInputs(ctx context.Context, arg ListCaseExecutionInputsParams) ([]*CaseExecutionInput, error) {
+	rows, err := q.db.Query(ctx, listCaseExecutionInputs, arg.TestExecutionID, arg.CaseExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CaseExecutionInput
+	for rows.Next() {
+		var i CaseExecutionInput
+		if err := rows.Scan(
+			&i.TestExecutionID,
+			&i.CaseExecutionID,
+			&i.Index,
+			&i.Data,
+			&i.Metadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCaseExecutions = `-- name: ListCaseExecutions :many
-SELECT id, test_execution_id, case_name, schedule_time, start_time, finish_time, error
+SELECT id, test_execution_id, case_name, schedule_time, start_time, finish_time, error, status, failure, heartbeat_time, progress
 FROM case_executions
 WHERE test_execution_id = $1
 `
@@ -117,6 +341,10 @@ func (q *Queries) ListCaseExecutions(ctx context.Context, testExecutionID test.T
 			&i.StartTime,
 			&i.FinishTime,
 			&i.Error,
+			&i.Status,
+			&i.Failure,
+			&i.HeartbeatTime,
+			&i.Progress,
 		); err != nil {
 			return nil, err
 		}
@@ -130,13 +358,17 @@ func (q *Queries) ListCaseExecutions(ctx context.Context, testExecutionID test.T
 
 const resetCaseExecution = `-- name: ResetCaseExecution :one
 UPDATE case_executions
-SET schedule_time = null,
-    start_time   = null,
-    finish_time  = null,
-    error        = null
+SET schedule_time  = null,
+    start_time     = null,
+    finish_time    = null,
+    error          = null,
+    failure        = null,
+    heartbeat_time = null,
+    progress       = null,
+    status         = 'scheduled'
 WHERE id = $1
   AND test_execution_id = $2
-RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error
+RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error, status, failure, heartbeat_time, progress
 `
 
 type ResetCaseExecutionParams struct {
@@ -155,6 +387,56 @@ func (q *Queries) ResetCaseExecution(ctx context.Context, arg ResetCaseExecution
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.Failure,
+		&i.HeartbeatTime,
+		&i.Progress,
+	)
+	return &i, err
+}
+
+const updateCaseExecutionAttemptFinished = `-- name: UpdateCaseExecutionAttemptFinished :one
+INSERT INTO case_execution_attempts (test_execution_id, case_execution_id, attempt, finish_time, error, status,
+                                     failure)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (test_execution_id, case_execution_id, attempt) DO UPDATE
+    SET finish_time = excluded.finish_time,
+        error       = excluded.error,
+        status      = excluded.status,
+        failure     = excluded.failure
+RETURNING test_execution_id, case_execution_id, attempt, start_time, finish_time, error, status, failure
+`
+
+type UpdateCaseExecutionAttemptFinishedParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	FinishTime      Timestamp            `json:"finish_time"`
+	Error           *string              `json:"error"`
+	Status          test.ExecutionStatus `json:"status"`
+	Failure         json.RawMessage      `json:"failure"`
+}
+
+func (q *Queries) UpdateCaseExecutionAttemptFinished(ctx context.Context, arg UpdateCaseExecutionAttemptFinishedParams) (*CaseExecutionAttempt, error) {
+	row := q.db.QueryRow(ctx, updateCaseExecutionAttemptFinished,
+		arg.TestExecutionID,
+		arg.CaseExecutionID,
+		arg.Attempt,
+		arg.FinishTime,
+		arg.Error,
+		arg.Status,
+		arg.Failure,
+	)
+	var i CaseExecutionAttempt
+	err := row.Scan(
+		&i.TestExecutionID,
+		&i.CaseExecutionID,
+		&i.Attempt,
+		&i.StartTime,
+		&i.FinishTime,
+		&i.Error,
+		&i.Status,
+		&i.Failure,
 	)
 	return &i, err
 }
@@ -162,10 +444,13 @@ func (q *Queries) ResetCaseExecution(ctx context.Context, arg ResetCaseExecution
 const updateCaseExecutionFinished = `-- name: UpdateCaseExecutionFinished :one
 UPDATE case_executions
 SET finish_time = $3,
-    error       = $4
+    error       = $4,
+    status      = $5,
+    failure     = $6
 WHERE id = $1
   AND test_execution_id = $2
-RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error
+  AND status NOT IN ('cancelled', 'timed_out')
+RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error, status, failure, heartbeat_time, progress
 `
 
 type UpdateCaseExecutionFinishedParams struct {
@@ -173,6 +458,8 @@ type UpdateCaseExecutionFinishedParams struct {
 	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
 	FinishTime      Timestamp            `json:"finish_time"`
 	Error           *string              `json:"error"`
+	Status          test.ExecutionStatus `json:"status"`
+	Failure         json.RawMessage      `json:"failure"`
 }
 
 func (q *Queries) UpdateCaseExecutionFinished(ctx context.Context, arg UpdateCaseExecutionFinishedParams) (*CaseExecution, error) {
@@ -181,6 +468,8 @@ func (q *Queries) UpdateCaseExecutionFinished(ctx context.Context, arg UpdateCas
 		arg.TestExecutionID,
 		arg.FinishTime,
 		arg.Error,
+		arg.Status,
+		arg.Failure,
 	)
 	var i CaseExecution
 	err := row.Scan(
@@ -191,16 +480,68 @@ func (q *Queries) UpdateCaseExecutionFinished(ctx context.Context, arg UpdateCas
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.Failure,
+		&i.HeartbeatTime,
+		&i.Progress,
+	)
+	return &i, err
+}
+
+const updateCaseExecutionHeartbeat = `-- name: UpdateCaseExecutionHeartbeat :one
+UPDATE case_executions
+SET heartbeat_time = $3,
+    progress       = COALESCE($4, progress)
+WHERE id = $1
+  AND test_execution_id = $2
+  AND status NOT IN ('cancelled', 'timed_out')
+RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error, status, failure, heartbeat_time, progress
+`
+
+type UpdateCaseExecutionHeartbeatParams struct {
+	ID              test.CaseExecutionID `json:"id"`
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	HeartbeatTime   Timestamp            `json:"heartbeat_time"`
+	Progress        json.RawMessage      `json:"progress"`
+}
+
+func (q *Queries) UpdateCaseExecutionHeartbeat(ctx context.Context, arg UpdateCaseExecutionHeartbeatParams) (*CaseExecution, error) {
+	row := q.db.QueryRow(ctx, updateCaseExecutionHeartbeat,
+		arg.ID,
+		arg.TestExecutionID,
+		arg.HeartbeatTime,
+		arg.Progress,
+	)
+	var i CaseExecution
+	err := row.Scan(
+		&i.ID,
+		&i.TestExecutionID,
+		&i.CaseName,
+		&i.ScheduleTime,
+		&i.StartTime,
+		&i.FinishTime,
+		&i.Error,
+		&i.Status,
+		&i.Failure,
+		&i.HeartbeatTime,
+		&i.Progress,
 	)
 	return &i, err
 }
 
 const updateCaseExecutionStarted = `-- name: UpdateCaseExecutionStarted :one
 UPDATE case_executions
-SET start_time = $3
+SET start_time     = $3,
+    finish_time    = null,
+    error          = null,
+    failure        = null,
+    heartbeat_time = null,
+    progress       = null,
+    status         = 'running'
 WHERE id = $1
   AND test_execution_id = $2
-RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error
+  AND status NOT IN ('cancelled', 'timed_out')
+RETURNING id, test_execution_id, case_name, schedule_time, start_time, finish_time, error, status, failure, heartbeat_time, progress
 `
 
 type UpdateCaseExecutionStartedParams struct {
@@ -220,6 +561,75 @@ func (q *Queries) UpdateCaseExecutionStarted(ctx context.Context, arg UpdateCase
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.Failure,
+		&i.HeartbeatTime,
+		&i.Progress,
 	)
 	return &i, err
 }
+
+const updateOpenCaseExecutionAttemptsStopped = `-- name: UpdateOpenCaseExecutionAttemptsStopped :exec
+UPDATE case_execution_attempts
+SET finish_time = $2,
+    status      = $3
+WHERE test_execution_id = $1
+  AND status = 'running'
+`
+
+type UpdateOpenCaseExecutionAttemptsStoppedParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	FinishTime      Timestamp            `json:"finish_time"`
+	Status          test.ExecutionStatus `json:"status"`
+}
+
+func (q *Queries) UpdateOpenCaseExecutionAttemptsStopped(ctx context.Context, arg UpdateOpenCaseExecutionAttemptsStoppedParams) error {
+	_, err := q.db.Exec(ctx, updateOpenCaseExecutionAttemptsStopped, arg.TestExecutionID, arg.FinishTime, arg.Status)
+	return err
+}
+
+const updateOpenCaseExecutionsStopped = `-- name: UpdateOpenCaseExecutionsStopped :exec
+UPDATE case_executions
+SET finish_time = $2,
+    status      = $3
+WHERE test_execution_id = $1
+  AND status IN ('scheduled', 'running')
+`
+
+type UpdateOpenCaseExecutionsStoppedParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	FinishTime      Timestamp            `json:"finish_time"`
+	Status          test.ExecutionStatus `json:"status"`
+}
+
+func (q *Queries) UpdateOpenCaseExecutionsStopped(ctx context.Context, arg UpdateOpenCaseExecutionsStoppedParams) error {
+	_, err := q.db.Exec(ctx, updateOpenCaseExecutionsStopped, arg.TestExecutionID, arg.FinishTime, arg.Status)
+	return err
+}
+
+const updatePreviousCaseExecutionAttemptsTimedOut = `-- name: UpdatePreviousCaseExecutionAttemptsTimedOut :exec
+UPDATE case_execution_attempts
+SET finish_time = $4,
+    status      = 'timed_out'
+WHERE test_execution_id = $1
+  AND case_execution_id = $2
+  AND attempt < $3
+  AND status = 'running'
+`
+
+type UpdatePreviousCaseExecutionAttemptsTimedOutParams struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	FinishTime      Timestamp            `json:"finish_time"`
+}
+
+func (q *Queries) UpdatePreviousCaseExecutionAttemptsTimedOut(ctx context.Context, arg UpdatePreviousCaseExecutionAttemptsTimedOutParams) error {
+	_, err := q.db.Exec(ctx, updatePreviousCaseExecutionAttemptsTimedOut,
+		arg.TestExecutionID,
+		arg.CaseExecutionID,
+		arg.Attempt,
+		arg.FinishTime,
+	)
+	return err
+}
@@ -9,15 +9,17 @@ import (
 	"context"
 )
 
-const contextExists = `-- name: ContextExists :exec
-SELECT id
-FROM contexts
-WHERE id = $1
+const contextExists = `-- name: ContextExists :one
+SELECT EXISTS (SELECT 1
+               FROM contexts
+               WHERE id = $1)
 `
 
-func (q *Queries) ContextExists(ctx context.Context, id string) error {
-	_, err := q.db.Exec(ctx, contextExists, id)
-	return err
+func (q *Queries) ContextExists(ctx context.Context, id string) (bool, error) {
+	row := q.db.QueryRow(ctx, contextExists, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
 }
 
 const createContext = `-- name: CreateContext :exec
@@ -30,9 +32,34 @@ func (q *Queries) CreateContext(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteContext = `-- name: DeleteContext :exec
+DELETE
+FROM contexts
+WHERE id = $1
+`
+
+func (q *Queries) DeleteContext(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, deleteContext, id)
+	return err
+}
+
+const isContextArchived = `-- name: IsContextArchived :one
+SELECT archived
+FROM contexts
+WHERE id = $1
+`
+
+func (q *Queries) IsContextArchived(ctx context.Context, id string) (bool, error) {
+	row := q.db.QueryRow(ctx, isContextArchived, id)
+	var archived bool
+	err := row.Scan(&archived)
+	return archived, err
+}
+
 const listContexts = `-- name: ListContexts :many
 SELECT id
 FROM contexts
+WHERE NOT archived
 `
 
 func (q *Queries) ListContexts(ctx context.Context) ([]string, error) {
@@ -54,3 +81,19 @@ func (q *Queries) ListContexts(ctx context.Context) ([]string, error) {
 	}
 	return items, nil
 }
+
+const updateContextArchived = `-- name: UpdateContextArchived :exec
+UPDATE contexts
+SET archived = $2
+WHERE id = $1
+`
+
+type UpdateContextArchivedParams struct {
+	ID       string `json:"id"`
+	Archived bool   `json:"archived"`
+}
+
+func (q *Queries) UpdateContextArchived(ctx context.Context, arg UpdateContextArchivedParams) error {
+	_, err := q.db.Exec(ctx, updateContextArchived, arg.ID, arg.Archived)
+	return err
+}
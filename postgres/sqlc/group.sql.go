@@ -7,49 +7,139 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
 const createGroup = `-- name: CreateGroup :exec
-INSERT INTO groups (context_id, id)
-VALUES ($1, $2)
-ON CONFLICT (context_id, id) DO NOTHING
+INSERT INTO groups (context_id, id, task_queue)
+VALUES ($1, $2, $3)
+ON CONFLICT (context_id, id) DO UPDATE
+    SET archived = FALSE
 `
 
 type CreateGroupParams struct {
 	ContextID string `json:"context_id"`
 	ID        string `json:"id"`
+	TaskQueue string `json:"task_queue"`
 }
 
 func (q *Queries) CreateGroup(ctx context.Context, arg CreateGroupParams) error {
-	_, err := q.db.Exec(ctx, createGroup, arg.ContextID, arg.ID)
+	_, err := q.db.Exec(ctx, createGroup, arg.ContextID, arg.ID, arg.TaskQueue)
 	return err
 }
 
-const groupExists = `-- name: GroupExists :exec
-SELECT context_id, id
+const deleteGroup = `-- name: DeleteGroup :exec
+DELETE
 FROM groups
 WHERE context_id = $1
   AND id = $2
 `
 
-type GroupExistsParams struct {
+type DeleteGroupParams struct {
 	ContextID string `json:"context_id"`
 	ID        string `json:"id"`
 }
 
-func (q *Queries) GroupExists(ctx context.Context, arg GroupExistsParams) error {
-	_, err := q.db.Exec(ctx, groupExists, arg.ContextID, arg.ID)
+func (q *Queries) DeleteGroup(ctx context.Context, arg DeleteGroupParams) error {
+	_, err := q.db.Exec(ctx, deleteGroup, arg.ContextID, arg.ID)
 	return err
 }
 
+const getGroupTaskQueue = `-- name: GetGroupTaskQueue :one
+SELECT task_queue
+FROM groups
+WHERE context_id = $1
+  AND id = $2
+`
+
+type GetGroupTaskQueueParams struct {
+	ContextID string `json:"context_id"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) GetGroupTaskQueue(ctx context.Context, arg GetGroupTaskQueueParams) (string, error) {
+	row := q.db.QueryRow(ctx, getGroupTaskQueue, arg.ContextID, arg.ID)
+	var task_queue string
+	err := row.Scan(&task_queue)
+	return task_queue, err
+}
+
+const groupExists = `-- name: GroupExists :one
+SELECT EXISTS (SELECT 1
+               FROM groups
+               WHERE context_id = $1
+                 AND id = $2)
+`
+
+type GroupExistsParams struct {
+	ContextID string `json:"context_id"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) GroupExists(ctx context.Context, arg GroupExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, groupExists, arg.ContextID, arg.ID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const hasActiveTestExecutions = `-- name: HasActiveTestExecutions :one
+SELECT EXISTS (SELECT 1
+               FROM test_executions te
+                        JOIN tests t ON t.id = te.test_id
+               WHERE t.context_id = $1
+                 AND ($2::text IS NULL OR t.group_id = $2::text)
+                 AND ($3::uuid IS NULL OR t.id = $3::uuid)
+                 AND te.status IN ('scheduled', 'running'))
+`
+
+type HasActiveTestExecutionsParams struct {
+	ContextID string     `json:"context_id"`
+	GroupID   *string    `json:"group_id"`
+	TestID    *uuid.UUID `json:"test_id"`
+}
+
+func (q *Queries) HasActiveTestExecutions(ctx context.Context, arg HasActiveTestExecutionsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasActiveTestExecutions, arg.ContextID, arg.GroupID, arg.TestID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isGroupArchived = `-- name: IsGroupArchived :one
+SELECT archived
+FROM groups
+WHERE context_id = $1
+  AND id = $2
+`
+
+type IsGroupArchivedParams struct {
+	ContextID string `json:"context_id"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) IsGroupArchived(ctx context.Context, arg IsGroupArchivedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isGroupArchived, arg.ContextID, arg.ID)
+	var archived bool
+	err := row.Scan(&archived)
+	return archived, err
+}
+
 const listGroups = `-- name: ListGroups :many
 SELECT id
 FROM groups
 WHERE context_id = $1
+  AND (NOT archived OR $2::bool)
 `
 
-func (q *Queries) ListGroups(ctx context.Context, contextID string) ([]string, error) {
-	rows, err := q.db.Query(ctx, listGroups, contextID)
+type ListGroupsParams struct {
+	ContextID       string `json:"context_id"`
+	IncludeArchived bool   `json:"include_archived"`
+}
+
+func (q *Queries) ListGroups(ctx context.Context, arg ListGroupsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listGroups, arg.ContextID, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +157,52 @@ func (q *Queries) ListGroups(ctx context.Context, contextID string) ([]string, e
 	}
 	return items, nil
 }
+
+const renameGroup = `-- name: RenameGroup :exec
+UPDATE groups
+SET id = $1
+WHERE context_id = $2
+  AND id = $3
+`
+
+type RenameGroupParams struct {
+	NewID     string `json:"new_id"`
+	ContextID string `json:"context_id"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) RenameGroup(ctx context.Context, arg RenameGroupParams) error {
+	_, err := q.db.Exec(ctx, renameGroup, arg.NewID, arg.ContextID, arg.ID)
+	return err
+}
+
+const taskQueueExists = `-- name: TaskQueueExists :one
+SELECT EXISTS (SELECT 1
+               FROM groups
+               WHERE task_queue = $1)
+`
+
+func (q *Queries) TaskQueueExists(ctx context.Context, taskQueue string) (bool, error) {
+	row := q.db.QueryRow(ctx, taskQueueExists, taskQueue)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const updateGroupArchived = `-- name: UpdateGroupArchived :exec
+UPDATE groups
+SET archived = $3
+WHERE context_id = $1
+  AND id = $2
+`
+
+type UpdateGroupArchivedParams struct {
+	ContextID string `json:"context_id"`
+	ID        string `json:"id"`
+	Archived  bool   `json:"archived"`
+}
+
+func (q *Queries) UpdateGroupArchived(ctx context.Context, arg UpdateGroupArchivedParams) error {
+	_, err := q.db.Exec(ctx, updateGroupArchived, arg.ContextID, arg.ID, arg.Archived)
+	return err
+}
@@ -5,10 +5,66 @@
 package sqlc
 
 import (
+	"encoding/json"
+
 	"github.com/annexsh/annex/test"
 	"github.com/google/uuid"
 )
 
+type AttemptCaseExecution struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	ID              test.CaseExecutionID `json:"id"`
+	CaseName        string               `json:"case_name"`
+	ScheduleTime    Timestamp            `json:"schedule_time"`
+	StartTime       Timestamp            `json:"start_time"`
+	FinishTime      Timestamp            `json:"finish_time"`
+	Error           *string              `json:"error"`
+	Status          test.ExecutionStatus `json:"status"`
+	Failure         json.RawMessage      `json:"failure"`
+	HeartbeatTime   Timestamp            `json:"heartbeat_time"`
+	Progress        json.RawMessage      `json:"progress"`
+}
+
+type AttemptCaseExecutionAttempt struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	CaseAttempt     int32                `json:"case_attempt"`
+	StartTime       Timestamp            `json:"start_time"`
+	FinishTime      Timestamp            `json:"finish_time"`
+	Error           *string              `json:"error"`
+	Status          test.ExecutionStatus `json:"status"`
+	Failure         json.RawMessage      `json:"failure"`
+}
+
+type AttemptCaseExecutionInput struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Index           int32                `json:"index"`
+	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
+}
+
+type AttemptCaseExecutionResult struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
+}
+
+type AttemptLog struct {
+	TestExecutionID test.TestExecutionID  `json:"test_execution_id"`
+	Attempt         int32                 `json:"attempt"`
+	ID              uuid.UUID             `json:"id"`
+	CaseExecutionID *test.CaseExecutionID `json:"case_execution_id"`
+	Level           string                `json:"level"`
+	Message         string                `json:"message"`
+	CreateTime      Timestamp             `json:"create_time"`
+}
+
 type CaseExecution struct {
 	ID              test.CaseExecutionID `json:"id"`
 	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
@@ -17,15 +73,48 @@ type CaseExecution struct {
 	StartTime       Timestamp            `json:"start_time"`
 	FinishTime      Timestamp            `json:"finish_time"`
 	Error           *string              `json:"error"`
+	Status          test.ExecutionStatus `json:"status"`
+	Failure         json.RawMessage      `json:"failure"`
+	HeartbeatTime   Timestamp            `json:"heartbeat_time"`
+	Progress        json.RawMessage      `json:"progress"`
+}
+
+type CaseExecutionAttempt struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	StartTime       Timestamp            `json:"start_time"`
+	FinishTime      Timestamp            `json:"finish_time"`
+	Error           *string              `json:"error"`
+	Status          test.ExecutionStatus `json:"status"`
+	Failure         json.RawMessage      `json:"failure"`
+}
+
+type CaseExecutionInput struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Index           int32                `json:"index"`
+	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
+}
+
+type CaseExecutionResult struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	CaseExecutionID test.CaseExecutionID `json:"case_execution_id"`
+	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
 }
 
 type Context struct {
-	ID string `json:"id"`
+	ID       string `json:"id"`
+	Archived bool   `json:"archived"`
 }
 
 type Group struct {
 	ContextID string `json:"context_id"`
 	ID        string `json:"id"`
+	Archived  bool   `json:"archived"`
+	TaskQueue string `json:"task_queue"`
 }
 
 type Log struct {
@@ -37,31 +126,101 @@ type Log struct {
 	CreateTime      Timestamp             `json:"create_time"`
 }
 
+type Schedule struct {
+	ID           uuid.UUID `json:"id"`
+	TestID       uuid.UUID `json:"test_id"`
+	Cron         string    `json:"cron"`
+	HasInput     bool      `json:"has_input"`
+	Paused       bool      `json:"paused"`
+	CreateTime   Timestamp `json:"create_time"`
+	NextFireTime Timestamp `json:"next_fire_time"`
+	LastFireTime Timestamp `json:"last_fire_time"`
+}
+
+type ScheduleInput struct {
+	ScheduleID uuid.UUID `json:"schedule_id"`
+	Metadata   []byte    `json:"metadata"`
+	Data       []byte    `json:"data"`
+}
+
 type Test struct {
-	ContextID  string    `json:"context_id"`
-	GroupID    string    `json:"group_id"`
-	ID         uuid.UUID `json:"id"`
-	Name       string    `json:"name"`
-	HasInput   bool      `json:"has_input"`
-	CreateTime Timestamp `json:"create_time"`
+	ContextID        string    `json:"context_id"`
+	GroupID          string    `json:"group_id"`
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	HasInput         bool      `json:"has_input"`
+	CreateTime       Timestamp `json:"create_time"`
+	ExecutionTimeout Interval  `json:"execution_timeout"`
+	RunTimeout       Interval  `json:"run_timeout"`
+	InputSchema      []byte    `json:"input_schema"`
+	Deprecated       bool      `json:"deprecated"`
+	Version          int32     `json:"version"`
+	Description      string    `json:"description"`
+	Tags             []string  `json:"tags"`
+	Owner            string    `json:"owner"`
+	SourceLocation   string    `json:"source_location"`
 }
 
 type TestDefaultInput struct {
-	TestID uuid.UUID `json:"test_id"`
-	Data   []byte    `json:"data"`
+	TestID   uuid.UUID `json:"test_id"`
+	Data     []byte    `json:"data"`
+	Metadata []byte    `json:"metadata"`
 }
 
 type TestExecution struct {
-	ID           test.TestExecutionID `json:"id"`
-	TestID       uuid.UUID            `json:"test_id"`
-	HasInput     bool                 `json:"has_input"`
-	ScheduleTime Timestamp            `json:"schedule_time"`
-	StartTime    Timestamp            `json:"start_time"`
-	FinishTime   Timestamp            `json:"finish_time"`
-	Error        *string              `json:"error"`
+	ID                test.TestExecutionID  `json:"id"`
+	TestID            uuid.UUID             `json:"test_id"`
+	HasInput          bool                  `json:"has_input"`
+	ScheduleTime      Timestamp             `json:"schedule_time"`
+	StartTime         Timestamp             `json:"start_time"`
+	FinishTime        Timestamp             `json:"finish_time"`
+	Error             *string               `json:"error"`
+	Status            test.ExecutionStatus  `json:"status"`
+	ExecutionTimeout  Interval              `json:"execution_timeout"`
+	RunTimeout        Interval              `json:"run_timeout"`
+	TestRunID         *uuid.UUID            `json:"test_run_id"`
+	Attempt           int32                 `json:"attempt"`
+	RerunOfID         *test.TestExecutionID `json:"rerun_of_id"`
+	TestVersion       *int32                `json:"test_version"`
+	Failure           json.RawMessage       `json:"failure"`
+	ParentID          *test.TestExecutionID `json:"parent_id"`
+	ChildWorkflowID   *string               `json:"child_workflow_id"`
+	ChildWorkflowType *string               `json:"child_workflow_type"`
+}
+
+type TestExecutionAttempt struct {
+	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
+	Attempt         int32                `json:"attempt"`
+	ScheduleTime    Timestamp            `json:"schedule_time"`
+	StartTime       Timestamp            `json:"start_time"`
+	FinishTime      Timestamp            `json:"finish_time"`
+	Error           *string              `json:"error"`
+	Status          test.ExecutionStatus `json:"status"`
+	ArchiveTime     Timestamp            `json:"archive_time"`
+	Failure         json.RawMessage      `json:"failure"`
 }
 
 type TestExecutionInput struct {
 	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
 	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
+}
+
+type TestRun struct {
+	ID         uuid.UUID `json:"id"`
+	ContextID  string    `json:"context_id"`
+	GroupID    *string   `json:"group_id"`
+	CreateTime Timestamp `json:"create_time"`
+}
+
+type TestVersion struct {
+	TestID               uuid.UUID `json:"test_id"`
+	Version              int32     `json:"version"`
+	HasInput             bool      `json:"has_input"`
+	DefaultInputMetadata []byte    `json:"default_input_metadata"`
+	DefaultInputData     []byte    `json:"default_input_data"`
+	ExecutionTimeout     Interval  `json:"execution_timeout"`
+	RunTimeout           Interval  `json:"run_timeout"`
+	InputSchema          []byte    `json:"input_schema"`
+	CreateTime           Timestamp `json:"create_time"`
 }
@@ -12,36 +12,100 @@ import (
 )
 
 type Querier interface {
-	ContextExists(ctx context.Context, id string) error
+	ContextExists(ctx context.Context, id string) (bool, error)
+	CreateAttemptCaseExecutionAttempts(ctx context.Context, testExecutionID test.TestExecutionID) error
+	CreateAttemptCaseExecutionInputs(ctx context.Context, testExecutionID test.TestExecutionID) error
+	CreateAttemptCaseExecutionResults(ctx context.Context, testExecutionID test.TestExecutionID) error
+	CreateAttemptCaseExecutions(ctx context.Context, testExecutionID test.TestExecutionID) error
+	CreateAttemptLogs(ctx context.Context, testExecutionID test.TestExecutionID) error
 	CreateCaseExecution(ctx context.Context, arg CreateCaseExecutionParams) (*CaseExecution, error)
+	CreateCaseExecutionAttempt(ctx context.Context, arg CreateCaseExecutionAttemptParams) (*CaseExecutionAttempt, error)
+	CreateCaseExecutionInput(ctx context.Context, arg CreateCaseExecutionInputParams) error
+	CreateCaseExecutionResult(ctx context.Context, arg CreateCaseExecutionResultParams) error
 	CreateContext(ctx context.Context, id string) error
 	CreateGroup(ctx context.Context, arg CreateGroupParams) error
 	CreateLog(ctx context.Context, arg CreateLogParams) error
+	CreateSchedule(ctx context.Context, arg CreateScheduleParams) (*Schedule, error)
+	CreateScheduleInput(ctx context.Context, arg CreateScheduleInputParams) error
+	CreateSubTestExecution(ctx context.Context, arg CreateSubTestExecutionParams) error
 	CreateTest(ctx context.Context, arg CreateTestParams) (*Test, error)
 	CreateTestDefaultInput(ctx context.Context, arg CreateTestDefaultInputParams) error
 	CreateTestExecution(ctx context.Context, arg CreateTestExecutionParams) (*TestExecution, error)
+	CreateTestExecutionAttempt(ctx context.Context, id test.TestExecutionID) (*TestExecutionAttempt, error)
 	CreateTestExecutionInput(ctx context.Context, arg CreateTestExecutionInputParams) error
+	CreateTestRun(ctx context.Context, arg CreateTestRunParams) (*TestRun, error)
+	CreateTestVersion(ctx context.Context, arg CreateTestVersionParams) (*TestVersion, error)
 	DeleteCaseExecution(ctx context.Context, arg DeleteCaseExecutionParams) error
+	DeleteCaseExecutionInputs(ctx context.Context, arg DeleteCaseExecutionInputsParams) error
+	DeleteContext(ctx context.Context, id string) error
+	DeleteGroup(ctx context.Context, arg DeleteGroupParams) error
+	DeleteGroupTestRuns(ctx context.Context, arg DeleteGroupTestRunsParams) error
 	DeleteLog(ctx context.Context, id uuid.UUID) error
+	DeleteSchedule(ctx context.Context, id uuid.UUID) (int64, error)
+	DeprecateTests(ctx context.Context, arg DeprecateTestsParams) ([]*Test, error)
+	GetAttemptCaseExecutionResult(ctx context.Context, arg GetAttemptCaseExecutionResultParams) (*AttemptCaseExecutionResult, error)
 	GetCaseExecution(ctx context.Context, arg GetCaseExecutionParams) (*CaseExecution, error)
+	GetCaseExecutionResult(ctx context.Context, arg GetCaseExecutionResultParams) (*CaseExecutionResult, error)
+	GetGroupTaskQueue(ctx context.Context, arg GetGroupTaskQueueParams) (string, error)
+	GetLatestTestVersion(ctx context.Context, testID uuid.UUID) (*TestVersion, error)
 	GetLog(ctx context.Context, id uuid.UUID) (*Log, error)
+	GetSchedule(ctx context.Context, id uuid.UUID) (*Schedule, error)
+	GetScheduleInput(ctx context.Context, scheduleID uuid.UUID) (*ScheduleInput, error)
+	GetSubTestExecution(ctx context.Context, childWorkflowID *string) (*TestExecution, error)
 	GetTest(ctx context.Context, id uuid.UUID) (*Test, error)
 	GetTestByName(ctx context.Context, arg GetTestByNameParams) (*Test, error)
 	GetTestDefaultInput(ctx context.Context, testID uuid.UUID) (*TestDefaultInput, error)
 	GetTestExecution(ctx context.Context, id test.TestExecutionID) (*TestExecution, error)
+	GetTestExecutionAttempt(ctx context.Context, arg GetTestExecutionAttemptParams) (*TestExecutionAttempt, error)
 	GetTestExecutionInput(ctx context.Context, testExecutionID test.TestExecutionID) (*TestExecutionInput, error)
-	GroupExists(ctx context.Context, arg GroupExistsParams) error
+	GetTestRun(ctx context.Context, id uuid.UUID) (*TestRun, error)
+	GetTestVersion(ctx context.Context, arg GetTestVersionParams) (*TestVersion, error)
+	GroupExists(ctx context.Context, arg GroupExistsParams) (bool, error)
+	HasActiveTestExecutions(ctx context.Context, arg HasActiveTestExecutionsParams) (bool, error)
+	IsContextArchived(ctx context.Context, id string) (bool, error)
+	IsGroupArchived(ctx context.Context, arg IsGroupArchivedParams) (bool, error)
+	ListAttemptCaseExecutionAttempts(ctx context.Context, arg ListAttemptCaseExecutionAttemptsParams) ([]*AttemptCaseExecutionAttempt, error)
+	ListAttemptCaseExecutionInputs(ctx context.Context, arg ListAttemptCaseExecutionInputsParams) ([]*AttemptCaseExecutionInput, error)
+	ListAttemptCaseExecutions(ctx context.Context, arg ListAttemptCaseExecutionsParams) ([]*AttemptCaseExecution, error)
+	ListAttemptLogs(ctx context.Context, arg ListAttemptLogsParams) ([]*AttemptLog, error)
+	ListCaseExecutionAttempts(ctx context.Context, arg ListCaseExecutionAttemptsParams) ([]*CaseExecutionAttempt, error)
+	ListCaseExecutionInputs(ctx context.Context, arg ListCaseExecutionInputsParams) ([]*CaseExecutionInput, error)
 	ListCaseExecutions(ctx context.Context, testExecutionID test.TestExecutionID) ([]*CaseExecution, error)
 	ListContexts(ctx context.Context) ([]string, error)
-	ListGroups(ctx context.Context, contextID string) ([]string, error)
+	ListDueSchedules(ctx context.Context, now Timestamp) ([]*Schedule, error)
+	ListExpiredTestExecutions(ctx context.Context, now Timestamp) ([]*TestExecution, error)
+	ListGroups(ctx context.Context, arg ListGroupsParams) ([]string, error)
 	ListLogs(ctx context.Context, testExecutionID test.TestExecutionID) ([]*Log, error)
+	ListRecentTestExecutions(ctx context.Context, arg ListRecentTestExecutionsParams) ([]*ListRecentTestExecutionsRow, error)
+	ListSchedules(ctx context.Context, testID uuid.UUID) ([]*Schedule, error)
+	ListSubTestExecutions(ctx context.Context, parentID *test.TestExecutionID) ([]*TestExecution, error)
+	ListTestExecutionAttempts(ctx context.Context, testExecutionID test.TestExecutionID) ([]*TestExecutionAttempt, error)
 	ListTestExecutions(ctx context.Context, arg ListTestExecutionsParams) ([]*TestExecution, error)
+	ListTestRunExecutions(ctx context.Context, testRunID *uuid.UUID) ([]*TestExecution, error)
+	ListTestVersions(ctx context.Context, testID uuid.UUID) ([]*TestVersion, error)
 	ListTests(ctx context.Context, arg ListTestsParams) ([]*Test, error)
+	MoveTest(ctx context.Context, arg MoveTestParams) (*Test, error)
+	RenameGroup(ctx context.Context, arg RenameGroupParams) error
+	RenameTestRunsGroup(ctx context.Context, arg RenameTestRunsGroupParams) error
 	ResetCaseExecution(ctx context.Context, arg ResetCaseExecutionParams) (*CaseExecution, error)
+	SearchTests(ctx context.Context, arg SearchTestsParams) ([]*Test, error)
+	TaskQueueExists(ctx context.Context, taskQueue string) (bool, error)
+	UpdateCaseExecutionAttemptFinished(ctx context.Context, arg UpdateCaseExecutionAttemptFinishedParams) (*CaseExecutionAttempt, error)
 	UpdateCaseExecutionFinished(ctx context.Context, arg UpdateCaseExecutionFinishedParams) (*CaseExecution, error)
+	UpdateCaseExecutionHeartbeat(ctx context.Context, arg UpdateCaseExecutionHeartbeatParams) (*CaseExecution, error)
 	UpdateCaseExecutionStarted(ctx context.Context, arg UpdateCaseExecutionStartedParams) (*CaseExecution, error)
+	UpdateContextArchived(ctx context.Context, arg UpdateContextArchivedParams) error
+	UpdateGroupArchived(ctx context.Context, arg UpdateGroupArchivedParams) error
+	UpdateOpenCaseExecutionAttemptsStopped(ctx context.Context, arg UpdateOpenCaseExecutionAttemptsStoppedParams) error
+	UpdateOpenCaseExecutionsStopped(ctx context.Context, arg UpdateOpenCaseExecutionsStoppedParams) error
+	UpdatePreviousCaseExecutionAttemptsTimedOut(ctx context.Context, arg UpdatePreviousCaseExecutionAttemptsTimedOutParams) error
+	UpdateScheduleFired(ctx context.Context, arg UpdateScheduleFiredParams) (*Schedule, error)
+	UpdateSchedulePaused(ctx context.Context, id uuid.UUID) (*Schedule, error)
+	UpdateScheduleResumed(ctx context.Context, arg UpdateScheduleResumedParams) (*Schedule, error)
 	UpdateTestExecutionFinished(ctx context.Context, arg UpdateTestExecutionFinishedParams) (*TestExecution, error)
 	UpdateTestExecutionStarted(ctx context.Context, arg UpdateTestExecutionStartedParams) (*TestExecution, error)
+	UpdateTestExecutionStopped(ctx context.Context, arg UpdateTestExecutionStoppedParams) (*TestExecution, error)
+	UpdateTestVersion(ctx context.Context, arg UpdateTestVersionParams) (*Test, error)
 }
 
 var _ Querier = (*Queries)(nil)
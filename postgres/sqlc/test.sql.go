@@ -12,20 +12,37 @@ import (
 )
 
 const createTest = `-- name: CreateTest :one
-INSERT INTO tests (context_id, group_id, id, name, has_input)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO tests (context_id, group_id, id, name, has_input, execution_timeout, run_timeout, input_schema,
+                   description, tags, owner, source_location)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
+        $9, $10, $11, $12)
 ON CONFLICT (context_id, group_id, name) DO UPDATE
-    SET has_input   = excluded.has_input,
-        create_time = now()
-RETURNING context_id, group_id, id, name, has_input, create_time
+    SET has_input         = excluded.has_input,
+        execution_timeout = COALESCE(excluded.execution_timeout, tests.execution_timeout),
+        run_timeout       = COALESCE(excluded.run_timeout, tests.run_timeout),
+        input_schema      = COALESCE(excluded.input_schema, tests.input_schema),
+        description       = CASE WHEN $13::bool THEN tests.description ELSE excluded.description END,
+        tags              = CASE WHEN $13::bool THEN tests.tags ELSE excluded.tags END,
+        owner             = CASE WHEN $13::bool THEN tests.owner ELSE excluded.owner END,
+        source_location   = CASE WHEN $13::bool THEN tests.source_location ELSE excluded.source_location END,
+        deprecated        = FALSE
+RETURNING context_id, group_id, id, name, has_input, create_time, execution_timeout, run_timeout, input_schema, deprecated, version, description, tags, owner, source_location
 `
 
 type CreateTestParams struct {
-	ContextID string    `json:"context_id"`
-	GroupID   string    `json:"group_id"`
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	HasInput  bool      `json:"has_input"`
+	ContextID        string    `json:"context_id"`
+	GroupID          string    `json:"group_id"`
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	HasInput         bool      `json:"has_input"`
+	ExecutionTimeout Interval  `json:"execution_timeout"`
+	RunTimeout       Interval  `json:"run_timeout"`
+	InputSchema      []byte    `json:"input_schema"`
+	Description      string    `json:"description"`
+	Tags             []string  `json:"tags"`
+	Owner            string    `json:"owner"`
+	SourceLocation   string    `json:"source_location"`
+	KeepMetadata     bool      `json:"keep_metadata"`
 }
 
 func (q *Queries) CreateTest(ctx context.Context, arg CreateTestParams) (*Test, error) {
@@ -35,6 +52,14 @@ func (q *Queries) CreateTest(ctx context.Context, arg CreateTestParams) (*Test,
 		arg.ID,
 		arg.Name,
 		arg.HasInput,
+		arg.ExecutionTimeout,
+		arg.RunTimeout,
+		arg.InputSchema,
+		arg.Description,
+		arg.Tags,
+		arg.Owner,
+		arg.SourceLocation,
+		arg.KeepMetadata,
 	)
 	var i Test
 	err := row.Scan(
@@ -44,29 +69,92 @@ func (q *Queries) CreateTest(ctx context.Context, arg CreateTestParams) (*Test,
 		&i.Name,
 		&i.HasInput,
 		&i.CreateTime,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.InputSchema,
+		&i.Deprecated,
+		&i.Version,
+		&i.Description,
+		&i.Tags,
+		&i.Owner,
+		&i.SourceLocation,
 	)
 	return &i, err
 }
 
 const createTestDefaultInput = `-- name: CreateTestDefaultInput :exec
-INSERT INTO test_default_inputs (test_id, data)
-VALUES ($1, $2)
+INSERT INTO test_default_inputs (test_id, data, metadata)
+VALUES ($1, $2, $3)
 ON CONFLICT (test_id) DO UPDATE
-    SET data = excluded.data
+    SET data     = excluded.data,
+        metadata = excluded.metadata
 `
 
 type CreateTestDefaultInputParams struct {
-	TestID uuid.UUID `json:"test_id"`
-	Data   []byte    `json:"data"`
+	TestID   uuid.UUID `json:"test_id"`
+	Data     []byte    `json:"data"`
+	Metadata []byte    `json:"metadata"`
 }
 
 func (q *Queries) CreateTestDefaultInput(ctx context.Context, arg CreateTestDefaultInputParams) error {
-	_, err := q.db.Exec(ctx, createTestDefaultInput, arg.TestID, arg.Data)
+	_, err := q.db.Exec(ctx, createTestDefaultInput, arg.TestID, arg.Data, arg.Metadata)
 	return err
 }
 
+const deprecateTests = `-- name: DeprecateTests :many
+UPDATE tests
+SET deprecated = TRUE
+WHERE context_id = $1
+  AND group_id = $2
+  AND NOT deprecated
+  AND NOT (name = ANY ($3::text[]))
+RETURNING context_id, group_id, id, name, has_input, create_time, execution_timeout, run_timeout, input_schema, deprecated, version, description, tags, owner, source_location
+`
+
+type DeprecateTestsParams struct {
+	ContextID string   `json:"context_id"`
+	GroupID   string   `json:"group_id"`
+	KeepNames []string `json:"keep_names"`
+}
+
+func (q *Queries) DeprecateTests(ctx context.Context, arg DeprecateTestsParams) ([]*Test, error) {
+	rows, err := q.db.Query(ctx, deprecateTests, arg.ContextID, arg.GroupID, arg.KeepNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Test
+	for rows.Next() {
+		var i Test
+		if err := rows.Scan(
+			&i.ContextID,
+			&i.GroupID,
+			&i.ID,
+			&i.Name,
+			&i.HasInput,
+			&i.CreateTime,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.InputSchema,
+			&i.Deprecated,
+			&i.Version,
+			&i.Description,
+			&i.Tags,
+			&i.Owner,
+			&i.SourceLocation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTest = `-- name: GetTest :one
-SELECT context_id, group_id, id, name, has_input, create_time
+SELECT context_id, group_id, id, name, has_input, create_time, execution_timeout, run_timeout, input_schema, deprecated, version, description, tags, owner, source_location
 FROM tests
 WHERE id = $1
 `
@@ -81,12 +169,21 @@ func (q *Queries) GetTest(ctx context.Context, id uuid.UUID) (*Test, error) {
 		&i.Name,
 		&i.HasInput,
 		&i.CreateTime,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.InputSchema,
+		&i.Deprecated,
+		&i.Version,
+		&i.Description,
+		&i.Tags,
+		&i.Owner,
+		&i.SourceLocation,
 	)
 	return &i, err
 }
 
 const getTestByName = `-- name: GetTestByName :one
-SELECT context_id, group_id, id, name, has_input, create_time
+SELECT context_id, group_id, id, name, has_input, create_time, execution_timeout, run_timeout, input_schema, deprecated, version, description, tags, owner, source_location
 FROM tests
 WHERE name = $1
   AND group_id = $2
@@ -107,12 +204,21 @@ func (q *Queries) GetTestByName(ctx context.Context, arg GetTestByNameParams) (*
 		&i.Name,
 		&i.HasInput,
 		&i.CreateTime,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.InputSchema,
+		&i.Deprecated,
+		&i.Version,
+		&i.Description,
+		&i.Tags,
+		&i.Owner,
+		&i.SourceLocation,
 	)
 	return &i, err
 }
 
 const getTestDefaultInput = `-- name: GetTestDefaultInput :one
-SELECT test_id, data
+SELECT test_id, data, metadata
 FROM test_default_inputs
 WHERE test_id = $1
 `
@@ -120,23 +226,144 @@ WHERE test_id = $1
 func (q *Queries) GetTestDefaultInput(ctx context.Context, testID uuid.UUID) (*TestDefaultInput, error) {
 	row := q.db.QueryRow(ctx, getTestDefaultInput, testID)
 	var i TestDefaultInput
-	err := row.Scan(&i.TestID, &i.Data)
+	err := row.Scan(&i.TestID, &i.Data, &i.Metadata)
 	return &i, err
 }
 
 const listTests = `-- name: ListTests :many
-SELECT context_id, group_id, id, name, has_input, create_time
+SELECT context_id, group_id, id, name, has_input, create_time, execution_timeout, run_timeout, input_schema, deprecated, version, description, tags, owner, source_location
 FROM tests
 WHERE context_id = $1 AND group_id = $2
+  AND (NOT deprecated OR $3::bool)
+  AND tags @> coalesce($4::text[], '{}')
+  AND ($5::text = '' OR owner = $5)
 `
 
 type ListTestsParams struct {
-	ContextID string `json:"context_id"`
-	GroupID   string `json:"group_id"`
+	ContextID         string   `json:"context_id"`
+	GroupID           string   `json:"group_id"`
+	IncludeDeprecated bool     `json:"include_deprecated"`
+	Tags              []string `json:"tags"`
+	Owner             string   `json:"owner"`
 }
 
 func (q *Queries) ListTests(ctx context.Context, arg ListTestsParams) ([]*Test, error) {
-	rows, err := q.db.Query(ctx, listTests, arg.ContextID, arg.GroupID)
+	rows, err := q.db.Query(ctx, listTests,
+		arg.ContextID,
+		arg.GroupID,
+		arg.IncludeDeprecated,
+		arg.Tags,
+		arg.Owner,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Test
+	for rows.Next() {
+		var i Test
+		if err := rows.Scan(
+			&i.ContextID,
+			&i.GroupID,
+			&i.ID,
+			&i.Name,
+			&i.HasInput,
+			&i.CreateTime,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.InputSchema,
+			&i.Deprecated,
+			&i.Version,
+			&i.Description,
+			&i.Tags,
+			&i.Owner,
+			&i.SourceLocation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveTest = `-- name: MoveTest :one
+UPDATE tests
+SET group_id = $2
+WHERE id = $1
+RETURNING context_id, group_id, id, name, has_input, create_time, execution_timeout, run_timeout, input_schema, deprecated, version, description, tags, owner, source_location
+`
+
+type MoveTestParams struct {
+	ID      uuid.UUID `json:"id"`
+	GroupID string    `json:"group_id"`
+}
+
+func (q *Queries) MoveTest(ctx context.Context, arg MoveTestParams) (*Test, error) {
+	row := q.db.QueryRow(ctx, moveTest, arg.ID, arg.GroupID)
+	var i Test
+	err := row.Scan(
+		&i.ContextID,
+		&i.GroupID,
+		&i.ID,
+		&i.Name,
+		&i.HasInput,
+		&i.CreateTime,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.InputSchema,
+		&i.Deprecated,
+		&i.Version,
+		&i.Description,
+		&i.Tags,
+		&i.Owner,
+		&i.SourceLocation,
+	)
+	return &i, err
+}
+
+const searchTests = `-- name: SearchTests :many
+SELECT context_id, group_id, id, name, has_input, create_time, execution_timeout, run_timeout, input_schema, deprecated, version, description, tags, owner, source_location
+FROM tests
+WHERE ($1::text IS NULL OR name ILIKE $1::text)
+  AND tags @> coalesce($2::text[], '{}')
+  AND ($3::text = '' OR owner = $3)
+  AND (NOT deprecated OR $4::bool)
+  AND (context_id, group_id) IN (SELECT g.context_id, g.id
+                                 FROM groups g
+                                          JOIN contexts c ON c.id = g.context_id
+                                 WHERE NOT g.archived
+                                   AND NOT c.archived)
+  AND (
+    ($5::timestamp IS NULL AND $6::uuid IS NULL)
+        OR (create_time, id) < ($5::timestamp, $6::uuid)
+    )
+ORDER BY create_time DESC, id DESC
+LIMIT ($7::integer)
+`
+
+type SearchTestsParams struct {
+	NamePattern       *string    `json:"name_pattern"`
+	Tags              []string   `json:"tags"`
+	Owner             string     `json:"owner"`
+	IncludeDeprecated bool       `json:"include_deprecated"`
+	LastCreateTime    Timestamp  `json:"last_create_time"`
+	LastTestID        *uuid.UUID `json:"last_test_id"`
+	PageSize          *int32     `json:"page_size"`
+}
+
+func (q *Queries) SearchTests(ctx context.Context, arg SearchTestsParams) ([]*Test, error) {
+	rows, err := q.db.Query(ctx, searchTests,
+		arg.NamePattern,
+		arg.Tags,
+		arg.Owner,
+		arg.IncludeDeprecated,
+		arg.LastCreateTime,
+		arg.LastTestID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +378,15 @@ func (q *Queries) ListTests(ctx context.Context, arg ListTestsParams) ([]*Test,
 			&i.Name,
 			&i.HasInput,
 			&i.CreateTime,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.InputSchema,
+			&i.Deprecated,
+			&i.Version,
+			&i.Description,
+			&i.Tags,
+			&i.Owner,
+			&i.SourceLocation,
 		); err != nil {
 			return nil, err
 		}
@@ -7,29 +7,73 @@ package sqlc
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/annexsh/annex/test"
 	"github.com/google/uuid"
 )
 
+const createSubTestExecution = `-- name: CreateSubTestExecution :exec
+INSERT INTO test_executions (id, test_id, has_input, schedule_time, status, test_version, parent_id,
+                             child_workflow_id, child_workflow_type)
+SELECT $1, p.test_id, false, $2, 'scheduled', p.test_version, p.id, $3,
+       $4
+FROM test_executions p
+WHERE p.id = $5
+ON CONFLICT (id) DO NOTHING
+`
+
+type CreateSubTestExecutionParams struct {
+	ID                test.TestExecutionID `json:"id"`
+	ScheduleTime      Timestamp            `json:"schedule_time"`
+	ChildWorkflowID   *string              `json:"child_workflow_id"`
+	ChildWorkflowType *string              `json:"child_workflow_type"`
+	ParentID          test.TestExecutionID `json:"parent_id"`
+}
+
+func (q *Queries) CreateSubTestExecution(ctx context.Context, arg CreateSubTestExecutionParams) error {
+	_, err := q.db.Exec(ctx, createSubTestExecution,
+		arg.ID,
+		arg.ScheduleTime,
+		arg.ChildWorkflowID,
+		arg.ChildWorkflowType,
+		arg.ParentID,
+	)
+	return err
+}
+
 const createTestExecution = `-- name: CreateTestExecution :one
-INSERT INTO test_executions (id, test_id, has_input, schedule_time)
-VALUES ($1, $2, $3, $4)
+INSERT INTO test_executions (id, test_id, has_input, schedule_time, status, execution_timeout, run_timeout,
+                             test_run_id, rerun_of_id, test_version)
+VALUES ($1, $2, $3, $4, 'scheduled', $5, $6, $7, $8, $9)
 ON CONFLICT (id) DO UPDATE
-    SET test_id       = excluded.test_id,
-        has_input     = excluded.has_input,
-        schedule_time = excluded.schedule_time,
-        start_time    = null,
-        finish_time   = null,
-        error         = null
-RETURNING id, test_id, has_input, schedule_time, start_time, finish_time, error
+    SET test_id           = excluded.test_id,
+        has_input         = excluded.has_input,
+        schedule_time     = excluded.schedule_time,
+        start_time        = null,
+        finish_time       = null,
+        error             = null,
+        failure           = null,
+        status            = excluded.status,
+        execution_timeout = excluded.execution_timeout,
+        run_timeout       = excluded.run_timeout,
+        test_run_id       = excluded.test_run_id,
+        rerun_of_id       = excluded.rerun_of_id,
+        test_version      = excluded.test_version,
+        attempt           = test_executions.attempt + 1
+RETURNING id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
 `
 
 type CreateTestExecutionParams struct {
-	ID           test.TestExecutionID `json:"id"`
-	TestID       uuid.UUID            `json:"test_id"`
-	HasInput     bool                 `json:"has_input"`
-	ScheduleTime Timestamp            `json:"schedule_time"`
+	ID               test.TestExecutionID  `json:"id"`
+	TestID           uuid.UUID             `json:"test_id"`
+	HasInput         bool                  `json:"has_input"`
+	ScheduleTime     Timestamp             `json:"schedule_time"`
+	ExecutionTimeout Interval              `json:"execution_timeout"`
+	RunTimeout       Interval              `json:"run_timeout"`
+	TestRunID        *uuid.UUID            `json:"test_run_id"`
+	RerunOfID        *test.TestExecutionID `json:"rerun_of_id"`
+	TestVersion      *int32                `json:"test_version"`
 }
 
 func (q *Queries) CreateTestExecution(ctx context.Context, arg CreateTestExecutionParams) (*TestExecution, error) {
@@ -38,6 +82,11 @@ func (q *Queries) CreateTestExecution(ctx context.Context, arg CreateTestExecuti
 		arg.TestID,
 		arg.HasInput,
 		arg.ScheduleTime,
+		arg.ExecutionTimeout,
+		arg.RunTimeout,
+		arg.TestRunID,
+		arg.RerunOfID,
+		arg.TestVersion,
 	)
 	var i TestExecution
 	err := row.Scan(
@@ -48,29 +97,76 @@ func (q *Queries) CreateTestExecution(ctx context.Context, arg CreateTestExecuti
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.TestRunID,
+		&i.Attempt,
+		&i.RerunOfID,
+		&i.TestVersion,
+		&i.Failure,
+		&i.ParentID,
+		&i.ChildWorkflowID,
+		&i.ChildWorkflowType,
 	)
 	return &i, err
 }
 
 const createTestExecutionInput = `-- name: CreateTestExecutionInput :exec
-INSERT INTO test_execution_inputs (test_execution_id, data)
-VALUES ($1, $2)
+INSERT INTO test_execution_inputs (test_execution_id, data, metadata)
+VALUES ($1, $2, $3)
 ON CONFLICT (test_execution_id) DO UPDATE
-    SET data = excluded.data
+    SET data     = excluded.data,
+        metadata = excluded.metadata
 `
 
 type CreateTestExecutionInputParams struct {
 	TestExecutionID test.TestExecutionID `json:"test_execution_id"`
 	Data            []byte               `json:"data"`
+	Metadata        []byte               `json:"metadata"`
 }
 
 func (q *Queries) CreateTestExecutionInput(ctx context.Context, arg CreateTestExecutionInputParams) error {
-	_, err := q.db.Exec(ctx, createTestExecutionInput, arg.TestExecutionID, arg.Data)
+	_, err := q.db.Exec(ctx, createTestExecutionInput, arg.TestExecutionID, arg.Data, arg.Metadata)
 	return err
 }
 
+const getSubTestExecution = `-- name: GetSubTestExecution :one
+SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
+FROM test_executions
+WHERE child_workflow_id = $1
+ORDER BY schedule_time DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetSubTestExecution(ctx context.Context, childWorkflowID *string) (*TestExecution, error) {
+	row := q.db.QueryRow(ctx, getSubTestExecution, childWorkflowID)
+	var i TestExecution
+	err := row.Scan(
+		&i.ID,
+		&i.TestID,
+		&i.HasInput,
+		&i.ScheduleTime,
+		&i.StartTime,
+		&i.FinishTime,
+		&i.Error,
+		&i.Status,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.TestRunID,
+		&i.Attempt,
+		&i.RerunOfID,
+		&i.TestVersion,
+		&i.Failure,
+		&i.ParentID,
+		&i.ChildWorkflowID,
+		&i.ChildWorkflowType,
+	)
+	return &i, err
+}
+
 const getTestExecution = `-- name: GetTestExecution :one
-SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error
+SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
 FROM test_executions
 WHERE id = $1
 `
@@ -86,12 +182,23 @@ func (q *Queries) GetTestExecution(ctx context.Context, id test.TestExecutionID)
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.TestRunID,
+		&i.Attempt,
+		&i.RerunOfID,
+		&i.TestVersion,
+		&i.Failure,
+		&i.ParentID,
+		&i.ChildWorkflowID,
+		&i.ChildWorkflowType,
 	)
 	return &i, err
 }
 
 const getTestExecutionInput = `-- name: GetTestExecutionInput :one
-SELECT test_execution_id, data
+SELECT test_execution_id, data, metadata
 FROM test_execution_inputs
 WHERE test_execution_id = $1
 `
@@ -99,20 +206,214 @@ WHERE test_execution_id = $1
 func (q *Queries) GetTestExecutionInput(ctx context.Context, testExecutionID test.TestExecutionID) (*TestExecutionInput, error) {
 	row := q.db.QueryRow(ctx, getTestExecutionInput, testExecutionID)
 	var i TestExecutionInput
-	err := row.Scan(&i.TestExecutionID, &i.Data)
+	err := row.Scan(&i.TestExecutionID, &i.Data, &i.Metadata)
 	return &i, err
 }
 
+const listExpiredTestExecutions = `-- name: ListExpiredTestExecutions :many
+SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
+FROM test_executions
+WHERE status IN ('scheduled', 'running')
+  AND LEAST(schedule_time + execution_timeout, schedule_time + run_timeout) <= $1::timestamp
+ORDER BY schedule_time
+`
+
+func (q *Queries) ListExpiredTestExecutions(ctx context.Context, now Timestamp) ([]*TestExecution, error) {
+	rows, err := q.db.Query(ctx, listExpiredTestExecutions, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*TestExecution
+	for rows.Next() {
+		var i TestExecution
+		if err := rows.Scan(
+			&i.ID,
+			&i.TestID,
+			&i.HasInput,
+			&i.ScheduleTime,
+			&i.StartTime,
+			&i.FinishTime,
+			&i.Error,
+			&i.Status,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.TestRunID,
+			&i.Attempt,
+			&i.RerunOfID,
+			&i.TestVersion,
+			&i.Failure,
+			&i.ParentID,
+			&i.ChildWorkflowID,
+			&i.ChildWorkflowType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentTestExecutions = `-- name: ListRecentTestExecutions :many
+SELECT te.id, te.test_id, te.has_input, te.schedule_time, te.start_time, te.finish_time, te.error, te.status, te.execution_timeout, te.run_timeout, te.test_run_id, te.attempt, te.rerun_of_id, te.test_version, te.failure, te.parent_id, te.child_workflow_id, te.child_workflow_type, t.context_id, t.group_id, t.name AS test_name
+FROM test_executions te
+         INNER JOIN tests t ON t.id = te.test_id
+WHERE t.context_id = $1
+  AND te.parent_id IS NULL
+  AND ($2::text IS NULL OR t.group_id = $2::text)
+  AND (
+    ($3::timestamp IS NULL AND $4::uuid IS NULL)
+        OR (te.schedule_time, te.id) <
+           ($3::timestamp, $4::uuid)
+    )
+ORDER BY te.schedule_time DESC, te.id DESC
+LIMIT ($5::integer)
+`
+
+type ListRecentTestExecutionsParams struct {
+	ContextID           string     `json:"context_id"`
+	GroupID             *string    `json:"group_id"`
+	LastScheduleTime    Timestamp  `json:"last_schedule_time"`
+	LastTestExecutionID *uuid.UUID `json:"last_test_execution_id"`
+	PageSize            *int32     `json:"page_size"`
+}
+
+type ListRecentTestExecutionsRow struct {
+	ID                test.TestExecutionID  `json:"id"`
+	TestID            uuid.UUID             `json:"test_id"`
+	HasInput          bool                  `json:"has_input"`
+	ScheduleTime      Timestamp             `json:"schedule_time"`
+	StartTime         Timestamp             `json:"start_time"`
+	FinishTime        Timestamp             `json:"finish_time"`
+	Error             *string               `json:"error"`
+	Status            test.ExecutionStatus  `json:"status"`
+	ExecutionTimeout  Interval              `json:"execution_timeout"`
+	RunTimeout        Interval              `json:"run_timeout"`
+	TestRunID         *uuid.UUID            `json:"test_run_id"`
+	Attempt           int32                 `json:"attempt"`
+	RerunOfID         *test.TestExecutionID `json:"rerun_of_id"`
+	TestVersion       *int32                `json:"test_version"`
+	Failure           json.RawMessage       `json:"failure"`
+	ParentID          *test.TestExecutionID `json:"parent_id"`
+	ChildWorkflowID   *string               `json:"child_workflow_id"`
+	ChildWorkflowType *string               `json:"child_workflow_type"`
+	ContextID         string                `json:"context_id"`
+	GroupID           string                `json:"group_id"`
+	TestName          string                `json:"test_name"`
+}
+
+func (q *Queries) ListRecentTestExecutions(ctx context.Context, arg ListRecentTestExecutionsParams) ([]*ListRecentTestExecutionsRow, error) {
+	rows, err := q.db.Query(ctx, listRecentTestExecutions,
+		arg.ContextID,
+		arg.GroupID,
+		arg.LastScheduleTime,
+		arg.LastTestExecutionID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListRecentTestExecutionsRow
+	for rows.Next() {
+		var i ListRecentTestExecutionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TestID,
+			&i.HasInput,
+			&i.ScheduleTime,
+			&i.StartTime,
+			&i.FinishTime,
+			&i.Error,
+			&i.Status,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.TestRunID,
+			&i.Attempt,
+			&i.RerunOfID,
+			&i.TestVersion,
+			&i.Failure,
+			&i.ParentID,
+			&i.ChildWorkflowID,
+			&i.ChildWorkflowType,
+			&i.ContextID,
+			&i.GroupID,
+			&i.TestName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubTestExecutions = `-- name: ListSubTestExecutions :many
+SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
+FROM test_executions
+WHERE parent_id = $1
+ORDER BY schedule_time, id
+`
+
+func (q *Queries) ListSubTestExecutions(ctx context.Context, parentID *test.TestExecutionID) ([]*TestExecution, error) {
+	rows, err := q.db.Query(ctx, listSubTestExecutions, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*TestExecution
+	for rows.Next() {
+		var i TestExecution
+		if err := rows.Scan(
+			&i.ID,
+			&i.TestID,
+			&i.HasInput,
+			&i.ScheduleTime,
+			&i.StartTime,
+			&i.FinishTime,
+			&i.Error,
+			&i.Status,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.TestRunID,
+			&i.Attempt,
+			&i.RerunOfID,
+			&i.TestVersion,
+			&i.Failure,
+			&i.ParentID,
+			&i.ChildWorkflowID,
+			&i.ChildWorkflowType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTestExecutions = `-- name: ListTestExecutions :many
-SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error
+SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
 FROM test_executions
 WHERE ($1 = test_id)
+  AND parent_id IS NULL
   AND (
     ($2::timestamp IS NULL AND $3::uuid IS NULL)
         OR (schedule_time, id) < ($2::timestamp, $4::uuid)
     )
+  AND ($5::text[] IS NULL OR status = ANY ($5::text[]))
+  AND ($6::timestamp IS NULL OR schedule_time >= $6::timestamp)
+  AND ($7::timestamp IS NULL OR schedule_time < $7::timestamp)
+  AND ($8::text IS NULL OR strpos(lower(error), lower($8::text)) > 0)
 ORDER BY schedule_time DESC, id DESC
-LIMIT ($5::integer)
+LIMIT ($9::integer)
 `
 
 type ListTestExecutionsParams struct {
@@ -120,6 +421,10 @@ type ListTestExecutionsParams struct {
 	LastScheduleTime    Timestamp  `json:"last_schedule_time"`
 	LastExecID          *uuid.UUID `json:"last_exec_id"`
 	LastTestExecutionID uuid.UUID  `json:"last_test_execution_id"`
+	Statuses            []string   `json:"statuses"`
+	ScheduledAfter      Timestamp  `json:"scheduled_after"`
+	ScheduledBefore     Timestamp  `json:"scheduled_before"`
+	ErrorContains       *string    `json:"error_contains"`
 	PageSize            *int32     `json:"page_size"`
 }
 
@@ -129,6 +434,10 @@ func (q *Queries) ListTestExecutions(ctx context.Context, arg ListTestExecutions
 		arg.LastScheduleTime,
 		arg.LastExecID,
 		arg.LastTestExecutionID,
+		arg.Statuses,
+		arg.ScheduledAfter,
+		arg.ScheduledBefore,
+		arg.ErrorContains,
 		arg.PageSize,
 	)
 	if err != nil {
@@ -146,6 +455,63 @@ func (q *Queries) ListTestExecutions(ctx context.Context, arg ListTestExecutions
 			&i.StartTime,
 			&i.FinishTime,
 			&i.Error,
+			&i.Status,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.TestRunID,
+			&i.Attempt,
+			&i.RerunOfID,
+			&i.TestVersion,
+			&i.Failure,
+			&i.ParentID,
+			&i.ChildWorkflowID,
+			&i.ChildWorkflowType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTestRunExecutions = `-- name: ListTestRunExecutions :many
+SELECT id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
+FROM test_executions
+WHERE test_run_id = $1
+ORDER BY schedule_time, id
+`
+
+func (q *Queries) ListTestRunExecutions(ctx context.Context, testRunID *uuid.UUID) ([]*TestExecution, error) {
+	rows, err := q.db.Query(ctx, listTestRunExecutions, testRunID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*TestExecution
+	for rows.Next() {
+		var i TestExecution
+		if err := rows.Scan(
+			&i.ID,
+			&i.TestID,
+			&i.HasInput,
+			&i.ScheduleTime,
+			&i.StartTime,
+			&i.FinishTime,
+			&i.Error,
+			&i.Status,
+			&i.ExecutionTimeout,
+			&i.RunTimeout,
+			&i.TestRunID,
+			&i.Attempt,
+			&i.RerunOfID,
+			&i.TestVersion,
+			&i.Failure,
+			&i.ParentID,
+			&i.ChildWorkflowID,
+			&i.ChildWorkflowType,
 		); err != nil {
 			return nil, err
 		}
@@ -160,19 +526,30 @@ func (q *Queries) ListTestExecutions(ctx context.Context, arg ListTestExecutions
 const updateTestExecutionFinished = `-- name: UpdateTestExecutionFinished :one
 UPDATE test_executions
 SET finish_time = $2,
-    error       = $3
+    error       = $3,
+    status      = $4,
+    failure     = $5
 WHERE id = $1
-RETURNING id, test_id, has_input, schedule_time, start_time, finish_time, error
+  AND status NOT IN ('cancelled', 'timed_out')
+RETURNING id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
 `
 
 type UpdateTestExecutionFinishedParams struct {
 	ID         test.TestExecutionID `json:"id"`
 	FinishTime Timestamp            `json:"finish_time"`
 	Error      *string              `json:"error"`
+	Status     test.ExecutionStatus `json:"status"`
+	Failure    json.RawMessage      `json:"failure"`
 }
 
 func (q *Queries) UpdateTestExecutionFinished(ctx context.Context, arg UpdateTestExecutionFinishedParams) (*TestExecution, error) {
-	row := q.db.QueryRow(ctx, updateTestExecutionFinished, arg.ID, arg.FinishTime, arg.Error)
+	row := q.db.QueryRow(ctx, updateTestExecutionFinished,
+		arg.ID,
+		arg.FinishTime,
+		arg.Error,
+		arg.Status,
+		arg.Failure,
+	)
 	var i TestExecution
 	err := row.Scan(
 		&i.ID,
@@ -182,6 +559,17 @@ func (q *Queries) UpdateTestExecutionFinished(ctx context.Context, arg UpdateTes
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.TestRunID,
+		&i.Attempt,
+		&i.RerunOfID,
+		&i.TestVersion,
+		&i.Failure,
+		&i.ParentID,
+		&i.ChildWorkflowID,
+		&i.ChildWorkflowType,
 	)
 	return &i, err
 }
@@ -190,9 +578,12 @@ const updateTestExecutionStarted = `-- name: UpdateTestExecutionStarted :one
 UPDATE test_executions
 SET start_time  = $2,
     finish_time = null,
-    error       = null
+    error       = null,
+    failure     = null,
+    status      = 'running'
 WHERE id = $1
-RETURNING id, test_id, has_input, schedule_time, start_time, finish_time, error
+  AND status NOT IN ('cancelled', 'timed_out')
+RETURNING id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
 `
 
 type UpdateTestExecutionStartedParams struct {
@@ -211,6 +602,58 @@ func (q *Queries) UpdateTestExecutionStarted(ctx context.Context, arg UpdateTest
 		&i.StartTime,
 		&i.FinishTime,
 		&i.Error,
+		&i.Status,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.TestRunID,
+		&i.Attempt,
+		&i.RerunOfID,
+		&i.TestVersion,
+		&i.Failure,
+		&i.ParentID,
+		&i.ChildWorkflowID,
+		&i.ChildWorkflowType,
+	)
+	return &i, err
+}
+
+const updateTestExecutionStopped = `-- name: UpdateTestExecutionStopped :one
+UPDATE test_executions
+SET finish_time = $2,
+    status      = $3
+WHERE id = $1
+  AND status IN ('scheduled', 'running')
+RETURNING id, test_id, has_input, schedule_time, start_time, finish_time, error, status, execution_timeout, run_timeout, test_run_id, attempt, rerun_of_id, test_version, failure, parent_id, child_workflow_id, child_workflow_type
+`
+
+type UpdateTestExecutionStoppedParams struct {
+	ID         test.TestExecutionID `json:"id"`
+	FinishTime Timestamp            `json:"finish_time"`
+	Status     test.ExecutionStatus `json:"status"`
+}
+
+func (q *Queries) UpdateTestExecutionStopped(ctx context.Context, arg UpdateTestExecutionStoppedParams) (*TestExecution, error) {
+	row := q.db.QueryRow(ctx, updateTestExecutionStopped, arg.ID, arg.FinishTime, arg.Status)
+	var i TestExecution
+	err := row.Scan(
+		&i.ID,
+		&i.TestID,
+		&i.HasInput,
+		&i.ScheduleTime,
+		&i.StartTime,
+		&i.FinishTime,
+		&i.Error,
+		&i.Status,
+		&i.ExecutionTimeout,
+		&i.RunTimeout,
+		&i.TestRunID,
+		&i.Attempt,
+		&i.RerunOfID,
+		&i.TestVersion,
+		&i.Failure,
+		&i.ParentID,
+		&i.ChildWorkflowID,
+		&i.ChildWorkflowType,
 	)
 	return &i, err
 }
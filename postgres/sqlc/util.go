@@ -3,6 +3,10 @@ package sqlc
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -61,6 +65,103 @@ func (ts *Timestamp) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+type Interval struct {
+	pgtype.Interval
+}
+
+func NewNullableInterval(d *time.Duration) Interval {
+	if d == nil {
+		return Interval{}
+	}
+	return Interval{
+		Interval: pgtype.Interval{
+			Microseconds: d.Microseconds(),
+			Valid:        true,
+		},
+	}
+}
+
+// Duration approximates months as 30 days. Intervals written by this package
+// only ever use microseconds.
+func (i Interval) Duration() time.Duration {
+	return time.Duration(i.Microseconds)*time.Microsecond +
+		time.Duration(i.Days)*24*time.Hour +
+		time.Duration(i.Months)*30*24*time.Hour
+}
+
+func (i Interval) Pointer() *time.Duration {
+	if !i.Valid {
+		return nil
+	}
+	return ptr.Get(i.Duration())
+}
+
+// UnmarshalJSON parses intervals in the default postgres output style, e.g.
+// "1 day 02:03:04.5" or "168:00:00", as sent by the notify event trigger.
+func (i *Interval) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if s == nil {
+		*i = Interval{}
+		return nil
+	}
+
+	parsed := pgtype.Interval{Valid: true}
+	fields := strings.Fields(*s)
+
+	for len(fields) > 0 {
+		if len(fields) >= 2 {
+			n, err := strconv.ParseInt(fields[0], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid interval '%s': %w", *s, err)
+			}
+			switch strings.TrimSuffix(fields[1], "s") {
+			case "year":
+				parsed.Months += int32(n) * 12
+			case "mon":
+				parsed.Months += int32(n)
+			case "day":
+				parsed.Days += int32(n)
+			default:
+				return fmt.Errorf("invalid interval '%s': unknown unit '%s'", *s, fields[1])
+			}
+			fields = fields[2:]
+			continue
+		}
+
+		clock := fields[0]
+		neg := strings.HasPrefix(clock, "-")
+		parts := strings.Split(strings.TrimLeft(clock, "+-"), ":")
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid interval '%s'", *s)
+		}
+		hours, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid interval '%s': %w", *s, err)
+		}
+		mins, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid interval '%s': %w", *s, err)
+		}
+		secs, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return fmt.Errorf("invalid interval '%s': %w", *s, err)
+		}
+		micros := (hours*3600+mins*60)*1_000_000 + int64(math.Round(secs*1_000_000))
+		if neg {
+			micros = -micros
+		}
+		parsed.Microseconds = micros
+		fields = fields[1:]
+	}
+
+	*i = Interval{Interval: parsed}
+	return nil
+}
+
 type Time struct {
 	time.Time
 }
@@ -2,9 +2,14 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
+	"github.com/annexsh/annex/internal/ptr"
 	"github.com/annexsh/annex/postgres/sqlc"
 
 	"github.com/annexsh/annex/test"
@@ -31,11 +36,14 @@ func (t *TestReader) GetTest(ctx context.Context, id uuid.UUID) (*test.Test, err
 	return marshalTest(tt), nil
 }
 
-func (t *TestReader) ListTests(ctx context.Context, contextID string, groupID string) (test.TestList, error) {
+func (t *TestReader) ListTests(ctx context.Context, contextID string, groupID string, filter *test.TestListFilter) (test.TestList, error) {
 	// TODO: pagination
 	tests, err := t.db.ListTests(ctx, sqlc.ListTestsParams{
-		ContextID: contextID,
-		GroupID:   groupID,
+		ContextID:         contextID,
+		GroupID:           groupID,
+		IncludeDeprecated: filter.IncludeDeprecated,
+		Tags:              filter.Tags,
+		Owner:             filter.Owner,
 	})
 	if err != nil {
 		return nil, err
@@ -43,12 +51,59 @@ func (t *TestReader) ListTests(ctx context.Context, contextID string, groupID st
 	return marshalTests(tests), nil
 }
 
+func (t *TestReader) SearchTests(ctx context.Context, filter *test.TestSearchFilter) (test.TestList, error) {
+	params := sqlc.SearchTestsParams{
+		Tags:              filter.Tags,
+		Owner:             filter.Owner,
+		IncludeDeprecated: filter.IncludeDeprecated,
+		LastCreateTime:    sqlc.NewNullableTimestamp(filter.LastCreateTime),
+		LastTestID:        filter.LastTestID,
+	}
+	if filter.NameContains != nil {
+		params.NamePattern = ptr.Get("%" + likeEscaper.Replace(*filter.NameContains) + "%")
+	}
+	if filter.PageSize > 0 {
+		params.PageSize = ptr.Get(int32(filter.PageSize))
+	}
+	tests, err := t.db.SearchTests(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTests(tests), nil
+}
+
+// likeEscaper escapes LIKE wildcards so that patterns match literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (t *TestReader) GetTestVersion(ctx context.Context, testID uuid.UUID, version int32) (*test.TestVersion, error) {
+	v, err := t.db.GetTestVersion(ctx, sqlc.GetTestVersionParams{
+		TestID:  testID,
+		Version: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestVersion(t.db.codec, v)
+}
+
+func (t *TestReader) ListTestVersions(ctx context.Context, testID uuid.UUID) (test.TestVersionList, error) {
+	versions, err := t.db.ListTestVersions(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestVersions(t.db.codec, versions)
+}
+
 func (t *TestReader) GetTestDefaultInput(ctx context.Context, testID uuid.UUID) (*test.Payload, error) {
 	payload, err := t.db.GetTestDefaultInput(ctx, testID)
 	if err != nil {
 		return nil, err
 	}
-	return marshalTestDefaultInput(payload), nil
+	input, err := marshalTestDefaultInput(payload)
+	if err != nil {
+		return nil, err
+	}
+	return test.DecodePayload(t.db.codec, input)
 }
 
 type TestWriter struct {
@@ -63,7 +118,7 @@ func (t *TestWriter) CreateTest(ctx context.Context, definition *test.TestDefini
 	var tt *test.Test
 
 	if err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
-		created, err := createTest(ctx, querier, definition)
+		created, err := createTest(ctx, querier, t.db.codec, definition)
 		if err != nil {
 			return err
 		}
@@ -81,7 +136,7 @@ func (t *TestWriter) CreateTests(ctx context.Context, definitions ...*test.TestD
 
 	if err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
 		for i, def := range definitions {
-			created, err := createTest(ctx, querier, def)
+			created, err := createTest(ctx, querier, t.db.codec, def)
 			if err != nil {
 				return err
 			}
@@ -95,24 +150,145 @@ func (t *TestWriter) CreateTests(ctx context.Context, definitions ...*test.TestD
 	return tests, nil
 }
 
-func createTest(ctx context.Context, querier sqlc.Querier, definition *test.TestDefinition) (*sqlc.Test, error) {
+func (t *TestWriter) DeprecateTests(ctx context.Context, contextID string, groupID string, keepNames []string) (test.TestList, error) {
+	if keepNames == nil {
+		keepNames = []string{} // a null array would match no tests
+	}
+	deprecated, err := t.db.DeprecateTests(ctx, sqlc.DeprecateTestsParams{
+		ContextID: contextID,
+		GroupID:   groupID,
+		KeepNames: keepNames,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalTests(deprecated), nil
+}
+
+func (t *TestWriter) MoveTest(ctx context.Context, id uuid.UUID, groupID string) (*test.Test, error) {
+	var tt *test.Test
+
+	if err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		existing, err := querier.GetTest(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		active, err := querier.HasActiveTestExecutions(ctx, sqlc.HasActiveTestExecutionsParams{
+			ContextID: existing.ContextID,
+			TestID:    &id,
+		})
+		if err != nil {
+			return err
+		}
+		if active {
+			return test.ErrorExecutionsActive
+		}
+
+		moved, err := querier.MoveTest(ctx, sqlc.MoveTestParams{
+			ID:      id,
+			GroupID: groupID,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return test.ErrorTestAlreadyExists
+			}
+			return err
+		}
+
+		tt = marshalTest(moved)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return tt, nil
+}
+
+func createTest(ctx context.Context, querier sqlc.Querier, codec test.PayloadCodec, definition *test.TestDefinition) (*sqlc.Test, error) {
+	var metadata test.TestMetadata
+	if definition.Metadata != nil {
+		metadata = *definition.Metadata
+	}
+	if metadata.Tags == nil {
+		metadata.Tags = []string{} // tags are not nullable
+	}
+
 	created, err := querier.CreateTest(ctx, sqlc.CreateTestParams{
-		ContextID: definition.ContextID,
-		GroupID:   definition.GroupID,
-		ID:        definition.TestID,
-		Name:      definition.Name,
-		HasInput:  definition.DefaultInput != nil,
+		ContextID:        definition.ContextID,
+		GroupID:          definition.GroupID,
+		ID:               definition.TestID,
+		Name:             definition.Name,
+		HasInput:         definition.DefaultInput != nil,
+		ExecutionTimeout: sqlc.NewNullableInterval(definition.ExecutionTimeout),
+		RunTimeout:       sqlc.NewNullableInterval(definition.RunTimeout),
+		InputSchema:      definition.InputSchema,
+		Description:      metadata.Description,
+		Tags:             metadata.Tags,
+		Owner:            metadata.Owner,
+		SourceLocation:   metadata.SourceLocation,
+		KeepMetadata:     definition.Metadata == nil,
 	})
 	if err != nil {
 		return nil, err
 	}
+
+	var inputMetadata, inputData []byte
 	if created.HasInput {
+		input, err := test.EncodePayload(codec, definition.DefaultInput)
+		if err != nil {
+			return nil, err
+		}
+		if inputMetadata, err = json.Marshal(input.Metadata); err != nil {
+			return nil, err
+		}
+		inputData = input.Data
 		if err = querier.CreateTestDefaultInput(ctx, sqlc.CreateTestDefaultInputParams{
-			TestID: created.ID,
-			Data:   definition.DefaultInput.Data,
+			TestID:   created.ID,
+			Data:     inputData,
+			Metadata: inputMetadata,
 		}); err != nil {
 			return nil, err
 		}
 	}
-	return created, nil
+
+	version := int32(1)
+
+	latest, err := querier.GetLatestTestVersion(ctx, created.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		latestVersion, err := marshalTestVersion(codec, latest)
+		if err != nil {
+			return nil, err
+		}
+		// Unset fields are kept from the previous registration
+		effective := *definition
+		effective.ExecutionTimeout = created.ExecutionTimeout.Pointer()
+		effective.RunTimeout = created.RunTimeout.Pointer()
+		effective.InputSchema = created.InputSchema
+		if latestVersion.Matches(&effective) {
+			return created, nil
+		}
+		version = latest.Version + 1
+	}
+
+	if _, err = querier.CreateTestVersion(ctx, sqlc.CreateTestVersionParams{
+		TestID:               created.ID,
+		Version:              version,
+		HasInput:             created.HasInput,
+		DefaultInputMetadata: inputMetadata,
+		DefaultInputData:     inputData,
+		ExecutionTimeout:     created.ExecutionTimeout,
+		RunTimeout:           created.RunTimeout,
+		InputSchema:          created.InputSchema,
+	}); err != nil {
+		return nil, err
+	}
+
+	return querier.UpdateTestVersion(ctx, sqlc.UpdateTestVersionParams{
+		ID:      created.ID,
+		Version: version,
+	})
 }
@@ -30,9 +30,12 @@ type TestExecutionEventSource struct {
 	pgConn      *pgx.Conn
 	connRelease func()
 	ctxCancel   context.CancelFunc
+	codec       test.PayloadCodec
 }
 
-func NewTestExecutionEventSource(ctx context.Context, pgPool *pgxpool.Pool, opts ...conc.BrokerOption) (*TestExecutionEventSource, error) {
+// NewTestExecutionEventSource creates an event source of the executions
+// stored in the database. The codec must be the payload codec of the database.
+func NewTestExecutionEventSource(ctx context.Context, pgPool *pgxpool.Pool, codec test.PayloadCodec, opts ...conc.BrokerOption) (*TestExecutionEventSource, error) {
 	conn, err := pgPool.Acquire(ctx)
 	if err != nil {
 		return nil, err
@@ -47,6 +50,7 @@ func NewTestExecutionEventSource(ctx context.Context, pgPool *pgxpool.Pool, opts
 		broker:      conc.NewBroker[*eventservice.ExecutionEvent](opts...),
 		pgConn:      pgConn,
 		connRelease: conn.Release,
+		codec:       codec,
 	}, nil
 }
 
@@ -103,17 +107,22 @@ func (t *TestExecutionEventSource) handleNextEvent(ctx context.Context) error {
 		if err = json.Unmarshal([]byte(notif.Payload), &msg); err != nil {
 			return err
 		}
-		testExec := marshalTestExec(msg.Data)
+		testExec := marshalTestExec(t.codec, msg.Data)
 
 		switch tableMsg.Action {
 		case pgInsert:
 			execEvent = eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionScheduled, testExec)
 		case pgUpdate:
-			if msg.Data.StartTime.Valid && !msg.Data.FinishTime.Valid {
+			switch msg.Data.Status {
+			case test.ExecutionStatusScheduled: // reset
+				execEvent = eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionRetried, testExec)
+			case test.ExecutionStatusRunning:
 				execEvent = eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionStarted, testExec)
-			} else if msg.Data.StartTime.Valid && msg.Data.FinishTime.Valid {
+			case test.ExecutionStatusPassed, test.ExecutionStatusFailed, test.ExecutionStatusTimedOut:
 				execEvent = eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionFinished, testExec)
-			} else {
+			case test.ExecutionStatusCancelled:
+				execEvent = eventservice.NewTestExecutionEvent(eventservice.TypeTestExecutionCancelled, testExec)
+			default:
 				// TODO: log unexpected state error
 				return nil
 			}
@@ -124,17 +133,28 @@ func (t *TestExecutionEventSource) handleNextEvent(ctx context.Context) error {
 			return err
 		}
 
-		caseExec := marshalCaseExec(msg.Data)
+		caseExec := marshalCaseExec(t.codec, msg.Data)
 
 		switch tableMsg.Action {
 		case pgInsert:
-			execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionScheduled, caseExec)
+			execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionScheduled, caseExec, tableMsg.TestExecutionAttempt)
 		case pgUpdate:
-			if msg.Data.StartTime.Valid && !msg.Data.FinishTime.Valid {
-				execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionStarted, caseExec)
-			} else if msg.Data.StartTime.Valid && msg.Data.FinishTime.Valid {
-				execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionFinished, caseExec)
-			} else {
+			switch msg.Data.Status {
+			case test.ExecutionStatusScheduled: // reset
+				execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionScheduled, caseExec, tableMsg.TestExecutionAttempt)
+			case test.ExecutionStatusRunning:
+				if msg.Data.HeartbeatTime.Valid {
+					// Progress is left out of notifications: the event carries
+					// the heartbeat time only.
+					execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionProgressed, caseExec, tableMsg.TestExecutionAttempt)
+				} else {
+					execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionStarted, caseExec, tableMsg.TestExecutionAttempt)
+				}
+			case test.ExecutionStatusPassed, test.ExecutionStatusFailed, test.ExecutionStatusTimedOut:
+				execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionFinished, caseExec, tableMsg.TestExecutionAttempt)
+			case test.ExecutionStatusCancelled:
+				execEvent = eventservice.NewCaseExecutionEvent(eventservice.TypeCaseExecutionCancelled, caseExec, tableMsg.TestExecutionAttempt)
+			default:
 				// TODO: log unexpected state error
 				return nil
 			}
@@ -160,8 +180,9 @@ func (t *TestExecutionEventSource) handleNextEvent(ctx context.Context) error {
 }
 
 type tableMessage struct {
-	Table  string `json:"table"`
-	Action string `json:"action"`
+	Table                string `json:"table"`
+	Action               string `json:"action"`
+	TestExecutionAttempt int32  `json:"test_execution_attempt"` // case executions only
 }
 
 type eventMessage[T any] struct {
@@ -2,9 +2,12 @@ package postgres
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/annexsh/annex/postgres/sqlc"
 
@@ -30,7 +33,7 @@ func (t *TestExecutionReader) GetTestExecution(ctx context.Context, id test.Test
 	if err != nil {
 		return nil, err
 	}
-	return marshalTestExec(exec), nil
+	return marshalTestExec(t.db.codec, exec), nil
 }
 
 func (t *TestExecutionReader) GetTestExecutionInput(ctx context.Context, id test.TestExecutionID) (*test.Payload, error) {
@@ -38,7 +41,11 @@ func (t *TestExecutionReader) GetTestExecutionInput(ctx context.Context, id test
 	if err != nil {
 		return nil, err
 	}
-	return marshalTestExecPayload(payload), nil
+	input, err := marshalTestExecPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	return test.DecodePayload(t.db.codec, input)
 }
 
 func (t *TestExecutionReader) ListTestExecutions(ctx context.Context, testID uuid.UUID, filter *test.TestExecutionListFilter) (test.TestExecutionList, error) {
@@ -46,6 +53,15 @@ func (t *TestExecutionReader) ListTestExecutions(ctx context.Context, testID uui
 		TestID:           testID,
 		LastScheduleTime: sqlc.NewNullableTimestamp(filter.LastScheduleTime),
 		LastExecID:       filter.LastTestExecutionID,
+		ScheduledAfter:   sqlc.NewNullableTimestamp(filter.ScheduledAfter),
+		ScheduledBefore:  sqlc.NewNullableTimestamp(filter.ScheduledBefore),
+		ErrorContains:    filter.ErrorContains,
+	}
+	if filter.LastTestExecutionID != nil {
+		params.LastTestExecutionID = *filter.LastTestExecutionID
+	}
+	for _, status := range filter.Statuses {
+		params.Statuses = append(params.Statuses, status.String())
 	}
 	if filter.PageSize > 0 {
 		params.PageSize = ptr.Get(int32(filter.PageSize))
@@ -54,13 +70,79 @@ func (t *TestExecutionReader) ListTestExecutions(ctx context.Context, testID uui
 	if err != nil {
 		return nil, err
 	}
-	return marshalTestExecs(execs), nil
+	return marshalTestExecs(t.db.codec, execs), nil
+}
+
+func (t *TestExecutionReader) ListRecentTestExecutions(ctx context.Context, contextID string, filter *test.RecentTestExecutionListFilter) (test.TestExecutionSummaryList, error) {
+	params := sqlc.ListRecentTestExecutionsParams{
+		ContextID:           contextID,
+		GroupID:             filter.GroupID,
+		LastScheduleTime:    sqlc.NewNullableTimestamp(filter.LastScheduleTime),
+		LastTestExecutionID: filter.LastTestExecutionID,
+	}
+	if filter.PageSize > 0 {
+		params.PageSize = ptr.Get(int32(filter.PageSize))
+	}
+	rows, err := t.db.ListRecentTestExecutions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestExecSummaries(t.db.codec, rows), nil
+}
+
+func (t *TestExecutionReader) ListExpiredTestExecutions(ctx context.Context, now time.Time) (test.TestExecutionList, error) {
+	execs, err := t.db.ListExpiredTestExecutions(ctx, sqlc.NewTimestamp(now))
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestExecs(t.db.codec, execs), nil
+}
+
+func (t *TestExecutionReader) GetTestExecutionAttempt(ctx context.Context, id test.TestExecutionID, attempt int32) (*test.TestExecutionAttempt, error) {
+	a, err := t.db.GetTestExecutionAttempt(ctx, sqlc.GetTestExecutionAttemptParams{
+		TestExecutionID: id,
+		Attempt:         attempt,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorAttemptNotFound
+		}
+		return nil, err
+	}
+	return marshalTestExecAttempt(t.db.codec, a), nil
+}
+
+func (t *TestExecutionReader) ListTestExecutionAttempts(ctx context.Context, id test.TestExecutionID) (test.TestExecutionAttemptList, error) {
+	attempts, err := t.db.ListTestExecutionAttempts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestExecAttempts(t.db.codec, attempts), nil
 }
 
 type TestExecutionWriter struct {
 	db *DB
 }
 
+func (t *TestExecutionReader) GetSubTestExecution(ctx context.Context, childWorkflowID string) (*test.TestExecution, error) {
+	exec, err := t.db.GetSubTestExecution(ctx, &childWorkflowID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorTestExecutionNotFound
+		}
+		return nil, err
+	}
+	return marshalTestExec(t.db.codec, exec), nil
+}
+
+func (t *TestExecutionReader) ListSubTestExecutions(ctx context.Context, parentID test.TestExecutionID) (test.TestExecutionList, error) {
+	execs, err := t.db.ListSubTestExecutions(ctx, &parentID)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestExecs(t.db.codec, execs), nil
+}
+
 func NewTestExecutionWriter(db *DB) *TestExecutionWriter {
 	return &TestExecutionWriter{db: db}
 }
@@ -69,31 +151,79 @@ func (t *TestExecutionWriter) CreateScheduledTestExecution(ctx context.Context,
 	var testExec *sqlc.TestExecution
 
 	err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
-		exec, err := t.db.CreateTestExecution(ctx, sqlc.CreateTestExecutionParams{
-			ID:           scheduled.ID,
-			TestID:       scheduled.TestID,
-			HasInput:     scheduled.Payload != nil,
-			ScheduleTime: sqlc.NewTimestamp(scheduled.ScheduleTime),
-		})
+		var err error
+		testExec, err = createTestExecution(ctx, querier, t.db.codec, scheduled)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalTestExec(t.db.codec, testExec), nil
+}
+
+func createTestExecution(ctx context.Context, querier sqlc.Querier, codec test.PayloadCodec, scheduled *test.ScheduledTestExecution) (*sqlc.TestExecution, error) {
+	exec, err := querier.CreateTestExecution(ctx, sqlc.CreateTestExecutionParams{
+		ID:               scheduled.ID,
+		TestID:           scheduled.TestID,
+		HasInput:         scheduled.Input != nil,
+		ScheduleTime:     sqlc.NewTimestamp(scheduled.ScheduleTime),
+		ExecutionTimeout: sqlc.NewNullableInterval(&scheduled.ExecutionTimeout),
+		RunTimeout:       sqlc.NewNullableInterval(scheduled.RunTimeout),
+		TestRunID:        scheduled.TestRunID,
+		RerunOfID:        scheduled.RerunOfID,
+		TestVersion:      scheduled.TestVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if exec.HasInput {
+		input, err := test.EncodePayload(codec, scheduled.Input)
 		if err != nil {
+			return nil, err
+		}
+		metadata, err := json.Marshal(input.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if err = querier.CreateTestExecutionInput(ctx, sqlc.CreateTestExecutionInputParams{
+			TestExecutionID: exec.ID,
+			Data:            input.Data,
+			Metadata:        metadata,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return exec, nil
+}
+
+func (t *TestExecutionWriter) CreateScheduledSubTestExecution(ctx context.Context, scheduled *test.ScheduledSubTestExecution) (*test.TestExecution, error) {
+	var exec *sqlc.TestExecution
+
+	err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		if err := querier.CreateSubTestExecution(ctx, sqlc.CreateSubTestExecutionParams{
+			ID:                scheduled.ID,
+			ScheduleTime:      sqlc.NewTimestamp(scheduled.ScheduleTime),
+			ChildWorkflowID:   &scheduled.ChildWorkflowID,
+			ChildWorkflowType: &scheduled.ChildWorkflowType,
+			ParentID:          scheduled.ParentID,
+		}); err != nil {
 			return err
 		}
-		if exec.HasInput {
-			if err = querier.CreateTestExecutionInput(ctx, sqlc.CreateTestExecutionInputParams{
-				TestExecutionID: exec.ID,
-				Data:            scheduled.Payload,
-			}); err != nil {
-				return err
-			}
+		var err error
+		// Nothing is created when the parent does not exist
+		if exec, err = querier.GetTestExecution(ctx, scheduled.ID); errors.Is(err, pgx.ErrNoRows) {
+			return test.ErrorTestExecutionNotFound
 		}
-		testExec = exec
-		return nil
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return marshalTestExec(testExec), nil
+	return marshalTestExec(t.db.codec, exec), nil
 }
 
 func (t *TestExecutionWriter) UpdateStartedTestExecution(ctx context.Context, started *test.StartedTestExecution) (*test.TestExecution, error) {
@@ -101,73 +231,189 @@ func (t *TestExecutionWriter) UpdateStartedTestExecution(ctx context.Context, st
 		ID:        started.ID,
 		StartTime: sqlc.NewTimestamp(started.StartTime),
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Stopped test executions are not restarted by late acks
+		exec, err = t.db.GetTestExecution(ctx, started.ID)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return marshalTestExec(exec), nil
+	return marshalTestExec(t.db.codec, exec), nil
 }
 
 func (t *TestExecutionWriter) UpdateFinishedTestExecution(ctx context.Context, finished *test.FinishedTestExecution) (*test.TestExecution, error) {
+	failure, err := encodeFailure(t.db.codec, finished.Failure)
+	if err != nil {
+		return nil, err
+	}
 	exec, err := t.db.UpdateTestExecutionFinished(ctx, sqlc.UpdateTestExecutionFinishedParams{
 		ID:         finished.ID,
 		FinishTime: sqlc.NewTimestamp(finished.FinishTime),
 		Error:      finished.Error,
+		Status:     finished.Status(),
+		Failure:    failure,
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Stopped test executions keep their final status
+		exec, err = t.db.GetTestExecution(ctx, finished.ID)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return marshalTestExec(exec), nil
+	return marshalTestExec(t.db.codec, exec), nil
+}
+
+func (t *TestExecutionWriter) UpdateCancelledTestExecution(ctx context.Context, cancelled *test.CancelledTestExecution) (*test.TestExecution, error) {
+	return t.stopTestExecution(ctx, cancelled.ID, cancelled.CancelTime, test.ExecutionStatusCancelled)
+}
+
+func (t *TestExecutionWriter) UpdateTimedOutTestExecution(ctx context.Context, timedOut *test.TimedOutTestExecution) (*test.TestExecution, error) {
+	return t.stopTestExecution(ctx, timedOut.ID, timedOut.TimeoutTime, test.ExecutionStatusTimedOut)
 }
 
-func (t *TestExecutionWriter) ResetTestExecution(ctx context.Context, reset *test.ResetTestExecution) (*test.TestExecution, test.ResetRollback, error) {
-	existing, err := t.db.GetTestExecution(ctx, reset.ID)
+// stopTestExecution finishes an open test execution, and its open case
+// executions, case execution attempts and sub-executions, with a status that
+// was not reported by the test itself.
+func (t *TestExecutionWriter) stopTestExecution(ctx context.Context, id test.TestExecutionID, stopTime time.Time, status test.ExecutionStatus) (*test.TestExecution, error) {
+	var testExec *sqlc.TestExecution
+
+	err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		existing, err := querier.GetTestExecution(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return test.ErrorTestExecutionNotFound
+			}
+			return err
+		}
+		if existing.Status.IsFinal() {
+			return test.ErrorTestExecutionFinished
+		}
+		testExec, err = stopOpenTestExecution(ctx, querier, id, stopTime, status)
+		return err
+	})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	return marshalTestExec(t.db.codec, testExec), nil
+}
+
+func stopOpenTestExecution(ctx context.Context, querier sqlc.Querier, id test.TestExecutionID, stopTime time.Time, status test.ExecutionStatus) (*sqlc.TestExecution, error) {
+	if err := querier.UpdateOpenCaseExecutionsStopped(ctx, sqlc.UpdateOpenCaseExecutionsStoppedParams{
+		TestExecutionID: id,
+		FinishTime:      sqlc.NewTimestamp(stopTime),
+		Status:          status,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := querier.UpdateOpenCaseExecutionAttemptsStopped(ctx, sqlc.UpdateOpenCaseExecutionAttemptsStoppedParams{
+		TestExecutionID: id,
+		FinishTime:      sqlc.NewTimestamp(stopTime),
+		Status:          status,
+	}); err != nil {
+		return nil, err
 	}
 
-	tx, querier, err := t.db.WithTx(ctx)
+	subExecs, err := querier.ListSubTestExecutions(ctx, &id)
+	if err != nil {
+		return nil, err
+	}
+	for _, subExec := range subExecs {
+		if subExec.Status.IsFinal() {
+			continue
+		}
+		// Sub-executions that finished since they were listed keep their final status
+		if _, err = stopOpenTestExecution(ctx, querier, subExec.ID, stopTime, status); err != nil && !errors.Is(err, test.ErrorTestExecutionFinished) {
+			return nil, err
+		}
+	}
+
+	exec, err := querier.UpdateTestExecutionStopped(ctx, sqlc.UpdateTestExecutionStoppedParams{
+		ID:         id,
+		FinishTime: sqlc.NewTimestamp(stopTime),
+		Status:     status,
+	})
 	if err != nil {
-		return nil, nil, err
+		// Finished since it was read
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorTestExecutionFinished
+		}
+		return nil, err
 	}
+	return exec, nil
+}
+
+func (t *TestExecutionWriter) ResetTestExecution(ctx context.Context, reset *test.ResetTestExecution, resetWorkflow func(ctx context.Context) error) (*test.TestExecution, error) {
+	var testExec *sqlc.TestExecution
 
-	defer func() {
+	err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		existing, err := querier.GetTestExecution(ctx, reset.ID)
 		if err != nil {
-			if rbErr := tx.Rollback(ctx); err != nil {
-				err = fmt.Errorf("%w; failed to rollback reset workflow transaction: %w", err, rbErr)
+			return err
+		}
+
+		// Archive the current attempt before its stale history is removed so that
+		// earlier attempts remain viewable after a retry.
+		if _, err = querier.CreateTestExecutionAttempt(ctx, reset.ID); err != nil {
+			return err
+		}
+		if err = querier.CreateAttemptCaseExecutions(ctx, reset.ID); err != nil {
+			return err
+		}
+		if err = querier.CreateAttemptCaseExecutionAttempts(ctx, reset.ID); err != nil {
+			return err
+		}
+		if err = querier.CreateAttemptCaseExecutionInputs(ctx, reset.ID); err != nil {
+			return err
+		}
+		if err = querier.CreateAttemptCaseExecutionResults(ctx, reset.ID); err != nil {
+			return err
+		}
+		if err = querier.CreateAttemptLogs(ctx, reset.ID); err != nil {
+			return err
+		}
+
+		for _, caseExecID := range reset.StaleCaseExecutions {
+			if err = querier.DeleteCaseExecution(ctx, sqlc.DeleteCaseExecutionParams{
+				ID:              caseExecID,
+				TestExecutionID: reset.ID,
+			}); err != nil {
+				return err
 			}
 		}
-	}()
 
-	for _, caseExecID := range reset.StaleCaseExecutions {
-		if err = querier.DeleteCaseExecution(ctx, sqlc.DeleteCaseExecutionParams{
-			ID:              caseExecID,
-			TestExecutionID: reset.ID,
-		}); err != nil {
-			return nil, nil, err
+		for _, logID := range reset.StaleLogs {
+			if err = querier.DeleteLog(ctx, logID); err != nil {
+				return err
+			}
 		}
-	}
 
-	for _, logID := range reset.StaleLogs {
-		if err = querier.DeleteLog(ctx, logID); err != nil {
-			return nil, nil, err
+		// CreateTestExecution is idempotent. On conflict, it resets the existing
+		// workflow to a new scheduled state matching the params below and
+		// increments the attempt.
+		testExec, err = querier.CreateTestExecution(ctx, sqlc.CreateTestExecutionParams{
+			ID:               reset.ID,
+			TestID:           existing.TestID,
+			HasInput:         existing.HasInput,
+			ScheduleTime:     sqlc.NewTimestamp(reset.ResetTime),
+			ExecutionTimeout: existing.ExecutionTimeout,
+			RunTimeout:       existing.RunTimeout,
+			TestRunID:        existing.TestRunID,
+			RerunOfID:        existing.RerunOfID,
+			TestVersion:      existing.TestVersion,
+		})
+		if err != nil {
+			return err
 		}
-	}
 
-	// CreateTestExecution is idempotent. On conflict, it resets the existing
-	// workflow to a new scheduled state matching the params below.
-	testExec, err := querier.CreateTestExecution(ctx, sqlc.CreateTestExecutionParams{
-		ID:           reset.ID,
-		TestID:       existing.TestID,
-		HasInput:     existing.HasInput,
-		ScheduleTime: sqlc.NewTimestamp(reset.ResetTime),
+		// The archived attempt and deleted history are rolled back if the
+		// workflow fails to reset
+		return resetWorkflow(ctx)
 	})
 	if err != nil {
-		return nil, nil, err
-	}
-
-	if err = tx.Commit(ctx); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return marshalTestExec(testExec), tx.Rollback, nil
+	return marshalTestExec(t.db.codec, testExec), nil
 }
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/internal/ptr"
+	"github.com/annexsh/annex/test"
+)
+
+func TestTestExecutionReader_ListExpiredTestExecutions(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewTestRepository(db)
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+	require.NoError(t, repo.CreateContext(ctx, contextID))
+	require.NoError(t, repo.CreateGroup(ctx, contextID, groupID, uuid.NewString()))
+	tt, err := repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID)))
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+
+	genOpen := func(scheduleTime time.Time, timeout time.Duration, runTimeout *time.Duration) *test.TestExecution {
+		scheduled := fake.GenScheduledTestExec(tt.ID)
+		scheduled.ScheduleTime = scheduleTime
+		scheduled.ExecutionTimeout = timeout
+		scheduled.RunTimeout = runTimeout
+		te, err := repo.CreateScheduledTestExecution(ctx, scheduled)
+		require.NoError(t, err)
+		return te
+	}
+
+	expired := genOpen(now.Add(-2*time.Hour), time.Hour, nil)
+	expiredRun := genOpen(now.Add(-30*time.Minute), time.Hour, ptr.Get(10*time.Minute))
+	open := genOpen(now.Add(-time.Minute), time.Hour, nil)
+	openRun := genOpen(now.Add(-30*time.Minute), time.Hour, ptr.Get(45*time.Minute))
+
+	got, err := repo.ListExpiredTestExecutions(ctx, now)
+	require.NoError(t, err)
+
+	// The database is shared with other tests
+	gotIDs := map[test.TestExecutionID]bool{}
+	for _, te := range got {
+		gotIDs[te.ID] = true
+	}
+	assert.True(t, gotIDs[expired.ID])
+	assert.True(t, gotIDs[expiredRun.ID])
+	assert.False(t, gotIDs[open.ID])
+	assert.False(t, gotIDs[openRun.ID])
+}
+
+func TestTestExecutionWriter_UpdateCancelledTestExecution(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewTestRepository(db)
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+	require.NoError(t, repo.CreateContext(ctx, contextID))
+	require.NoError(t, repo.CreateGroup(ctx, contextID, groupID, uuid.NewString()))
+	tt, err := repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID)))
+	require.NoError(t, err)
+
+	_, err = repo.UpdateCancelledTestExecution(ctx, &test.CancelledTestExecution{
+		ID:         test.NewTestExecutionID(),
+		CancelTime: time.Now().UTC(),
+	})
+	require.ErrorIs(t, err, test.ErrorTestExecutionNotFound)
+
+	te, err := repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+
+	cancelled := &test.CancelledTestExecution{
+		ID:         te.ID,
+		CancelTime: time.Now().UTC(),
+	}
+	got, err := repo.UpdateCancelledTestExecution(ctx, cancelled)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusCancelled, got.Status)
+
+	// Stopped test executions keep their final status
+	_, err = repo.UpdateTimedOutTestExecution(ctx, &test.TimedOutTestExecution{
+		ID:          te.ID,
+		TimeoutTime: time.Now().UTC(),
+	})
+	require.ErrorIs(t, err, test.ErrorTestExecutionFinished)
+}
@@ -16,6 +16,10 @@ func NewTestRepository(db *DB) test.Repository {
 		CaseExecutionWriter: NewCaseExecutionWriter(db),
 		LogReader:           NewLogReader(db),
 		LogWriter:           NewLogWriter(db),
+		ScheduleReader:      NewScheduleReader(db),
+		ScheduleWriter:      NewScheduleWriter(db),
+		TestRunReader:       NewTestRunReader(db),
+		TestRunWriter:       NewTestRunWriter(db),
 	}
 }
 
@@ -32,4 +36,8 @@ type testRepository struct {
 	*CaseExecutionWriter
 	*LogReader
 	*LogWriter
+	*ScheduleReader
+	*ScheduleWriter
+	*TestRunReader
+	*TestRunWriter
 }
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/annexsh/annex/postgres/sqlc"
+
+	"github.com/annexsh/annex/test"
+)
+
+var (
+	_ test.TestRunReader = (*TestRunReader)(nil)
+	_ test.TestRunWriter = (*TestRunWriter)(nil)
+)
+
+type TestRunReader struct {
+	db *DB
+}
+
+func NewTestRunReader(db *DB) *TestRunReader {
+	return &TestRunReader{db: db}
+}
+
+func (t *TestRunReader) GetTestRun(ctx context.Context, id uuid.UUID) (*test.TestRun, error) {
+	run, err := t.db.GetTestRun(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, test.ErrorTestRunNotFound
+		}
+		return nil, err
+	}
+	execs, err := t.db.ListTestRunExecutions(ctx, &id)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestRun(t.db.codec, run, execs), nil
+}
+
+func (t *TestRunReader) ListTestRunExecutions(ctx context.Context, id uuid.UUID) (test.TestExecutionList, error) {
+	execs, err := t.db.ListTestRunExecutions(ctx, &id)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTestExecs(t.db.codec, execs), nil
+}
+
+type TestRunWriter struct {
+	db *DB
+}
+
+func NewTestRunWriter(db *DB) *TestRunWriter {
+	return &TestRunWriter{db: db}
+}
+
+func (t *TestRunWriter) CreateTestRun(ctx context.Context, definition *test.TestRunDefinition) (*test.TestRun, error) {
+	var run *sqlc.TestRun
+	var execs []*sqlc.TestExecution
+
+	err := t.db.ExecuteTx(ctx, func(querier sqlc.Querier) error {
+		var err error
+		run, err = querier.CreateTestRun(ctx, sqlc.CreateTestRunParams{
+			ID:        definition.ID,
+			ContextID: definition.ContextID,
+			GroupID:   definition.GroupID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, scheduled := range definition.Executions {
+			scheduled := *scheduled
+			scheduled.TestRunID = &run.ID
+			exec, err := createTestExecution(ctx, querier, t.db.codec, &scheduled)
+			if err != nil {
+				return err
+			}
+			execs = append(execs, exec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalTestRun(t.db.codec, run, execs), nil
+}
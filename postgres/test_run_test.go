@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/test"
+)
+
+func TestTestRunWriter_CreateTestRun_executions(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewTestRunReader(db)
+	w := NewTestRunWriter(db)
+
+	_, err := r.GetTestRun(ctx, uuid.New())
+	require.ErrorIs(t, err, test.ErrorTestRunNotFound)
+
+	contextID := uuid.NewString()
+	require.NoError(t, NewContextWriter(db).CreateContext(ctx, contextID))
+
+	// Nothing is created when a test execution cannot be created
+	def := &test.TestRunDefinition{
+		ID:         uuid.New(),
+		ContextID:  contextID,
+		Executions: []*test.ScheduledTestExecution{fake.GenScheduledTestExec(uuid.New())},
+	}
+	_, err = w.CreateTestRun(ctx, def)
+	require.Error(t, err)
+
+	_, err = r.GetTestRun(ctx, def.ID)
+	assert.ErrorIs(t, err, test.ErrorTestRunNotFound)
+}
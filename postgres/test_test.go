@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/test"
+)
+
+func TestTestReader_SearchTests_archived(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewTestRepository(db)
+
+	// The database is shared with other tests
+	owner := uuid.NewString()
+
+	genTest := func() *test.Test {
+		contextID := uuid.NewString()
+		groupID := uuid.NewString()
+		require.NoError(t, repo.CreateContext(ctx, contextID))
+		require.NoError(t, repo.CreateGroup(ctx, contextID, groupID, uuid.NewString()))
+		def := fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID))
+		def.Metadata.Owner = owner
+		tt, err := repo.CreateTest(ctx, def)
+		require.NoError(t, err)
+		return tt
+	}
+
+	active := genTest()
+	archivedGroup := genTest()
+	require.NoError(t, repo.UpdateGroupArchived(ctx, archivedGroup.ContextID, archivedGroup.GroupID, true))
+	archivedContext := genTest()
+	require.NoError(t, repo.UpdateContextArchived(ctx, archivedContext.ContextID, true))
+
+	got, err := repo.SearchTests(ctx, &test.TestSearchFilter{Owner: owner})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, active.ID, got[0].ID)
+}
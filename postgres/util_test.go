@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testSchemaVersion is the latest migration version.
+const testSchemaVersion = 24
+
+// newTestDB opens the database at ANNEX_TEST_POSTGRES_URL, e.g. the database
+// of the compose stack. Tests are skipped if it is not set.
+func newTestDB(t *testing.T) *DB {
+	url := os.Getenv("ANNEX_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("ANNEX_TEST_POSTGRES_URL not set")
+	}
+
+	pool, err := OpenPool(context.Background(), url, testSchemaVersion)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return NewDB(pool)
+}
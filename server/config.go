@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/base64"
 	"fmt"
 
 	"github.com/cristalhq/aconfig"
@@ -25,15 +26,19 @@ func (e Env) validate() error {
 }
 
 type Config struct {
-	Env      Env      `yaml:"env"`
-	Port     int      `yaml:"port" required:"true"`
-	Temporal Temporal `yaml:"temporal"`
-	Postgres Postgres `yaml:"postgres"`
-	InMemory bool     `yaml:"inMemory"` // temporary option during initial development phase (overrides Postgres when set)
+	Env               Env               `yaml:"env"`
+	Port              int               `yaml:"port" required:"true"`
+	Temporal          Temporal          `yaml:"temporal"`
+	Postgres          Postgres          `yaml:"postgres"`
+	PayloadEncryption PayloadEncryption `yaml:"payloadEncryption"`
+	InMemory          bool              `yaml:"inMemory"` // temporary option during initial development phase (overrides Postgres when set)
 }
 
 func (c Config) Validate() error {
-	return c.Env.validate()
+	if err := c.Env.validate(); err != nil {
+		return err
+	}
+	return c.PayloadEncryption.validate()
 }
 
 type Temporal struct {
@@ -55,6 +60,40 @@ func (p Postgres) URL() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s", p.User, p.Password, p.Host, p.Port, p.Database)
 }
 
+// PayloadEncryption encrypts test inputs at rest with AES-GCM. Inputs are
+// stored unencrypted when no keys are set.
+type PayloadEncryption struct {
+	KeyID string            `yaml:"keyId"` // key used to encrypt new inputs
+	Keys  map[string]string `yaml:"keys"`  // base64 encoded 16, 24 or 32 byte keys by ID (old keys decrypt existing inputs)
+}
+
+func (p PayloadEncryption) Enabled() bool {
+	return len(p.Keys) > 0
+}
+
+func (p PayloadEncryption) DecodedKeys() (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(p.Keys))
+	for id, encoded := range p.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload encryption key '%s': %w", id, err)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+func (p PayloadEncryption) validate() error {
+	if !p.Enabled() {
+		return nil
+	}
+	if _, ok := p.Keys[p.KeyID]; !ok {
+		return fmt.Errorf("payload encryption key '%s' not found", p.KeyID)
+	}
+	_, err := p.DecodedKeys()
+	return err
+}
+
 func LoadConfig(opts ...ConfigOption) (Config, error) {
 	loaderCfg := aconfig.Config{
 		FileDecoders: map[string]aconfig.FileDecoder{},
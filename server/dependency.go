@@ -26,7 +26,7 @@ type dependencies struct {
 	close        func()
 }
 
-func setupPostgresDeps(ctx context.Context, url string, schemaVersion uint) (*dependencies, error) {
+func setupPostgresDeps(ctx context.Context, url string, schemaVersion uint, codec test.PayloadCodec) (*dependencies, error) {
 	pgPool, err := postgres.OpenPool(ctx, url, schemaVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open db connection for %s: %w", url, err)
@@ -36,10 +36,10 @@ func setupPostgresDeps(ctx context.Context, url string, schemaVersion uint) (*de
 		healthChecks: []health.DependencyChecker{health.WithPostgres(pgPool)},
 	}
 
-	db := postgres.NewDB(pgPool)
+	db := postgres.NewDB(pgPool, postgres.WithPayloadCodec(codec))
 	deps.repo = postgres.NewTestRepository(db)
 
-	eventSrc, err := postgres.NewTestExecutionEventSource(ctx, pgPool)
+	eventSrc, err := postgres.NewTestExecutionEventSource(ctx, pgPool, codec)
 	if err != nil {
 		return nil, err
 	}
@@ -55,12 +55,12 @@ func setupPostgresDeps(ctx context.Context, url string, schemaVersion uint) (*de
 }
 
 // Temporary option during initial development phase
-func setupInMemoryDeps(ctx context.Context) *dependencies {
+func setupInMemoryDeps(ctx context.Context, codec test.PayloadCodec) *dependencies {
 	deps := &dependencies{
 		errs: make(chan error), // will never be published to
 	}
 
-	db := inmem.NewDB()
+	db := inmem.NewDB(inmem.WithPayloadCodec(codec))
 	deps.repo = inmem.NewTestRepository(db)
 	eventSrc := db.TestExecutionEventSource()
 	eventSrc.Start(ctx)
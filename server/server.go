@@ -13,10 +13,12 @@ import (
 	"go.temporal.io/sdk/client"
 	grpchealthv1 "google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/annexsh/annex/codec"
 	"github.com/annexsh/annex/eventservice"
 	"github.com/annexsh/annex/internal/health"
 	"github.com/annexsh/annex/internal/rpc"
 	"github.com/annexsh/annex/log"
+	"github.com/annexsh/annex/test"
 	"github.com/annexsh/annex/testservice"
 	"github.com/annexsh/annex/workflowservice"
 )
@@ -25,6 +27,7 @@ type Option func(opts *serverOptions)
 
 type serverOptions struct {
 	logger log.Logger
+	codec  test.PayloadCodec
 }
 
 func WithLogger(logger log.Logger) Option {
@@ -33,6 +36,14 @@ func WithLogger(logger log.Logger) Option {
 	}
 }
 
+// WithPayloadCodec encodes test inputs at rest with a custom codec. It
+// overrides the payload encryption config.
+func WithPayloadCodec(codec test.PayloadCodec) Option {
+	return func(opts *serverOptions) {
+		opts.codec = codec
+	}
+}
+
 func Start(ctx context.Context, cfg Config, opts ...Option) error {
 	var options serverOptions
 	for _, opt := range opts {
@@ -44,13 +55,24 @@ func Start(ctx context.Context, cfg Config, opts ...Option) error {
 		logger = options.logger
 	}
 
+	payloadCodec := options.codec
+	if payloadCodec == nil && cfg.PayloadEncryption.Enabled() {
+		keys, err := cfg.PayloadEncryption.DecodedKeys()
+		if err != nil {
+			return err
+		}
+		if payloadCodec, err = codec.NewAESGCM(cfg.PayloadEncryption.KeyID, keys); err != nil {
+			return err
+		}
+	}
+
 	var deps *dependencies
 	var err error
 
 	if cfg.InMemory {
-		deps = setupInMemoryDeps(ctx)
+		deps = setupInMemoryDeps(ctx, payloadCodec)
 	} else {
-		if deps, err = setupPostgresDeps(ctx, cfg.Postgres.URL(), cfg.Postgres.SchemaVersion); err != nil {
+		if deps, err = setupPostgresDeps(ctx, cfg.Postgres.URL(), cfg.Postgres.SchemaVersion, payloadCodec); err != nil {
 			return err
 		}
 	}
@@ -112,6 +134,8 @@ func newServer(ctx context.Context, cfg Config, deps *dependencies, logger log.L
 	// Connect
 
 	testSvc := testservice.New(deps.repo, temporalClient, testservice.WithLogger(logger))
+	go testSvc.WatchTimeouts(ctx, time.Minute)
+	go testSvc.RunSchedules(ctx, 10*time.Second)
 	eventSvc := eventservice.NewService(deps.eventSrc, deps.repo)
 
 	connectOps := []connect.HandlerOption{rpc.WithConnectInterceptors(logger)}
@@ -133,10 +157,32 @@ func newServer(ctx context.Context, cfg Config, deps *dependencies, logger log.L
 		return nil, err
 	}
 
-	workflowSvc := workflowservice.NewProxyService(testClient, temporalClient.WorkflowService())
+	workflowSvc := workflowservice.NewProxyService(testClient, temporalClient.WorkflowService(),
+		workflowservice.WithTaskQueueResolver(testSvc),
+		workflowservice.WithCasePayloadRecorder(testSvc),
+		workflowservice.WithCaseAttemptRecorder(testSvc),
+		workflowservice.WithCaseHeartbeatRecorder(testSvc),
+		workflowservice.WithSubExecutionRecorder(testSvc),
+		workflowservice.WithFinishedAcker(finishedAcker{svc: testSvc}),
+		workflowservice.WithLogger(logger),
+	)
 	srv.RegisterGRPC(&workflowservicev1.WorkflowService_ServiceDesc, workflowSvc)
 	srv.RegisterGRPC(&grpchealthv1.Health_ServiceDesc, healthSvc)
 	srv.WithGRPCOptions(rpc.WithGRPCInterceptors(logger)...)
 
 	return srv, nil
 }
+
+// finishedAcker acknowledges the finished executions of the workflow proxy
+// with the test service.
+type finishedAcker struct {
+	svc *testservice.Service
+}
+
+func (a finishedAcker) AckTestExecutionFinished(ctx context.Context, finished *test.FinishedTestExecution) error {
+	return a.svc.RecordTestExecutionFinished(ctx, finished)
+}
+
+func (a finishedAcker) AckCaseExecutionFinished(ctx context.Context, finished *test.FinishedCaseExecution) error {
+	return a.svc.RecordCaseExecutionFinished(ctx, finished)
+}
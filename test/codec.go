@@ -0,0 +1,96 @@
+package test
+
+import (
+	"errors"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// PayloadCodec transforms payloads before they are stored and reverses the
+// transformation when they are served, e.g. to encrypt inputs at rest. Codecs
+// are interchangeable with Temporal's so that the same codec can be shared
+// with workers.
+type PayloadCodec = converter.PayloadCodec
+
+// EncodePayload encodes a payload with the codec. The payload is returned
+// unchanged when the codec is nil.
+func EncodePayload(codec PayloadCodec, p *Payload) (*Payload, error) {
+	if codec == nil || p == nil {
+		return p, nil
+	}
+	encoded, err := codec.Encode([]*commonpb.Payload{p.temporal()})
+	if err != nil {
+		return nil, err
+	}
+	return newPayloadFromTemporal(encoded)
+}
+
+// DecodePayload reverses EncodePayload. The payload is returned unchanged when
+// the codec is nil.
+func DecodePayload(codec PayloadCodec, p *Payload) (*Payload, error) {
+	if codec == nil || p == nil {
+		return p, nil
+	}
+	decoded, err := codec.Decode([]*commonpb.Payload{p.temporal()})
+	if err != nil {
+		return nil, err
+	}
+	return newPayloadFromTemporal(decoded)
+}
+
+// EncodePayloads encodes each payload with the codec. The payloads are
+// returned unchanged when the codec is nil.
+func EncodePayloads(codec PayloadCodec, payloads []*Payload) ([]*Payload, error) {
+	return transformPayloads(codec, payloads, EncodePayload)
+}
+
+// DecodePayloads reverses EncodePayloads. The payloads are returned unchanged
+// when the codec is nil.
+func DecodePayloads(codec PayloadCodec, payloads []*Payload) ([]*Payload, error) {
+	return transformPayloads(codec, payloads, DecodePayload)
+}
+
+func transformPayloads(codec PayloadCodec, payloads []*Payload, transform func(PayloadCodec, *Payload) (*Payload, error)) ([]*Payload, error) {
+	if codec == nil || payloads == nil {
+		return payloads, nil
+	}
+	out := make([]*Payload, len(payloads))
+	for i, p := range payloads {
+		var err error
+		if out[i], err = transform(codec, p); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (p *Payload) temporal() *commonpb.Payload {
+	return &commonpb.Payload{
+		Metadata: p.Metadata,
+		Data:     p.Data,
+	}
+}
+
+func newPayloadFromTemporal(payloads []*commonpb.Payload) (*Payload, error) {
+	if len(payloads) != 1 {
+		return nil, errors.New("payload codec must return exactly one payload per payload")
+	}
+	return &Payload{
+		Metadata: payloads[0].Metadata,
+		Data:     payloads[0].Data,
+	}, nil
+}
+
+// NewPayloadsFromTemporal converts Temporal payloads, e.g. the arguments of an
+// activity, to payloads.
+func NewPayloadsFromTemporal(payloads *commonpb.Payloads) []*Payload {
+	converted := make([]*Payload, len(payloads.GetPayloads()))
+	for i, p := range payloads.GetPayloads() {
+		converted[i] = &Payload{
+			Metadata: p.Metadata,
+			Data:     p.Data,
+		}
+	}
+	return converted
+}
@@ -2,12 +2,32 @@ package test
 
 const (
 	ErrorContextAlreadyExists  = testErr("context already exists")
+	ErrorContextNotFound       = testErr("context not found")
+	ErrorContextArchived       = testErr("context is archived")
+	ErrorGroupNotFound         = testErr("group not found")
+	ErrorGroupAlreadyExists    = testErr("group already exists")
+	ErrorGroupArchived         = testErr("group is archived")
+	ErrorExecutionsActive      = testErr("test executions are still active")
+	ErrorRunnersActive         = testErr("runners are still polling")
+	ErrorTaskQueueConflict     = testErr("groups would share a task queue")
+	ErrorTaskQueueAmbiguous    = testErr("task queue is shared by several groups")
 	ErrorTestNotFound          = testErr("test not found")
+	ErrorTestAlreadyExists     = testErr("test already exists")
+	ErrorTestDeprecated        = testErr("test is deprecated")
+	ErrorTestMoved             = testErr("test moved to another group after the test execution started")
+	ErrorTestVersionNotFound   = testErr("test version not found")
 	ErrorTestExecutionNotFound = testErr("test execution not found")
+	ErrorTestExecutionFinished = testErr("test execution already finished")
+	ErrorTestExecutionChanged  = testErr("test execution changed while it was reset")
+	ErrorSubTestExecution      = testErr("test execution is a sub-execution of another test execution")
+	ErrorAttemptNotFound       = testErr("test execution attempt not found")
 	ErrorCaseExecutionNotFound = testErr("case execution not found")
 	ErrorLogNotFound           = testErr("execution log not found")
 	ErrorNotTestExecution      = testErr("workflow is not a test execution")
 	ErrorNotCaseExecution      = testErr("activity is not a test execution")
+	ErrorTestRunNotFound       = testErr("test run not found")
+	ErrorScheduleNotFound      = testErr("schedule not found")
+	ErrorScheduleNotDue        = testErr("schedule not due")
 )
 
 type testErr string
@@ -0,0 +1,114 @@
+package test
+
+import (
+	failurepb "go.temporal.io/api/failure/v1"
+)
+
+type FailureKind string
+
+const (
+	FailureKindApplication   FailureKind = "application"
+	FailureKindTimeout       FailureKind = "timeout"
+	FailureKindCanceled      FailureKind = "canceled"
+	FailureKindTerminated    FailureKind = "terminated"
+	FailureKindServer        FailureKind = "server"
+	FailureKindResetWorkflow FailureKind = "reset_workflow"
+	FailureKindActivity      FailureKind = "activity"
+	FailureKindChildWorkflow FailureKind = "child_workflow"
+)
+
+// Failure is the failure of an execution with the chain of failures that
+// caused it. Like inputs, their details are encoded with the payload codec
+// before they are stored.
+type Failure struct {
+	Kind         FailureKind `json:"kind,omitempty"`
+	Message      string      `json:"message"`
+	Type         string      `json:"type,omitempty"` // application error type, or the timeout type of timeouts
+	Source       string      `json:"source,omitempty"`
+	StackTrace   string      `json:"stack_trace,omitempty"`
+	NonRetryable bool        `json:"non_retryable,omitempty"`
+	Details      []*Payload  `json:"details,omitempty"` // application error details
+	Cause        *Failure    `json:"cause,omitempty"`
+}
+
+// NewFailureFromTemporal converts a Temporal failure, and the failures that
+// caused it, to a failure. It returns nil if the Temporal failure is nil.
+func NewFailureFromTemporal(f *failurepb.Failure) *Failure {
+	if f == nil {
+		return nil
+	}
+
+	failure := &Failure{
+		Message:    f.GetMessage(),
+		Source:     f.GetSource(),
+		StackTrace: f.GetStackTrace(),
+		Cause:      NewFailureFromTemporal(f.GetCause()),
+	}
+
+	switch {
+	case f.GetApplicationFailureInfo() != nil:
+		info := f.GetApplicationFailureInfo()
+		failure.Kind = FailureKindApplication
+		failure.Type = info.GetType()
+		failure.NonRetryable = info.GetNonRetryable()
+		failure.Details = NewPayloadsFromTemporal(info.GetDetails())
+	case f.GetTimeoutFailureInfo() != nil:
+		failure.Kind = FailureKindTimeout
+		failure.Type = f.GetTimeoutFailureInfo().GetTimeoutType().String()
+	case f.GetCanceledFailureInfo() != nil:
+		failure.Kind = FailureKindCanceled
+		failure.Details = NewPayloadsFromTemporal(f.GetCanceledFailureInfo().GetDetails())
+	case f.GetTerminatedFailureInfo() != nil:
+		failure.Kind = FailureKindTerminated
+	case f.GetServerFailureInfo() != nil:
+		failure.Kind = FailureKindServer
+		failure.NonRetryable = f.GetServerFailureInfo().GetNonRetryable()
+	case f.GetResetWorkflowFailureInfo() != nil:
+		failure.Kind = FailureKindResetWorkflow
+	case f.GetActivityFailureInfo() != nil:
+		failure.Kind = FailureKindActivity
+		failure.Type = f.GetActivityFailureInfo().GetActivityType().GetName()
+	case f.GetChildWorkflowExecutionFailureInfo() != nil:
+		failure.Kind = FailureKindChildWorkflow
+		failure.Type = f.GetChildWorkflowExecutionFailureInfo().GetWorkflowType().GetName()
+	}
+
+	if len(failure.Details) == 0 {
+		failure.Details = nil
+	}
+
+	return failure
+}
+
+// EncodeFailure encodes the details of the failure, and of the failures that
+// caused it, with the codec. The failure is returned unchanged when the codec
+// is nil.
+func EncodeFailure(codec PayloadCodec, f *Failure) (*Failure, error) {
+	return transformFailure(codec, f, EncodePayloads)
+}
+
+// DecodeFailure reverses EncodeFailure. The failure is returned unchanged when
+// the codec is nil.
+func DecodeFailure(codec PayloadCodec, f *Failure) (*Failure, error) {
+	return transformFailure(codec, f, DecodePayloads)
+}
+
+func transformFailure(codec PayloadCodec, f *Failure, transform func(PayloadCodec, []*Payload) ([]*Payload, error)) (*Failure, error) {
+	if codec == nil || f == nil {
+		return f, nil
+	}
+
+	details, err := transform(codec, f.Details)
+	if err != nil {
+		return nil, err
+	}
+	cause, err := transformFailure(codec, f.Cause, transform)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *f
+	out.Details = details
+	out.Cause = cause
+	return &out, nil
+}
@@ -29,6 +29,17 @@ func NewTestExecutionID() TestExecutionID {
 	return TestExecutionID{uuid.New()}
 }
 
+var uuidNameSpaceSubTestExecution = uuid.MustParse("b6cd6a55-429a-4d74-9507-9db38efe3333")
+
+// NewSubTestExecutionID derives the ID of the sub-execution of a child workflow
+// started by a run of a test execution. The same child workflow gets the same
+// ID when its start is acknowledged again, and a new ID when the test
+// execution is retried since retries start a new run.
+func NewSubTestExecutionID(parentID TestExecutionID, parentRunID string, childWorkflowID string) TestExecutionID {
+	raw := parentID.String() + "." + parentRunID + "." + childWorkflowID
+	return TestExecutionID{uuid.NewSHA1(uuidNameSpaceSubTestExecution, []byte(raw))}
+}
+
 func ParseTestExecutionID(id string) (TestExecutionID, error) {
 	texid, err := uuid.Parse(id)
 	if err != nil {
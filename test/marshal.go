@@ -33,6 +33,10 @@ func (p *Payload) Proto() *testsv1.Payload {
 	}
 }
 
+// Proto converts the test execution to the tests API type. The tests API does
+// not define statuses or structured failures, so only the error message of a
+// failure is sent. Service methods that return test types, such as
+// testservice.Service.GetTestExecutionTree, expose both.
 func (t *TestExecution) Proto() *testsv1.TestExecution {
 	exec := &testsv1.TestExecution{
 		Id:           t.ID.String(),
@@ -59,6 +63,8 @@ func (t TestExecutionList) Proto() []*testsv1.TestExecution {
 	return fe
 }
 
+// Proto converts the case execution to the tests API type. Like test
+// executions, only the error message of a failure is sent.
 func (c *CaseExecution) Proto() *testsv1.CaseExecution {
 	exec := &testsv1.CaseExecution{
 		Id:              c.ID.Int32(),
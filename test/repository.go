@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,6 +14,8 @@ type Repository interface {
 	TestExecutionReadWriter
 	CaseExecutionReadWriter
 	LogReadWriter
+	ScheduleReadWriter
+	TestRunReadWriter
 }
 
 type ContextReadWriter interface {
@@ -20,13 +23,21 @@ type ContextReadWriter interface {
 	ContextWriter
 }
 
+// ContextReader reads contexts. Archived contexts are not listed but still
+// exist.
 type ContextReader interface {
 	ListContexts(ctx context.Context) ([]string, error)
 	ContextExists(ctx context.Context, id string) (bool, error)
+	IsContextArchived(ctx context.Context, id string) (bool, error)
 }
 
 type ContextWriter interface {
 	CreateContext(ctx context.Context, id string) error
+	UpdateContextArchived(ctx context.Context, id string, archived bool) error
+	// DeleteContext deletes a context with its groups, tests, test runs and
+	// executions. It returns ErrorExecutionsActive without deleting anything
+	// if any execution in the context has not finished.
+	DeleteContext(ctx context.Context, id string) error
 }
 
 type GroupReadWriter interface {
@@ -35,12 +46,32 @@ type GroupReadWriter interface {
 }
 
 type GroupReader interface {
-	ListGroups(ctx context.Context, contextID string) ([]string, error)
+	ListGroups(ctx context.Context, contextID string, filter *GroupListFilter) ([]string, error)
 	GroupExists(ctx context.Context, contextID string, groupID string) (bool, error)
+	IsGroupArchived(ctx context.Context, contextID string, groupID string) (bool, error)
+	// GetGroupTaskQueue gets the task queue the tests of a group are executed
+	// on. It returns ErrorGroupNotFound if the group does not exist.
+	GetGroupTaskQueue(ctx context.Context, contextID string, groupID string) (string, error)
+	// TaskQueueExists reports whether any group has the task queue.
+	TaskQueueExists(ctx context.Context, taskQueue string) (bool, error)
 }
 
 type GroupWriter interface {
-	CreateGroup(ctx context.Context, contextID string, groupID string) error
+	// CreateGroup creates a group with the task queue its tests are executed
+	// on. Groups keep their task queue, including when they are renamed.
+	// Creating an existing group keeps its task queue and restores it if it is
+	// archived.
+	CreateGroup(ctx context.Context, contextID string, groupID string, taskQueue string) error
+	UpdateGroupArchived(ctx context.Context, contextID string, groupID string, archived bool) error
+	// RenameGroup renames a group. Its tests keep their IDs and executions,
+	// and the group keeps its task queue.
+	// It returns ErrorGroupAlreadyExists if the new group exists and
+	// ErrorExecutionsActive if any execution in the group has not finished.
+	RenameGroup(ctx context.Context, contextID string, groupID string, newGroupID string) error
+	// DeleteGroup deletes a group with its tests and executions. It returns
+	// ErrorExecutionsActive without deleting anything if any execution in the
+	// group has not finished.
+	DeleteGroup(ctx context.Context, contextID string, groupID string) error
 }
 
 type TestReadWriter interface {
@@ -50,13 +81,26 @@ type TestReadWriter interface {
 
 type TestReader interface {
 	GetTest(ctx context.Context, id uuid.UUID) (*Test, error)
-	ListTests(ctx context.Context, contextID string, groupID string) (TestList, error)
+	ListTests(ctx context.Context, contextID string, groupID string, filter *TestListFilter) (TestList, error)
+	SearchTests(ctx context.Context, filter *TestSearchFilter) (TestList, error)
 	GetTestDefaultInput(ctx context.Context, testID uuid.UUID) (*Payload, error)
+	GetTestVersion(ctx context.Context, testID uuid.UUID, version int32) (*TestVersion, error)
+	ListTestVersions(ctx context.Context, testID uuid.UUID) (TestVersionList, error)
 }
 
+// TestWriter creates tests. Creating a test that exists updates its
+// definition and records a new version if the definition changed.
 type TestWriter interface {
 	CreateTest(ctx context.Context, test *TestDefinition) (*Test, error)
 	CreateTests(ctx context.Context, tests ...*TestDefinition) (TestList, error)
+	// DeprecateTests deprecates the tests of a group that are not named in
+	// keepNames and returns them. Tests already deprecated are not returned.
+	DeprecateTests(ctx context.Context, contextID string, groupID string, keepNames []string) (TestList, error)
+	// MoveTest moves a test to another group of its context. The test keeps
+	// its ID and executions. It returns ErrorTestAlreadyExists if the group
+	// has a test with the same name and ErrorExecutionsActive if any execution
+	// of the test has not finished.
+	MoveTest(ctx context.Context, id uuid.UUID, groupID string) (*Test, error)
 }
 
 type TestExecutionReadWriter interface {
@@ -68,13 +112,39 @@ type TestExecutionReader interface {
 	GetTestExecution(ctx context.Context, id TestExecutionID) (*TestExecution, error)
 	GetTestExecutionInput(ctx context.Context, id TestExecutionID) (*Payload, error)
 	ListTestExecutions(ctx context.Context, testID uuid.UUID, filter *TestExecutionListFilter) (TestExecutionList, error)
+	ListRecentTestExecutions(ctx context.Context, contextID string, filter *RecentTestExecutionListFilter) (TestExecutionSummaryList, error)
+	// ListExpiredTestExecutions lists the open test executions whose execution
+	// or run timeout, whichever ends first, has passed since they were
+	// scheduled.
+	ListExpiredTestExecutions(ctx context.Context, now time.Time) (TestExecutionList, error)
+	GetTestExecutionAttempt(ctx context.Context, id TestExecutionID, attempt int32) (*TestExecutionAttempt, error)
+	ListTestExecutionAttempts(ctx context.Context, id TestExecutionID) (TestExecutionAttemptList, error)
+	// GetSubTestExecution gets the latest sub-execution of a child workflow. It
+	// returns ErrorTestExecutionNotFound if the workflow is not a child workflow
+	// of a test execution.
+	GetSubTestExecution(ctx context.Context, childWorkflowID string) (*TestExecution, error)
+	ListSubTestExecutions(ctx context.Context, parentID TestExecutionID) (TestExecutionList, error)
 }
 
 type TestExecutionWriter interface {
 	CreateScheduledTestExecution(ctx context.Context, scheduled *ScheduledTestExecution) (*TestExecution, error)
+	// CreateScheduledSubTestExecution creates a sub-execution of the test of its
+	// parent. Creating an existing sub-execution returns it unchanged.
+	CreateScheduledSubTestExecution(ctx context.Context, scheduled *ScheduledSubTestExecution) (*TestExecution, error)
+	// UpdateStartedTestExecution starts a test execution, including a failed
+	// one restarted by a workflow retry. Starting a test execution that was
+	// already stopped by a cancellation or timeout returns it unchanged.
 	UpdateStartedTestExecution(ctx context.Context, started *StartedTestExecution) (*TestExecution, error)
+	// UpdateFinishedTestExecution finishes a test execution. Finishing a test
+	// execution that was already stopped by a cancellation or timeout returns
+	// it unchanged.
 	UpdateFinishedTestExecution(ctx context.Context, finished *FinishedTestExecution) (*TestExecution, error)
-	ResetTestExecution(ctx context.Context, reset *ResetTestExecution) (*TestExecution, ResetRollback, error)
+	UpdateCancelledTestExecution(ctx context.Context, cancelled *CancelledTestExecution) (*TestExecution, error)
+	UpdateTimedOutTestExecution(ctx context.Context, timedOut *TimedOutTestExecution) (*TestExecution, error)
+	// ResetTestExecution archives the current attempt of a test execution and
+	// resets it to a new scheduled attempt. The reset is only kept if
+	// resetWorkflow, which resets the workflow of the test execution, succeeds.
+	ResetTestExecution(ctx context.Context, reset *ResetTestExecution, resetWorkflow func(ctx context.Context) error) (*TestExecution, error)
 }
 
 type CaseExecutionReadWriter interface {
@@ -85,13 +155,36 @@ type CaseExecutionReadWriter interface {
 type CaseExecutionReader interface {
 	GetCaseExecution(ctx context.Context, testExecID TestExecutionID, caseExecID CaseExecutionID) (*CaseExecution, error)
 	ListCaseExecutions(ctx context.Context, testExecID TestExecutionID) (CaseExecutionList, error)
+	ListAttemptCaseExecutions(ctx context.Context, testExecID TestExecutionID, attempt int32) (CaseExecutionList, error)
+	GetCaseExecutionPayloads(ctx context.Context, testExecID TestExecutionID, caseExecID CaseExecutionID) (*CaseExecutionPayloads, error)
+	ListCaseExecutionAttempts(ctx context.Context, testExecID TestExecutionID, caseExecID CaseExecutionID) (CaseExecutionAttemptList, error)
+	// ListAttemptCaseExecutionAttempts lists the activity attempts of a case
+	// execution as they were when the test execution attempt was archived.
+	ListAttemptCaseExecutionAttempts(ctx context.Context, testExecID TestExecutionID, attempt int32, caseExecID CaseExecutionID) (CaseExecutionAttemptList, error)
+	// GetAttemptCaseExecutionPayloads gets the payloads of a case execution as
+	// they were when the test execution attempt was archived.
+	GetAttemptCaseExecutionPayloads(ctx context.Context, testExecID TestExecutionID, attempt int32, caseExecID CaseExecutionID) (*CaseExecutionPayloads, error)
 }
 
 type CaseExecutionWriter interface {
 	CreateScheduledCaseExecution(ctx context.Context, scheduled *ScheduledCaseExecution) (*CaseExecution, error)
+	// UpdateStartedCaseExecution starts a case execution, including a failed
+	// one restarted by an activity retry. Starting a case execution that was
+	// already stopped by a cancellation or timeout returns it unchanged.
 	UpdateStartedCaseExecution(ctx context.Context, started *StartedCaseExecution) (*CaseExecution, error)
+	// UpdateFinishedCaseExecution finishes a case execution. Finishing a case
+	// execution that was already stopped by a cancellation or timeout returns
+	// it unchanged.
 	UpdateFinishedCaseExecution(ctx context.Context, finished *FinishedCaseExecution) (*CaseExecution, error)
+	// UpdateCaseExecutionHeartbeat records a heartbeat of a case execution.
+	// Heartbeats of a case execution that was already stopped by a cancellation
+	// or timeout return it unchanged.
+	UpdateCaseExecutionHeartbeat(ctx context.Context, heartbeat *CaseExecutionHeartbeat) (*CaseExecution, error)
 	DeleteCaseExecution(ctx context.Context, testExecID TestExecutionID, id CaseExecutionID) error
+	UpdateCaseExecutionInput(ctx context.Context, testExecID TestExecutionID, id CaseExecutionID, input []*Payload) error
+	UpdateCaseExecutionResult(ctx context.Context, testExecID TestExecutionID, id CaseExecutionID, result *Payload) error
+	CreateStartedCaseExecutionAttempt(ctx context.Context, started *StartedCaseExecutionAttempt) (*CaseExecutionAttempt, error)
+	UpdateFinishedCaseExecutionAttempt(ctx context.Context, finished *FinishedCaseExecutionAttempt) (*CaseExecutionAttempt, error)
 }
 
 type LogReadWriter interface {
@@ -102,6 +195,7 @@ type LogReadWriter interface {
 type LogReader interface {
 	GetLog(ctx context.Context, id uuid.UUID) (*Log, error)
 	ListLogs(ctx context.Context, testExecID TestExecutionID) (LogList, error)
+	ListAttemptLogs(ctx context.Context, testExecID TestExecutionID, attempt int32) (LogList, error)
 }
 
 type LogWriter interface {
@@ -109,4 +203,36 @@ type LogWriter interface {
 	DeleteLog(ctx context.Context, id uuid.UUID) error
 }
 
-type ResetRollback func(ctx context.Context) error
+type ScheduleReadWriter interface {
+	ScheduleReader
+	ScheduleWriter
+}
+
+type ScheduleReader interface {
+	GetSchedule(ctx context.Context, id uuid.UUID) (*Schedule, error)
+	GetScheduleInput(ctx context.Context, id uuid.UUID) (*Payload, error)
+	ListSchedules(ctx context.Context, testID uuid.UUID) (ScheduleList, error)
+	ListDueSchedules(ctx context.Context, now time.Time) (ScheduleList, error)
+}
+
+type ScheduleWriter interface {
+	CreateSchedule(ctx context.Context, schedule *ScheduleDefinition) (*Schedule, error)
+	UpdateFiredSchedule(ctx context.Context, fired *FiredSchedule) (*Schedule, error)
+	UpdatePausedSchedule(ctx context.Context, id uuid.UUID) (*Schedule, error)
+	UpdateResumedSchedule(ctx context.Context, resumed *ResumedSchedule) (*Schedule, error)
+	DeleteSchedule(ctx context.Context, id uuid.UUID) error
+}
+
+type TestRunReadWriter interface {
+	TestRunReader
+	TestRunWriter
+}
+
+type TestRunReader interface {
+	GetTestRun(ctx context.Context, id uuid.UUID) (*TestRun, error)
+	ListTestRunExecutions(ctx context.Context, id uuid.UUID) (TestExecutionList, error)
+}
+
+type TestRunWriter interface {
+	CreateTestRun(ctx context.Context, run *TestRunDefinition) (*TestRun, error)
+}
@@ -6,42 +6,160 @@ import (
 	"github.com/google/uuid"
 )
 
+type GroupListFilter struct {
+	IncludeArchived bool
+}
+
 type TestDefinition struct {
-	ContextID    string
-	GroupID      string
-	TestID       uuid.UUID
-	Name         string
-	DefaultInput *Payload
+	ContextID        string
+	GroupID          string
+	TestID           uuid.UUID
+	Name             string
+	DefaultInput     *Payload
+	ExecutionTimeout *time.Duration // optional: nil keeps the registered timeout, or the server default for new tests
+	RunTimeout       *time.Duration // optional: nil keeps the registered timeout, or is bounded by the execution timeout for new tests
+	InputSchema      []byte         // optional: JSON Schema that inputs are validated against; nil keeps the registered schema
+	Metadata         *TestMetadata  // optional: nil keeps the registered metadata
 }
 
 type Test struct {
-	ContextID  string
-	GroupID    string
-	ID         uuid.UUID
-	Name       string
-	HasInput   bool
-	CreateTime time.Time
+	ContextID        string
+	GroupID          string
+	ID               uuid.UUID
+	Name             string
+	HasInput         bool
+	CreateTime       time.Time
+	ExecutionTimeout *time.Duration
+	RunTimeout       *time.Duration
+	InputSchema      []byte
+	Deprecated       bool  // missing from the latest registration of its group
+	Version          int32 // version of the current definition
+	Metadata         TestMetadata
 }
 
 type TestList []*Test
 
+// TestMetadata describes a test to organise large groups and route failures
+// to the people responsible. It does not affect how a test is executed, so
+// changes to it are not versioned.
+type TestMetadata struct {
+	Description    string
+	Tags           []string
+	Owner          string // e.g. a team name or email address
+	SourceLocation string // e.g. a link to the test source in a repository
+}
+
+// TestVersion is a distinct definition of a test. A new version is recorded
+// each time a test is registered with a definition that differs from its
+// latest version.
+type TestVersion struct {
+	TestID           uuid.UUID
+	Version          int32 // starts at 1
+	DefaultInput     *Payload
+	ExecutionTimeout *time.Duration
+	RunTimeout       *time.Duration
+	InputSchema      []byte
+	CreateTime       time.Time
+}
+
+type TestVersionList []*TestVersion
+
+type TestListFilter struct {
+	IncludeDeprecated bool
+	Tags              []string // optional: tests must have all tags
+	Owner             string   // optional
+}
+
+// TestSearchFilter filters tests across all contexts and groups that are not
+// archived. Tests are searched most recently registered first.
+type TestSearchFilter struct {
+	NameContains      *string  // optional: case-insensitive substring of the name
+	Tags              []string // optional: tests must have all tags
+	Owner             string   // optional
+	IncludeDeprecated bool
+	LastCreateTime    *time.Time // required when searching next page
+	LastTestID        *uuid.UUID // required when searching next page
+	PageSize          uint32
+}
+
 type Payload struct {
 	Metadata map[string][]byte
 	Data     []byte
 }
 
+type ExecutionStatus string
+
+const (
+	ExecutionStatusScheduled ExecutionStatus = "scheduled"
+	ExecutionStatusRunning   ExecutionStatus = "running"
+	ExecutionStatusPassed    ExecutionStatus = "passed"
+	ExecutionStatusFailed    ExecutionStatus = "failed"
+	ExecutionStatusCancelled ExecutionStatus = "cancelled"
+	ExecutionStatusTimedOut  ExecutionStatus = "timed_out"
+)
+
+// IsFinal reports whether the status is terminal, i.e. the execution will not
+// transition to another status unless it is retried.
+func (s ExecutionStatus) IsFinal() bool {
+	switch s {
+	case ExecutionStatusPassed, ExecutionStatusFailed, ExecutionStatusCancelled, ExecutionStatusTimedOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsStopped reports whether the status was set by Annex stopping the
+// execution, rather than reported by the test itself. Unlike failures, stopped
+// executions are not resumed by activity or workflow retries.
+func (s ExecutionStatus) IsStopped() bool {
+	return s == ExecutionStatusCancelled || s == ExecutionStatusTimedOut
+}
+
+func (s ExecutionStatus) String() string {
+	return string(s)
+}
+
 type TestExecution struct {
-	ID           TestExecutionID
-	TestID       uuid.UUID
-	HasInput     bool
-	ScheduleTime time.Time
-	StartTime    *time.Time
-	FinishTime   *time.Time
-	Error        *string
+	ID                TestExecutionID
+	TestID            uuid.UUID
+	HasInput          bool
+	ScheduleTime      time.Time
+	StartTime         *time.Time
+	FinishTime        *time.Time
+	Error             *string
+	Status            ExecutionStatus
+	ExecutionTimeout  *time.Duration // nil for executions scheduled before timeouts were recorded
+	RunTimeout        *time.Duration
+	TestRunID         *uuid.UUID       // nil when not executed as part of a test run
+	Attempt           int32            // starts at 1 and is incremented by each retry
+	RerunOfID         *TestExecutionID // nil unless the execution is a rerun of another execution
+	TestVersion       *int32           // nil for executions scheduled before tests were versioned
+	Failure           *Failure         // nil unless the execution failed with a failure reported by its runner
+	ParentID          *TestExecutionID // nil unless the execution is a sub-execution of a child workflow
+	ChildWorkflowID   string           // empty unless the execution is a sub-execution
+	ChildWorkflowType string           // empty unless the execution is a sub-execution
 }
 
 type TestExecutionList []*TestExecution
 
+// TestExecutionAttempt is an archived attempt of a test execution. An attempt
+// is archived with its case executions and logs when the test execution is
+// retried.
+type TestExecutionAttempt struct {
+	TestExecutionID TestExecutionID
+	Attempt         int32
+	ScheduleTime    time.Time
+	StartTime       *time.Time
+	FinishTime      *time.Time
+	Error           *string
+	Failure         *Failure
+	Status          ExecutionStatus
+	ArchiveTime     time.Time
+}
+
+type TestExecutionAttemptList []*TestExecutionAttempt
+
 type ResetTestExecution struct {
 	ID                  TestExecutionID
 	ResetTime           time.Time
@@ -50,10 +168,26 @@ type ResetTestExecution struct {
 }
 
 type ScheduledTestExecution struct {
-	ID           TestExecutionID
-	TestID       uuid.UUID
-	Payload      []byte
-	ScheduleTime time.Time
+	ID               TestExecutionID
+	TestID           uuid.UUID
+	Input            *Payload // optional: the execution has no input when nil
+	ScheduleTime     time.Time
+	ExecutionTimeout time.Duration
+	RunTimeout       *time.Duration
+	TestRunID        *uuid.UUID       // optional
+	RerunOfID        *TestExecutionID // optional
+	TestVersion      *int32           // optional: version of the test definition executed
+}
+
+// ScheduledSubTestExecution is a child workflow started by a test execution.
+// Sub-executions are executions of the test of their parent and have their own
+// case executions and logs.
+type ScheduledSubTestExecution struct {
+	ID                TestExecutionID
+	ParentID          TestExecutionID
+	ChildWorkflowID   string
+	ChildWorkflowType string
+	ScheduleTime      time.Time
 }
 
 type StartedTestExecution struct {
@@ -65,9 +199,45 @@ type FinishedTestExecution struct {
 	ID         TestExecutionID
 	FinishTime time.Time
 	Error      *string
+	Failure    *Failure // optional: the failure the error is the message of
+}
+
+// Status returns the final status of the finished test execution.
+func (f *FinishedTestExecution) Status() ExecutionStatus {
+	return finishedStatus(f.Error)
+}
+
+type CancelledTestExecution struct {
+	ID         TestExecutionID
+	CancelTime time.Time
+}
+
+type TimedOutTestExecution struct {
+	ID          TestExecutionID
+	TimeoutTime time.Time
 }
 
 type TestExecutionListFilter struct {
+	LastScheduleTime    *time.Time        // required when listing next page
+	LastTestExecutionID *uuid.UUID        // required when listing next page
+	Statuses            []ExecutionStatus // optional: matches any of the statuses
+	ScheduledAfter      *time.Time        // optional: inclusive lower bound on schedule time
+	ScheduledBefore     *time.Time        // optional: exclusive upper bound on schedule time
+	ErrorContains       *string           // optional: case-insensitive substring of the error
+	PageSize            uint32
+}
+
+type TestExecutionSummary struct {
+	ContextID string
+	GroupID   string
+	TestName  string
+	Execution *TestExecution
+}
+
+type TestExecutionSummaryList []*TestExecutionSummary
+
+type RecentTestExecutionListFilter struct {
+	GroupID             *string    // optional: all groups in the context when nil
 	LastScheduleTime    *time.Time // required when listing next page
 	LastTestExecutionID *uuid.UUID // required when listing next page
 	PageSize            uint32
@@ -81,6 +251,10 @@ type CaseExecution struct {
 	StartTime       *time.Time
 	FinishTime      *time.Time
 	Error           *string
+	Failure         *Failure // nil unless the execution failed with a failure reported by its runner
+	Status          ExecutionStatus
+	HeartbeatTime   *time.Time // nil until the running case heartbeats
+	Progress        []*Payload // the details of the latest heartbeat that reported any
 }
 
 type CaseExecutionList []*CaseExecution
@@ -103,6 +277,65 @@ type FinishedCaseExecution struct {
 	TestExecutionID TestExecutionID
 	FinishTime      time.Time
 	Error           *string
+	Failure         *Failure // optional: the failure the error is the message of
+}
+
+// Status returns the final status of the finished case execution.
+func (f *FinishedCaseExecution) Status() ExecutionStatus {
+	return finishedStatus(f.Error)
+}
+
+// CaseExecutionHeartbeat is a heartbeat of a running case execution. Long
+// running cases report their progress as heartbeat details. Like failures,
+// progress is stored as reported: it is not encoded with the payload codec.
+type CaseExecutionHeartbeat struct {
+	ID              CaseExecutionID
+	TestExecutionID TestExecutionID
+	HeartbeatTime   time.Time
+	Progress        []*Payload // optional: keeps the latest progress if empty
+}
+
+// CaseExecutionAttempt is an attempt of a case execution. Case executions are
+// attempted again when their activity is retried.
+type CaseExecutionAttempt struct {
+	TestExecutionID TestExecutionID
+	CaseExecutionID CaseExecutionID
+	Attempt         int32      // starts at 1 and is incremented by each activity retry
+	StartTime       *time.Time // nil for attempts started before attempts were recorded
+	FinishTime      *time.Time
+	Error           *string
+	Failure         *Failure
+	Status          ExecutionStatus
+}
+
+type CaseExecutionAttemptList []*CaseExecutionAttempt
+
+type StartedCaseExecutionAttempt struct {
+	TestExecutionID TestExecutionID
+	CaseExecutionID CaseExecutionID
+	Attempt         int32
+	StartTime       time.Time
+}
+
+type FinishedCaseExecutionAttempt struct {
+	TestExecutionID TestExecutionID
+	CaseExecutionID CaseExecutionID
+	Attempt         int32
+	FinishTime      time.Time
+	Error           *string
+	Failure         *Failure // optional: the failure the error is the message of
+}
+
+// Status returns the final status of the finished case execution attempt.
+func (f *FinishedCaseExecutionAttempt) Status() ExecutionStatus {
+	return finishedStatus(f.Error)
+}
+
+// CaseExecutionPayloads are the payloads a case execution was called with and
+// the payload it returned.
+type CaseExecutionPayloads struct {
+	Input  []*Payload // one payload per case argument
+	Result *Payload   // optional: nil until the case execution returns a result
 }
 
 type Log struct {
@@ -115,3 +348,133 @@ type Log struct {
 }
 
 type LogList []*Log
+
+type TestRunDefinition struct {
+	ID         uuid.UUID
+	ContextID  string
+	GroupID    *string                   // optional: nil when the run is not scoped to a group
+	Executions []*ScheduledTestExecution // optional: created atomically with the test run
+}
+
+// TestRun groups the test executions created from a single request. The
+// status, counts and times are aggregated from its test executions.
+type TestRun struct {
+	ID         uuid.UUID
+	ContextID  string
+	GroupID    *string
+	CreateTime time.Time
+	Status     ExecutionStatus
+	Counts     TestRunCounts
+	StartTime  *time.Time // earliest start of the test executions
+	FinishTime *time.Time // latest finish once all test executions are final
+}
+
+type TestRunCounts struct {
+	Total     int
+	Scheduled int
+	Running   int
+	Passed    int
+	Failed    int
+	Cancelled int
+	TimedOut  int
+}
+
+// Aggregate sets the status, counts and times of the test run from its test
+// executions. A run is failed if any execution failed or timed out, and is
+// otherwise cancelled if any execution was cancelled.
+func (r *TestRun) Aggregate(execs TestExecutionList) {
+	r.Counts = TestRunCounts{Total: len(execs)}
+	r.StartTime = nil
+	r.FinishTime = nil
+
+	for _, exec := range execs {
+		switch exec.Status {
+		case ExecutionStatusScheduled:
+			r.Counts.Scheduled++
+		case ExecutionStatusRunning:
+			r.Counts.Running++
+		case ExecutionStatusPassed:
+			r.Counts.Passed++
+		case ExecutionStatusFailed:
+			r.Counts.Failed++
+		case ExecutionStatusCancelled:
+			r.Counts.Cancelled++
+		case ExecutionStatusTimedOut:
+			r.Counts.TimedOut++
+		}
+		if exec.StartTime != nil && (r.StartTime == nil || exec.StartTime.Before(*r.StartTime)) {
+			r.StartTime = exec.StartTime
+		}
+		if exec.FinishTime != nil && (r.FinishTime == nil || exec.FinishTime.After(*r.FinishTime)) {
+			r.FinishTime = exec.FinishTime
+		}
+	}
+
+	switch {
+	case r.Counts.Scheduled == r.Counts.Total:
+		r.Status = ExecutionStatusScheduled
+	case r.Counts.Scheduled > 0 || r.Counts.Running > 0:
+		r.Status = ExecutionStatusRunning
+	case r.Counts.Failed > 0 || r.Counts.TimedOut > 0:
+		r.Status = ExecutionStatusFailed
+	case r.Counts.Cancelled > 0:
+		r.Status = ExecutionStatusCancelled
+	default:
+		r.Status = ExecutionStatusPassed
+	}
+
+	if !r.Status.IsFinal() {
+		r.FinishTime = nil
+	}
+}
+
+// Duration returns the time between the first test execution starting and the
+// last finishing. It returns the elapsed time so far while the run is open.
+func (r *TestRun) Duration() time.Duration {
+	if r.StartTime == nil {
+		return 0
+	}
+	if r.FinishTime == nil {
+		return time.Since(*r.StartTime)
+	}
+	return r.FinishTime.Sub(*r.StartTime)
+}
+
+type ScheduleDefinition struct {
+	ID           uuid.UUID
+	TestID       uuid.UUID
+	Cron         string
+	Input        *Payload // optional: executions have no input when nil
+	NextFireTime time.Time
+}
+
+type Schedule struct {
+	ID           uuid.UUID
+	TestID       uuid.UUID
+	Cron         string
+	HasInput     bool
+	Paused       bool
+	CreateTime   time.Time
+	NextFireTime time.Time
+	LastFireTime *time.Time
+}
+
+type ScheduleList []*Schedule
+
+type FiredSchedule struct {
+	ID           uuid.UUID
+	FireTime     time.Time // must equal the current next fire time of the schedule
+	NextFireTime time.Time
+}
+
+type ResumedSchedule struct {
+	ID           uuid.UUID
+	NextFireTime time.Time
+}
+
+func finishedStatus(err *string) ExecutionStatus {
+	if err != nil {
+		return ExecutionStatusFailed
+	}
+	return ExecutionStatusPassed
+}
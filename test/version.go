@@ -0,0 +1,44 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"time"
+)
+
+// Matches reports whether the version describes the definition.
+func (v *TestVersion) Matches(def *TestDefinition) bool {
+	return equalPayloads(v.DefaultInput, def.DefaultInput) &&
+		equalDurations(v.ExecutionTimeout, def.ExecutionTimeout) &&
+		equalDurations(v.RunTimeout, def.RunTimeout) &&
+		equalJSON(v.InputSchema, def.InputSchema)
+}
+
+func equalPayloads(a, b *Payload) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(a.Data, b.Data) && maps.EqualFunc(a.Metadata, b.Metadata, bytes.Equal)
+}
+
+func equalDurations(a, b *time.Duration) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// equalJSON compares JSON documents semantically since stored documents may
+// be reformatted, e.g. by postgres JSONB.
+func equalJSON(a, b []byte) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,56 @@
+package testservice
+
+import (
+	"context"
+
+	"github.com/annexsh/annex/test"
+)
+
+// TestExecutionAttemptDetails is an archived attempt of a test execution with
+// the case executions and logs it had when it was retried.
+type TestExecutionAttemptDetails struct {
+	Attempt        *test.TestExecutionAttempt
+	CaseExecutions test.CaseExecutionList
+	Logs           test.LogList
+}
+
+type ListTestExecutionAttemptsRequest struct {
+	TestExecutionID test.TestExecutionID
+}
+
+// ListTestExecutionAttempts lists the archived attempts of a test execution in
+// attempt order. The current attempt is the test execution itself.
+func (s *Service) ListTestExecutionAttempts(ctx context.Context, req *ListTestExecutionAttemptsRequest) (test.TestExecutionAttemptList, error) {
+	if _, err := s.repo.GetTestExecution(ctx, req.TestExecutionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTestExecutionAttempts(ctx, req.TestExecutionID)
+}
+
+type GetTestExecutionAttemptRequest struct {
+	TestExecutionID test.TestExecutionID
+	Attempt         int32
+}
+
+func (s *Service) GetTestExecutionAttempt(ctx context.Context, req *GetTestExecutionAttemptRequest) (*TestExecutionAttemptDetails, error) {
+	a, err := s.repo.GetTestExecutionAttempt(ctx, req.TestExecutionID, req.Attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	caseExecs, err := s.repo.ListAttemptCaseExecutions(ctx, req.TestExecutionID, req.Attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.repo.ListAttemptLogs(ctx, req.TestExecutionID, req.Attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TestExecutionAttemptDetails{
+		Attempt:        a,
+		CaseExecutions: caseExecs,
+		Logs:           logs,
+	}, nil
+}
@@ -83,15 +83,164 @@ func (s *Service) AckCaseExecutionFinished(
 		return nil, err
 	}
 
-	finished := &test.FinishedCaseExecution{
+	if err = s.RecordCaseExecutionFinished(ctx, &test.FinishedCaseExecution{
 		ID:              test.CaseExecutionID(req.Msg.CaseExecutionId),
 		TestExecutionID: testExecID,
 		FinishTime:      req.Msg.FinishTime.AsTime(),
 		Error:           req.Msg.Error,
-	}
-	if _, err = s.repo.UpdateFinishedCaseExecution(ctx, finished); err != nil {
-		return nil, fmt.Errorf("failed to update case execution: %w", err)
+	}); err != nil {
+		return nil, err
 	}
 
 	return connect.NewResponse(&testsv1.AckCaseExecutionFinishedResponse{}), nil
 }
+
+// RecordCaseExecutionFinished records the end of a case execution with the
+// structured failure the tests API cannot carry.
+func (s *Service) RecordCaseExecutionFinished(ctx context.Context, finished *test.FinishedCaseExecution) error {
+	if _, err := s.repo.UpdateFinishedCaseExecution(ctx, finished); err != nil {
+		return fmt.Errorf("failed to update case execution: %w", err)
+	}
+	return nil
+}
+
+// CaseExecutionDetails is a case execution with the attempts of its activity.
+type CaseExecutionDetails struct {
+	CaseExecution *test.CaseExecution
+	Attempts      test.CaseExecutionAttemptList
+}
+
+type GetCaseExecutionRequest struct {
+	TestExecutionID test.TestExecutionID
+	CaseExecutionID test.CaseExecutionID
+	Attempt         int32 // optional: archived test execution attempt; the current attempt when 0
+}
+
+// GetCaseExecution gets a case execution with its attempts in attempt order.
+// Case executions that passed after being retried have several attempts.
+func (s *Service) GetCaseExecution(ctx context.Context, req *GetCaseExecutionRequest) (*CaseExecutionDetails, error) {
+	if req.Attempt > 0 {
+		return s.getAttemptCaseExecution(ctx, req)
+	}
+
+	caseExec, err := s.repo.GetCaseExecution(ctx, req.TestExecutionID, req.CaseExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.ListCaseExecutionAttempts(ctx, req.TestExecutionID, req.CaseExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CaseExecutionDetails{
+		CaseExecution: caseExec,
+		Attempts:      attempts,
+	}, nil
+}
+
+// RecordCaseExecutionAttemptStarted records the start of an attempt of a case
+// execution. Previous attempts that are still running are timed out since
+// their activity would not have been retried otherwise.
+func (s *Service) RecordCaseExecutionAttemptStarted(ctx context.Context, started *test.StartedCaseExecutionAttempt) error {
+	if _, err := s.repo.CreateStartedCaseExecutionAttempt(ctx, started); err != nil {
+		return fmt.Errorf("failed to create case execution attempt: %w", err)
+	}
+	return nil
+}
+
+// RecordCaseExecutionAttemptFinished records the end of an attempt of a case
+// execution.
+func (s *Service) RecordCaseExecutionAttemptFinished(ctx context.Context, finished *test.FinishedCaseExecutionAttempt) error {
+	if _, err := s.repo.UpdateFinishedCaseExecutionAttempt(ctx, finished); err != nil {
+		return fmt.Errorf("failed to update case execution attempt: %w", err)
+	}
+	return nil
+}
+
+// RecordCaseExecutionHeartbeat records a heartbeat of a running case
+// execution and the progress it reported.
+func (s *Service) RecordCaseExecutionHeartbeat(ctx context.Context, heartbeat *test.CaseExecutionHeartbeat) error {
+	if _, err := s.repo.UpdateCaseExecutionHeartbeat(ctx, heartbeat); err != nil {
+		return fmt.Errorf("failed to update case execution heartbeat: %w", err)
+	}
+	return nil
+}
+
+// RecordCaseExecutionInput records the payloads a case execution was called
+// with, one per case argument.
+func (s *Service) RecordCaseExecutionInput(ctx context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID, input []*test.Payload) error {
+	if err := s.repo.UpdateCaseExecutionInput(ctx, testExecID, caseExecID, input); err != nil {
+		return fmt.Errorf("failed to update case execution input: %w", err)
+	}
+	return nil
+}
+
+// RecordCaseExecutionResult records the payload returned by a case execution.
+// Case executions that returned nothing have no result.
+func (s *Service) RecordCaseExecutionResult(ctx context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID, result *test.Payload) error {
+	if result == nil {
+		return nil
+	}
+	if err := s.repo.UpdateCaseExecutionResult(ctx, testExecID, caseExecID, result); err != nil {
+		return fmt.Errorf("failed to update case execution result: %w", err)
+	}
+	return nil
+}
+
+// getAttemptCaseExecution gets a case execution as it was when a test
+// execution attempt was archived.
+func (s *Service) getAttemptCaseExecution(ctx context.Context, req *GetCaseExecutionRequest) (*CaseExecutionDetails, error) {
+	caseExec, err := s.findAttemptCaseExecution(ctx, req.TestExecutionID, req.Attempt, req.CaseExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.ListAttemptCaseExecutionAttempts(ctx, req.TestExecutionID, req.Attempt, req.CaseExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CaseExecutionDetails{
+		CaseExecution: caseExec,
+		Attempts:      attempts,
+	}, nil
+}
+
+func (s *Service) findAttemptCaseExecution(ctx context.Context, testExecID test.TestExecutionID, attempt int32, caseExecID test.CaseExecutionID) (*test.CaseExecution, error) {
+	if _, err := s.repo.GetTestExecutionAttempt(ctx, testExecID, attempt); err != nil {
+		return nil, err
+	}
+	caseExecs, err := s.repo.ListAttemptCaseExecutions(ctx, testExecID, attempt)
+	if err != nil {
+		return nil, err
+	}
+	for _, ce := range caseExecs {
+		if ce.ID == caseExecID {
+			return ce, nil
+		}
+	}
+	return nil, test.ErrorCaseExecutionNotFound
+}
+
+type GetCaseExecutionPayloadsRequest struct {
+	TestExecutionID test.TestExecutionID
+	CaseExecutionID test.CaseExecutionID
+	Attempt         int32 // optional: archived test execution attempt; the current attempt when 0
+}
+
+// GetCaseExecutionPayloads gets the payloads a case execution was called with
+// and returned.
+func (s *Service) GetCaseExecutionPayloads(ctx context.Context, req *GetCaseExecutionPayloadsRequest) (*test.CaseExecutionPayloads, error) {
+	if req.Attempt > 0 {
+		if _, err := s.findAttemptCaseExecution(ctx, req.TestExecutionID, req.Attempt, req.CaseExecutionID); err != nil {
+			return nil, err
+		}
+		return s.repo.GetAttemptCaseExecutionPayloads(ctx, req.TestExecutionID, req.Attempt, req.CaseExecutionID)
+	}
+
+	if _, err := s.repo.GetCaseExecution(ctx, req.TestExecutionID, req.CaseExecutionID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCaseExecutionPayloads(ctx, req.TestExecutionID, req.CaseExecutionID)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/annexsh/annex/internal/fake"
 	"github.com/annexsh/annex/internal/ptr"
+	"github.com/annexsh/annex/test"
 )
 
 func TestService_AckCaseExecutionScheduled(t *testing.T) {
@@ -102,6 +103,154 @@ func TestService_AckCaseExecutionFinished(t *testing.T) {
 	assert.Equal(t, req.Error, ackd.Error)
 }
 
+func TestService_AckCaseExecution_retryAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+	ce, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(te.ID))
+	require.NoError(t, err)
+
+	ackStarted := func() {
+		_, err := s.AckCaseExecutionStarted(ctx, connect.NewRequest(&testsv1.AckCaseExecutionStartedRequest{
+			TestExecutionId: te.ID.String(),
+			CaseExecutionId: ce.ID.Int32(),
+			StartTime:       timestamppb.Now(),
+		}))
+		require.NoError(t, err)
+	}
+	ackFinished := func(failure *string) {
+		_, err := s.AckCaseExecutionFinished(ctx, connect.NewRequest(&testsv1.AckCaseExecutionFinishedRequest{
+			TestExecutionId: te.ID.String(),
+			CaseExecutionId: ce.ID.Int32(),
+			FinishTime:      timestamppb.Now(),
+			Error:           failure,
+		}))
+		require.NoError(t, err)
+	}
+
+	// The first activity attempt fails and is retried by Temporal
+	ackStarted()
+	ackFinished(ptr.Get("bang"))
+
+	got, err := fakes.repo.GetCaseExecution(ctx, te.ID, ce.ID)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusFailed, got.Status)
+
+	ackStarted()
+	got, err = fakes.repo.GetCaseExecution(ctx, te.ID, ce.ID)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusRunning, got.Status)
+	assert.Nil(t, got.Error)
+
+	heartbeat := &test.CaseExecutionHeartbeat{
+		ID:              ce.ID,
+		TestExecutionID: te.ID,
+		HeartbeatTime:   time.Now().UTC(),
+		Progress:        []*test.Payload{fake.GenInput()},
+	}
+	require.NoError(t, s.RecordCaseExecutionHeartbeat(ctx, heartbeat))
+
+	ackFinished(nil)
+	got, err = fakes.repo.GetCaseExecution(ctx, te.ID, ce.ID)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusPassed, got.Status)
+	assert.Nil(t, got.Error)
+	assert.Equal(t, heartbeat.HeartbeatTime, *got.HeartbeatTime)
+	assert.Equal(t, heartbeat.Progress, got.Progress)
+}
+
+func TestService_RecordCaseExecutionFinished(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+	ce, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(te.ID))
+	require.NoError(t, err)
+
+	failure := &test.Failure{
+		Kind:         test.FailureKindApplication,
+		Message:      "bang",
+		Type:         "PaymentDeclined",
+		NonRetryable: true,
+		Details:      []*test.Payload{fake.GenInput()},
+		Cause: &test.Failure{
+			Kind:    test.FailureKindTimeout,
+			Message: "boom",
+		},
+	}
+	finished := &test.FinishedCaseExecution{
+		ID:              ce.ID,
+		TestExecutionID: te.ID,
+		FinishTime:      time.Now().UTC(),
+		Error:           &failure.Message,
+		Failure:         failure,
+	}
+	require.NoError(t, s.RecordCaseExecutionFinished(ctx, finished))
+
+	got, err := s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	assert.Equal(t, finished.Error, got.CaseExecution.Error)
+	assert.Equal(t, failure, got.CaseExecution.Failure)
+
+	// Failures are cleared when the case is rescheduled by a retry and rerun
+	_, err = fakes.repo.CreateScheduledCaseExecution(ctx, &test.ScheduledCaseExecution{
+		ID:           ce.ID,
+		TestExecID:   te.ID,
+		CaseName:     ce.CaseName,
+		ScheduleTime: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+	_, err = fakes.repo.UpdateStartedCaseExecution(ctx, &test.StartedCaseExecution{
+		ID:              ce.ID,
+		TestExecutionID: te.ID,
+		StartTime:       time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	got, err = s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	assert.Nil(t, got.CaseExecution.Failure)
+}
+
+func TestService_RecordCaseExecutionHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+	ce, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(te.ID))
+	require.NoError(t, err)
+
+	err = s.RecordCaseExecutionHeartbeat(ctx, &test.CaseExecutionHeartbeat{
+		ID:              fake.GenCaseID(),
+		TestExecutionID: te.ID,
+		HeartbeatTime:   time.Now().UTC(),
+	})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	heartbeat := &test.CaseExecutionHeartbeat{
+		ID:              ce.ID,
+		TestExecutionID: te.ID,
+		HeartbeatTime:   time.Now().UTC(),
+		Progress:        []*test.Payload{fake.GenInput()},
+	}
+	require.NoError(t, s.RecordCaseExecutionHeartbeat(ctx, heartbeat))
+
+	got, err := s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	assert.Equal(t, heartbeat.HeartbeatTime, *got.CaseExecution.HeartbeatTime)
+	assert.Equal(t, heartbeat.Progress, got.CaseExecution.Progress)
+}
+
 func TestService_ListTestCaseExecutions(t *testing.T) {
 	ctx := context.Background()
 	s, fakes := newService()
@@ -132,3 +281,162 @@ func TestService_ListTestCaseExecutions(t *testing.T) {
 	assert.Len(t, got, wantCount)
 	assert.Equal(t, want, got)
 }
+
+func TestService_GetCaseExecutionPayloads(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+	ce, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(te.ID))
+	require.NoError(t, err)
+
+	_, err = s.GetCaseExecutionPayloads(ctx, &GetCaseExecutionPayloadsRequest{TestExecutionID: te.ID, CaseExecutionID: fake.GenCaseID()})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	input := []*test.Payload{fake.GenInput()}
+	require.NoError(t, s.RecordCaseExecutionInput(ctx, te.ID, ce.ID, input))
+	// Cases that return nothing have no result
+	require.NoError(t, s.RecordCaseExecutionResult(ctx, te.ID, ce.ID, nil))
+
+	got, err := s.GetCaseExecutionPayloads(ctx, &GetCaseExecutionPayloadsRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	assert.Equal(t, input, got.Input)
+	assert.Nil(t, got.Result)
+
+	result := fake.GenInput()
+	require.NoError(t, s.RecordCaseExecutionResult(ctx, te.ID, ce.ID, result))
+
+	got, err = s.GetCaseExecutionPayloads(ctx, &GetCaseExecutionPayloadsRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	assert.Equal(t, input, got.Input)
+	assert.Equal(t, result, got.Result)
+}
+
+func TestService_GetCaseExecution(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+	ce, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(te.ID))
+	require.NoError(t, err)
+
+	_, err = s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: fake.GenCaseID()})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	got, err := s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	assert.Equal(t, ce.ID, got.CaseExecution.ID)
+	assert.Empty(t, got.Attempts)
+
+	for attempt := int32(1); attempt <= 2; attempt++ {
+		require.NoError(t, s.RecordCaseExecutionAttemptStarted(ctx, &test.StartedCaseExecutionAttempt{
+			TestExecutionID: te.ID,
+			CaseExecutionID: ce.ID,
+			Attempt:         attempt,
+			StartTime:       time.Now().UTC(),
+		}))
+	}
+	require.NoError(t, s.RecordCaseExecutionAttemptFinished(ctx, &test.FinishedCaseExecutionAttempt{
+		TestExecutionID: te.ID,
+		CaseExecutionID: ce.ID,
+		Attempt:         2,
+		FinishTime:      time.Now().UTC(),
+	}))
+
+	got, err = s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	require.Len(t, got.Attempts, 2)
+	assert.Equal(t, test.ExecutionStatusTimedOut, got.Attempts[0].Status)
+	assert.Equal(t, test.ExecutionStatusPassed, got.Attempts[1].Status)
+
+	// Open attempts are stopped with their test execution
+	require.NoError(t, s.RecordCaseExecutionAttemptStarted(ctx, &test.StartedCaseExecutionAttempt{
+		TestExecutionID: te.ID,
+		CaseExecutionID: ce.ID,
+		Attempt:         3,
+		StartTime:       time.Now().UTC(),
+	}))
+	_, err = fakes.repo.UpdateCancelledTestExecution(ctx, &test.CancelledTestExecution{
+		ID:         te.ID,
+		CancelTime: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	got, err = s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.NoError(t, err)
+	require.Len(t, got.Attempts, 3)
+	assert.Equal(t, test.ExecutionStatusCancelled, got.Attempts[2].Status)
+}
+
+func TestService_GetCaseExecution_archivedAttempt(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+	ce, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(te.ID))
+	require.NoError(t, err)
+
+	input := []*test.Payload{fake.GenInput()}
+	result := fake.GenInput()
+	require.NoError(t, s.RecordCaseExecutionInput(ctx, te.ID, ce.ID, input))
+	require.NoError(t, s.RecordCaseExecutionResult(ctx, te.ID, ce.ID, result))
+	require.NoError(t, s.RecordCaseExecutionAttemptStarted(ctx, &test.StartedCaseExecutionAttempt{
+		TestExecutionID: te.ID,
+		CaseExecutionID: ce.ID,
+		Attempt:         1,
+		StartTime:       time.Now().UTC(),
+	}))
+	_, err = fakes.repo.UpdateStartedCaseExecution(ctx, &test.StartedCaseExecution{
+		ID:              ce.ID,
+		TestExecutionID: te.ID,
+		StartTime:       time.Now().UTC(),
+	})
+	require.NoError(t, err)
+	progress := []*test.Payload{fake.GenInput()}
+	require.NoError(t, s.RecordCaseExecutionHeartbeat(ctx, &test.CaseExecutionHeartbeat{
+		ID:              ce.ID,
+		TestExecutionID: te.ID,
+		HeartbeatTime:   time.Now().UTC(),
+		Progress:        progress,
+	}))
+
+	// The stale case execution is deleted by the retry but kept by the
+	// archived attempt
+	_, err = fakes.repo.ResetTestExecution(ctx, &test.ResetTestExecution{
+		ID:                  te.ID,
+		ResetTime:           time.Now().UTC(),
+		StaleCaseExecutions: []test.CaseExecutionID{ce.ID},
+	}, func(context.Context) error { return nil })
+	require.NoError(t, err)
+
+	_, err = s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	got, err := s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID, Attempt: te.Attempt})
+	require.NoError(t, err)
+	assert.Equal(t, ce.ID, got.CaseExecution.ID)
+	assert.NotNil(t, got.CaseExecution.HeartbeatTime)
+	assert.Equal(t, progress, got.CaseExecution.Progress)
+	require.Len(t, got.Attempts, 1)
+	assert.Equal(t, int32(1), got.Attempts[0].Attempt)
+
+	payloads, err := s.GetCaseExecutionPayloads(ctx, &GetCaseExecutionPayloadsRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID, Attempt: te.Attempt})
+	require.NoError(t, err)
+	assert.Equal(t, input, payloads.Input)
+	assert.Equal(t, result, payloads.Result)
+
+	_, err = s.GetCaseExecutionPayloads(ctx, &GetCaseExecutionPayloadsRequest{TestExecutionID: te.ID, CaseExecutionID: fake.GenCaseID(), Attempt: te.Attempt})
+	require.ErrorIs(t, err, test.ErrorCaseExecutionNotFound)
+
+	_, err = s.GetCaseExecution(ctx, &GetCaseExecutionRequest{TestExecutionID: te.ID, CaseExecutionID: ce.ID, Attempt: te.Attempt + 1})
+	require.ErrorIs(t, err, test.ErrorAttemptNotFound)
+}
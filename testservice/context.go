@@ -5,6 +5,8 @@ import (
 
 	"connectrpc.com/connect"
 	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+
+	"github.com/annexsh/annex/test"
 )
 
 func (s *Service) ListContexts(
@@ -24,8 +26,82 @@ func (s *Service) RegisterContext(
 	ctx context.Context,
 	req *connect.Request[testsv1.RegisterContextRequest],
 ) (*connect.Response[testsv1.RegisterContextResponse], error) {
-	if err := s.repo.CreateContext(ctx, req.Msg.Context); err != nil {
+	exists, err := s.repo.ContextExists(ctx, req.Msg.Context)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		// Contexts registered before validation remain usable
+		if err = validateIdentifier("context", req.Msg.Context); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.repo.CreateContext(ctx, req.Msg.Context); err != nil {
 		return nil, err
 	}
 	return connect.NewResponse(&testsv1.RegisterContextResponse{}), nil
 }
+
+type ArchiveContextRequest struct {
+	Context string
+}
+
+// ArchiveContext archives a context. Archived contexts are hidden from
+// ListContexts and their tests cannot be executed until the context is
+// restored. Groups, tests and executions of archived contexts are kept.
+func (s *Service) ArchiveContext(ctx context.Context, req *ArchiveContextRequest) error {
+	return s.updateContextArchived(ctx, req.Context, true)
+}
+
+type RestoreContextRequest struct {
+	Context string
+}
+
+// RestoreContext restores an archived context.
+func (s *Service) RestoreContext(ctx context.Context, req *RestoreContextRequest) error {
+	return s.updateContextArchived(ctx, req.Context, false)
+}
+
+func (s *Service) updateContextArchived(ctx context.Context, contextID string, archived bool) error {
+	exists, err := s.repo.ContextExists(ctx, contextID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return test.ErrorContextNotFound
+	}
+	return s.repo.UpdateContextArchived(ctx, contextID, archived)
+}
+
+type DeleteContextRequest struct {
+	Context string
+}
+
+// DeleteContext deletes a context with all of its groups, test runs and the
+// tests, executions, case executions and logs of its groups. A context cannot
+// be deleted while executions are active or runners are polling the task
+// queue of any of its groups.
+func (s *Service) DeleteContext(ctx context.Context, req *DeleteContextRequest) error {
+	contextID := req.Context
+
+	exists, err := s.repo.ContextExists(ctx, contextID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return test.ErrorContextNotFound
+	}
+
+	groups, err := s.repo.ListGroups(ctx, contextID, &test.GroupListFilter{IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+	for _, groupID := range groups {
+		if err = checkNoActiveRunners(ctx, s.repo, contextID, groupID, s.workflower); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.DeleteContext(ctx, contextID)
+}
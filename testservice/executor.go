@@ -3,8 +3,10 @@ package testservice
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"connectrpc.com/connect"
 	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/google/uuid"
@@ -17,10 +19,15 @@ import (
 
 	"github.com/annexsh/annex/test"
 
+	"github.com/annexsh/annex/internal/ptr"
 	"github.com/annexsh/annex/log"
 )
 
-const retryReason = "retry failed test execution"
+const (
+	retryReason = "retry failed test execution"
+	// defaultExecutionTimeout applies to tests registered without an execution timeout.
+	defaultExecutionTimeout = 7 * 24 * time.Hour // 1 week
+)
 
 type executor struct {
 	repo     test.Repository
@@ -37,7 +44,11 @@ func newExecutor(repo test.Repository, workflower Workflower, logger log.Logger)
 }
 
 type executeOptions struct {
-	payload *testsv1.Payload
+	payload          *testsv1.Payload
+	executionTimeout *time.Duration
+	runTimeout       *time.Duration
+	testRunID        *uuid.UUID
+	rerunOfID        *test.TestExecutionID
 }
 
 type executeOption func(opts *executeOptions)
@@ -48,66 +59,241 @@ func withInput(input *testsv1.Payload) executeOption {
 	}
 }
 
+// withExecutionTimeout overrides the execution timeout of the test.
+func withExecutionTimeout(timeout time.Duration) executeOption {
+	return func(opts *executeOptions) {
+		opts.executionTimeout = &timeout
+	}
+}
+
+// withRunTimeout overrides the run timeout of the test.
+func withRunTimeout(timeout time.Duration) executeOption {
+	return func(opts *executeOptions) {
+		opts.runTimeout = &timeout
+	}
+}
+
+// withTestRun adds the test execution to a test run.
+func withTestRun(testRunID uuid.UUID) executeOption {
+	return func(opts *executeOptions) {
+		opts.testRunID = &testRunID
+	}
+}
+
+// withRerunOf links the test execution to the test execution it reruns.
+func withRerunOf(testExecID test.TestExecutionID) executeOption {
+	return func(opts *executeOptions) {
+		opts.rerunOfID = &testExecID
+	}
+}
+
+// scheduledExecution is a test execution that is ready to be created and
+// started.
+type scheduledExecution struct {
+	scheduled *test.ScheduledTestExecution
+	testName  string
+	wfOpts    client.StartWorkflowOptions
+	payload   *testsv1.Payload
+}
+
 func (e *executor) execute(ctx context.Context, testID uuid.UUID, opts ...executeOption) (*test.TestExecution, error) {
 	t, err := e.repo.GetTest(ctx, testID)
 	if err != nil {
 		return nil, err
 	}
 
+	exec, err := e.schedule(ctx, t, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	testExec, err := e.repo.CreateScheduledTestExecution(ctx, exec.scheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = e.start(ctx, exec); err != nil {
+		return nil, err
+	}
+
+	return testExec, nil
+}
+
+// schedule validates the test and prepares its test execution without
+// creating or starting it.
+func (e *executor) schedule(ctx context.Context, t *test.Test, opts ...executeOption) (*scheduledExecution, error) {
+	if err := e.validate(ctx, t, opts...); err != nil {
+		return nil, err
+	}
+
 	var options executeOptions
 	for _, opt := range opts {
 		opt(&options)
 	}
 
+	executionTimeout := defaultExecutionTimeout
+	if options.executionTimeout != nil {
+		executionTimeout = *options.executionTimeout
+	} else if t.ExecutionTimeout != nil {
+		executionTimeout = *t.ExecutionTimeout
+	}
+	if executionTimeout <= 0 {
+		return nil, errors.New("execution timeout must be positive")
+	}
+
+	runTimeout := t.RunTimeout
+	if options.runTimeout != nil {
+		runTimeout = options.runTimeout
+	}
+	if runTimeout != nil && *runTimeout <= 0 {
+		return nil, errors.New("run timeout must be positive")
+	}
+
+	taskQueue, err := e.repo.GetGroupTaskQueue(ctx, t.ContextID, t.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
 	execID := test.NewTestExecutionID()
-	workflowID := execID.WorkflowID()
 
 	scheduled := &test.ScheduledTestExecution{
-		ID:           execID,
-		TestID:       t.ID,
-		Payload:      nil, // TODO: payload metadata isn't saved: double check if it is needed
-		ScheduleTime: time.Now(),
+		ID:               execID,
+		TestID:           t.ID,
+		ScheduleTime:     time.Now(),
+		ExecutionTimeout: executionTimeout,
+		RunTimeout:       runTimeout,
+		TestRunID:        options.testRunID,
+		RerunOfID:        options.rerunOfID,
+		TestVersion:      ptr.Get(t.Version),
 	}
 	if options.payload != nil {
 		if options.payload.Metadata == nil {
 			return nil, errors.New("payload metadata cannot be nil")
 		}
-		scheduled.Payload = options.payload.Data
-	}
-
-	testExec, err := e.repo.CreateScheduledTestExecution(ctx, scheduled)
-	if err != nil {
-		return nil, err
+		scheduled.Input = &test.Payload{
+			Metadata: options.payload.Metadata,
+			Data:     options.payload.Data,
+		}
 	}
 
 	wfOpts := client.StartWorkflowOptions{
-		ID:                       workflowID,
-		TaskQueue:                getTaskQueue(t.ContextID, t.GroupID),
-		WorkflowExecutionTimeout: 7 * 24 * time.Hour, // 1 week
+		ID:                       execID.WorkflowID(),
+		TaskQueue:                taskQueue,
+		WorkflowExecutionTimeout: executionTimeout,
 		RetryPolicy: &temporal.RetryPolicy{
 			MaximumAttempts: 1,
 		},
 	}
+	if runTimeout != nil {
+		wfOpts.WorkflowRunTimeout = *runTimeout
+	}
 
-	if options.payload == nil {
-		if _, err = e.temporal.ExecuteWorkflow(ctx, wfOpts, t.Name); err != nil {
-			return nil, err
-		}
+	return &scheduledExecution{
+		scheduled: scheduled,
+		testName:  t.Name,
+		wfOpts:    wfOpts,
+		payload:   options.payload,
+	}, nil
+}
+
+// start starts the workflow of a created test execution. The test execution
+// fails rather than staying scheduled if its workflow cannot be started.
+func (e *executor) start(ctx context.Context, exec *scheduledExecution) error {
+	var err error
+	if exec.payload == nil {
+		_, err = e.temporal.ExecuteWorkflow(ctx, exec.wfOpts, exec.testName)
 	} else {
-		if _, err = e.temporal.ExecuteWorkflow(ctx, wfOpts, t.Name, options.payload); err != nil {
-			return nil, err
+		_, err = e.temporal.ExecuteWorkflow(ctx, exec.wfOpts, exec.testName, exec.payload)
+	}
+	if err == nil {
+		return nil
+	}
+
+	errMsg := fmt.Sprintf("failed to start workflow: %s", err)
+	if _, finishErr := e.repo.UpdateFinishedTestExecution(ctx, &test.FinishedTestExecution{
+		ID:         exec.scheduled.ID,
+		FinishTime: time.Now().UTC(),
+		Error:      &errMsg,
+	}); finishErr != nil {
+		return errors.Join(err, finishErr)
+	}
+	return err
+}
+
+// validate checks that the test can be executed with the options without
+// executing it.
+func (e *executor) validate(ctx context.Context, t *test.Test, opts ...executeOption) error {
+	if t.Deprecated {
+		return test.ErrorTestDeprecated
+	}
+	if err := checkGroupActive(ctx, e.repo, t.ContextID, t.GroupID); err != nil {
+		return err
+	}
+
+	var options executeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if t.InputSchema != nil {
+		if err := validateInput(t.InputSchema, options.payload); err != nil {
+			return connect.NewError(connect.CodeInvalidArgument, err)
 		}
 	}
+	return nil
+}
 
-	return testExec, nil
+type retryOptions struct {
+	fromCaseExec *test.CaseExecutionID
+	fromStart    bool
+}
+
+type retryOption func(opts *retryOptions)
+
+// retryFromCase resets the test execution to just before the case execution
+// was scheduled instead of to the first failure. The case and all later cases
+// are executed again.
+func retryFromCase(id test.CaseExecutionID) retryOption {
+	return func(opts *retryOptions) {
+		opts.fromCaseExec = &id
+	}
+}
+
+// retryFromStart resets the test execution to its first workflow task so that
+// all cases are executed again.
+func retryFromStart() retryOption {
+	return func(opts *retryOptions) {
+		opts.fromStart = true
+	}
 }
 
-// TODO: add safeguard to ensure reset point it after the start test execution signal
-func (e *executor) retry(ctx context.Context, execID test.TestExecutionID) (*test.TestExecution, error) {
+func (e *executor) retry(ctx context.Context, execID test.TestExecutionID, opts ...retryOption) (*test.TestExecution, error) {
+	var options retryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.fromStart && options.fromCaseExec != nil {
+		return nil, errors.New("cannot retry from both the start and a case execution")
+	}
+
 	testExec, err := e.repo.GetTestExecution(ctx, execID)
 	if err != nil {
 		return nil, err
 	}
+	// Sub-executions are retried with their parent
+	if testExec.ParentID != nil {
+		return nil, test.ErrorSubTestExecution
+	}
+
+	t, err := e.repo.GetTest(ctx, testExec.TestID)
+	if err != nil {
+		return nil, err
+	}
+	taskQueue, err := e.repo.GetGroupTaskQueue(ctx, t.ContextID, t.GroupID)
+	if err != nil {
+		return nil, err
+	}
 
 	origCaseExecs, err := e.repo.ListCaseExecutions(ctx, testExec.ID)
 	if err != nil {
@@ -139,12 +325,24 @@ func (e *executor) retry(ctx context.Context, execID test.TestExecutionID) (*tes
 		caseExecsToDelete[c.ID] = c
 	}
 
+	if options.fromCaseExec != nil {
+		if _, ok := caseExecsToDelete[*options.fromCaseExec]; !ok {
+			return nil, test.ErrorCaseExecutionNotFound
+		}
+	}
+
 	eventIDsToCaseIDs := map[int64]test.CaseExecutionID{}
 
 	it := e.temporal.GetWorkflowHistory(ctx, testExec.ID.WorkflowID(), "", false, enums.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
 
 	var resetCaseExec *test.CaseExecution
 	var resetID int64
+	var reachedCaseExec bool
+
+	// A reset discards the events recorded after the reset point, so history
+	// is only preserved once a later resettable event has been reached.
+	var pendingCaseIDs []test.CaseExecutionID
+	var pendingLogIDs []uuid.UUID
 
 	for it.HasNext() {
 		event, err := it.Next()
@@ -152,7 +350,8 @@ func (e *executor) retry(ctx context.Context, execID test.TestExecutionID) (*tes
 			return nil, err
 		}
 
-		if isFailedEvent(event.EventType) {
+		// Failures before the target case are replayed when retrying from a case
+		if options.fromCaseExec == nil && isFailedEvent(event.EventType) {
 			if activityAttrs := event.GetActivityTaskFailedEventAttributes(); activityAttrs != nil {
 				if caseID, ok := eventIDsToCaseIDs[activityAttrs.ScheduledEventId]; ok {
 					if caseExec, ok := caseExecsToDelete[caseID]; ok {
@@ -170,22 +369,48 @@ func (e *executor) retry(ctx context.Context, execID test.TestExecutionID) (*tes
 
 		if isResettableEvent(event.EventType) {
 			resetID = event.EventId
+			if options.fromStart {
+				break
+			}
+			for _, caseID := range pendingCaseIDs {
+				delete(caseExecsToDelete, caseID)
+				delete(caseLogsToDelete, caseID)
+			}
+			for _, logID := range pendingLogIDs {
+				testLogsToDelete.Remove(logID)
+			}
+			pendingCaseIDs, pendingLogIDs = nil, nil
 		}
 
 		switch event.EventType {
+		case enums.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED:
+			// A reset workflow stays on the task queue it was started on, which
+			// is no longer polled for the test once it moved to another group
+			attrs := event.GetWorkflowExecutionStartedEventAttributes()
+			if attrs.GetTaskQueue().GetName() != taskQueue {
+				return nil, test.ErrorTestMoved
+			}
 		case enums.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED:
 			attrs := event.GetActivityTaskScheduledEventAttributes()
 			activityID, err := test.ParseCaseActivityID(attrs.ActivityId)
 			if err != nil {
 				return nil, err
 			}
+			if options.fromCaseExec != nil && activityID == *options.fromCaseExec {
+				reachedCaseExec = true
+			}
 			eventIDsToCaseIDs[event.EventId] = activityID
 		case enums.EVENT_TYPE_ACTIVITY_TASK_COMPLETED:
 			attrs := event.GetActivityTaskCompletedEventAttributes()
 			// Completed without failure indicates history can be preserved
 			if caseID, ok := eventIDsToCaseIDs[attrs.ScheduledEventId]; ok {
-				delete(caseExecsToDelete, caseID)
-				delete(caseLogsToDelete, caseID)
+				pendingCaseIDs = append(pendingCaseIDs, caseID)
+			}
+		case enums.EVENT_TYPE_ACTIVITY_TASK_FAILED:
+			// Only reached when retrying from a case after this failure
+			attrs := event.GetActivityTaskFailedEventAttributes()
+			if caseID, ok := eventIDsToCaseIDs[attrs.ScheduledEventId]; ok {
+				pendingCaseIDs = append(pendingCaseIDs, caseID)
 			}
 		case enums.EVENT_TYPE_MARKER_RECORDED:
 			attrs := event.GetMarkerRecordedEventAttributes()
@@ -198,11 +423,19 @@ func (e *executor) retry(ctx context.Context, execID test.TestExecutionID) (*tes
 						if err = dc.FromPayload(data.Payloads[0], &logResult); err != nil {
 							return nil, err
 						}
-						testLogsToDelete.Remove(logResult.LogID)
+						pendingLogIDs = append(pendingLogIDs, logResult.LogID)
 					}
 				}
 			}
 		}
+
+		if reachedCaseExec {
+			break
+		}
+	}
+
+	if options.fromCaseExec != nil && !reachedCaseExec {
+		return nil, fmt.Errorf("case execution %s was not scheduled by the test execution workflow", *options.fromCaseExec)
 	}
 
 	if resetCaseExec != nil {
@@ -214,32 +447,107 @@ func (e *executor) retry(ctx context.Context, execID test.TestExecutionID) (*tes
 		logsToDelete = append(logsToDelete, caseExecLogs...)
 	}
 
-	reset, rollback, err := e.repo.ResetTestExecution(ctx, &test.ResetTestExecution{
+	resetWorkflow := func(ctx context.Context) error {
+		_, err := e.temporal.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
+			Namespace: "default",
+			WorkflowExecution: &common.WorkflowExecution{
+				WorkflowId: testExec.ID.WorkflowID(),
+			},
+			Reason:                    retryReason,
+			WorkflowTaskFinishEventId: resetID,
+		})
+		return err
+	}
+
+	return e.repo.ResetTestExecution(ctx, &test.ResetTestExecution{
 		ID:                  testExec.ID,
-		ResetTime:           time.Now().UTC().Add(25 * time.Hour),
+		ResetTime:           time.Now().UTC(),
 		StaleCaseExecutions: keys(caseExecsToDelete),
 		StaleLogs:           logsToDelete,
-	})
+	}, resetWorkflow)
+}
+
+func (e *executor) cancel(ctx context.Context, execID test.TestExecutionID) (*test.TestExecution, error) {
+	testExec, err := e.repo.GetTestExecution(ctx, execID)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = e.temporal.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
-		Namespace: "default",
-		WorkflowExecution: &common.WorkflowExecution{
-			WorkflowId: testExec.ID.WorkflowID(),
-		},
-		Reason:                    retryReason,
-		WorkflowTaskFinishEventId: resetID,
+	if testExec.Status.IsFinal() {
+		return nil, test.ErrorTestExecutionFinished
+	}
+	// Sub-executions are cancelled with their parent
+	if testExec.ParentID != nil {
+		return nil, test.ErrorSubTestExecution
+	}
+
+	if err = e.temporal.CancelWorkflow(ctx, testExec.ID.WorkflowID(), ""); err != nil {
+		return nil, err
+	}
+
+	return e.repo.UpdateCancelledTestExecution(ctx, &test.CancelledTestExecution{
+		ID:         testExec.ID,
+		CancelTime: time.Now().UTC(),
 	})
+}
+
+// recordTimeouts settles expired test executions whose workflow has closed.
+// Workers never acknowledge workflows that Temporal timed out or terminated,
+// and acknowledgements of other closed workflows may have been lost. Test
+// executions that cannot be settled are logged and retried on the next pass.
+func (e *executor) recordTimeouts(ctx context.Context) error {
+	expired, err := e.repo.ListExpiredTestExecutions(ctx, time.Now().UTC())
 	if err != nil {
-		if rbErr := rollback(ctx); rbErr != nil {
-			e.logger.Error("failed to rollback retry test execution after workflow failed to reset", "error", rbErr)
+		return err
+	}
+
+	for _, testExec := range expired {
+		if err = e.settleClosedTestExecution(ctx, testExec); err != nil && !errors.Is(err, test.ErrorTestExecutionFinished) {
+			e.logger.Error("failed to settle expired test execution", "test_execution_id", testExec.ID.String(), "error", err)
 		}
-		return nil, err
 	}
 
-	return reset, nil
+	return nil
+}
+
+// settleClosedTestExecution finishes a test execution with the status of its
+// workflow if the workflow has closed.
+func (e *executor) settleClosedTestExecution(ctx context.Context, testExec *test.TestExecution) error {
+	res, err := e.temporal.DescribeWorkflowExecution(ctx, testExec.ID.WorkflowID(), "")
+	if err != nil {
+		return err
+	}
+
+	info := res.GetWorkflowExecutionInfo()
+	closeTime := time.Now().UTC()
+	if info.GetCloseTime() != nil {
+		closeTime = info.GetCloseTime().AsTime()
+	}
+
+	switch info.GetStatus() {
+	case enums.WORKFLOW_EXECUTION_STATUS_TIMED_OUT:
+		_, err = e.repo.UpdateTimedOutTestExecution(ctx, &test.TimedOutTestExecution{
+			ID:          testExec.ID,
+			TimeoutTime: closeTime,
+		})
+	case enums.WORKFLOW_EXECUTION_STATUS_CANCELED, enums.WORKFLOW_EXECUTION_STATUS_TERMINATED:
+		_, err = e.repo.UpdateCancelledTestExecution(ctx, &test.CancelledTestExecution{
+			ID:         testExec.ID,
+			CancelTime: closeTime,
+		})
+	case enums.WORKFLOW_EXECUTION_STATUS_COMPLETED:
+		_, err = e.repo.UpdateFinishedTestExecution(ctx, &test.FinishedTestExecution{
+			ID:         testExec.ID,
+			FinishTime: closeTime,
+		})
+	case enums.WORKFLOW_EXECUTION_STATUS_FAILED:
+		_, err = e.repo.UpdateFinishedTestExecution(ctx, &test.FinishedTestExecution{
+			ID:         testExec.ID,
+			FinishTime: closeTime,
+			Error:      ptr.Get("workflow execution failed"),
+		})
+	}
+	return err
 }
 
 func isResettableEvent(eventType enums.EventType) bool {
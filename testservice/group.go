@@ -2,12 +2,15 @@ package testservice
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"connectrpc.com/connect"
 	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
 	"go.temporal.io/api/enums/v1"
 	"golang.org/x/sync/errgroup"
+
+	"github.com/annexsh/annex/test"
 )
 
 const runnerActiveExpireDuration = time.Minute
@@ -16,16 +19,120 @@ func (s *Service) RegisterGroup(
 	ctx context.Context,
 	req *connect.Request[testsv1.RegisterGroupRequest],
 ) (*connect.Response[testsv1.RegisterGroupResponse], error) {
-	if err := s.repo.CreateGroup(ctx, req.Msg.Context, req.Msg.Name); err != nil {
+	if err := s.validateNewGroup(ctx, req.Msg.Context, req.Msg.Name); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateGroup(ctx, req.Msg.Context, req.Msg.Name, getTaskQueue(req.Msg.Context, req.Msg.Name)); err != nil {
 		return nil, err
 	}
 	return &connect.Response[testsv1.RegisterGroupResponse]{}, nil
 }
 
+type ArchiveGroupRequest struct {
+	Context string
+	Group   string
+}
+
+// ArchiveGroup archives a group. Archived groups are hidden from ListGroups and
+// their tests cannot be executed until the group is restored or registered
+// again. Tests and executions of archived groups are kept.
+func (s *Service) ArchiveGroup(ctx context.Context, req *ArchiveGroupRequest) error {
+	return s.updateGroupArchived(ctx, req.Context, req.Group, true)
+}
+
+type RestoreGroupRequest struct {
+	Context string
+	Group   string
+}
+
+// RestoreGroup restores an archived group.
+func (s *Service) RestoreGroup(ctx context.Context, req *RestoreGroupRequest) error {
+	return s.updateGroupArchived(ctx, req.Context, req.Group, false)
+}
+
+func (s *Service) updateGroupArchived(ctx context.Context, contextID string, groupID string, archived bool) error {
+	exists, err := s.repo.GroupExists(ctx, contextID, groupID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return test.ErrorGroupNotFound
+	}
+	return s.repo.UpdateGroupArchived(ctx, contextID, groupID, archived)
+}
+
+type DeleteGroupRequest struct {
+	Context string
+	Group   string
+}
+
+// DeleteGroup deletes a group with its tests, executions, case executions and
+// logs. A group cannot be deleted while its executions are active or runners
+// are polling its task queue.
+func (s *Service) DeleteGroup(ctx context.Context, req *DeleteGroupRequest) error {
+	contextID := req.Context
+	groupID := req.Group
+
+	exists, err := s.repo.GroupExists(ctx, contextID, groupID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return test.ErrorGroupNotFound
+	}
+
+	if err = checkNoActiveRunners(ctx, s.repo, contextID, groupID, s.workflower); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteGroup(ctx, contextID, groupID)
+}
+
+type RenameGroupRequest struct {
+	Context  string
+	Group    string
+	NewGroup string
+}
+
+// RenameGroup renames a group. Tests of the group keep their IDs and execution
+// history.
+//
+// Groups keep their task queue when renamed, so executions dispatched before
+// the rename, and retries of them, are still received by runners of the
+// group. Runners register tests under the name of their group, so they must be
+// stopped before the rename and started with the new group name after it.
+func (s *Service) RenameGroup(ctx context.Context, req *RenameGroupRequest) error {
+	contextID := req.Context
+	groupID := req.Group
+	newGroupID := req.NewGroup
+
+	if newGroupID == groupID {
+		return nil
+	}
+
+	exists, err := s.repo.GroupExists(ctx, contextID, groupID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return test.ErrorGroupNotFound
+	}
+
+	if err = s.validateNewGroup(ctx, contextID, newGroupID); err != nil {
+		return err
+	}
+
+	if err = checkNoActiveRunners(ctx, s.repo, contextID, groupID, s.workflower); err != nil {
+		return err
+	}
+
+	return s.repo.RenameGroup(ctx, contextID, groupID, newGroupID)
+}
+
 func (s *Service) ListGroups(ctx context.Context, req *connect.Request[testsv1.ListGroupsRequest]) (*connect.Response[testsv1.ListGroupsResponse], error) {
 	contextID := req.Msg.Context
 
-	groupIDs, err := s.repo.ListGroups(ctx, contextID)
+	groupIDs, err := s.repo.ListGroups(ctx, contextID, &test.GroupListFilter{})
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +152,7 @@ func (s *Service) ListGroups(ctx context.Context, req *connect.Request[testsv1.L
 	for i, groupID := range groupIDs {
 		sem <- struct{}{}
 		errg.Go(func() error {
-			runners, isGroupAvail, err := getGroupRunners(errCtx, contextID, groupID, s.workflower)
+			runners, isGroupAvail, err := getGroupRunners(errCtx, s.repo, contextID, groupID, s.workflower)
 			if err != nil {
 				return err
 			}
@@ -77,8 +184,11 @@ func (s *Service) ListGroups(ctx context.Context, req *connect.Request[testsv1.L
 	}), nil
 }
 
-func getGroupRunners(ctx context.Context, contextID string, groupID string, workflower Workflower) ([]*testsv1.Group_Runner, bool, error) {
-	taskQueue := getTaskQueue(contextID, groupID)
+func getGroupRunners(ctx context.Context, repo test.Repository, contextID string, groupID string, workflower Workflower) ([]*testsv1.Group_Runner, bool, error) {
+	taskQueue, err := repo.GetGroupTaskQueue(ctx, contextID, groupID)
+	if err != nil {
+		return nil, false, err
+	}
 	taskQueueRes, err := workflower.DescribeTaskQueue(ctx, taskQueue, enums.TASK_QUEUE_TYPE_WORKFLOW)
 	if err != nil {
 		return nil, false, err
@@ -92,10 +202,42 @@ func getGroupRunners(ctx context.Context, contextID string, groupID string, work
 			Id:             poller.Identity,
 			LastAccessTime: poller.LastAccessTime,
 		}
-		if poller.LastAccessTime.AsTime().Sub(time.Now()) < runnerActiveExpireDuration {
+		if time.Since(poller.LastAccessTime.AsTime()) < runnerActiveExpireDuration {
 			isGroupAvail = true
 		}
 	}
 
 	return runners, isGroupAvail, nil
 }
+
+func checkNoActiveRunners(ctx context.Context, repo test.Repository, contextID string, groupID string, workflower Workflower) error {
+	_, isGroupAvail, err := getGroupRunners(ctx, repo, contextID, groupID, workflower)
+	if err != nil {
+		return err
+	}
+	if isGroupAvail {
+		return fmt.Errorf("group '%s': %w", groupID, test.ErrorRunnersActive)
+	}
+	return nil
+}
+
+// checkGroupActive checks that neither the context nor the group of a test is
+// archived.
+func checkGroupActive(ctx context.Context, repo test.Repository, contextID string, groupID string) error {
+	archived, err := repo.IsContextArchived(ctx, contextID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return test.ErrorContextArchived
+	}
+
+	if archived, err = repo.IsGroupArchived(ctx, contextID, groupID); err != nil {
+		return err
+	}
+	if archived {
+		return test.ErrorGroupArchived
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package testservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/taskqueue/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/test"
+)
+
+func TestService_ArchiveGroup(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+
+	require.NoError(t, fakes.repo.CreateContext(ctx, def.ContextID))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, def.ContextID, def.GroupID, getTaskQueue(def.ContextID, def.GroupID)))
+	tt, err := fakes.repo.CreateTest(ctx, def)
+	require.NoError(t, err)
+
+	err = s.ArchiveGroup(ctx, &ArchiveGroupRequest{Context: def.ContextID, Group: uuid.NewString()})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+
+	require.NoError(t, s.ArchiveGroup(ctx, &ArchiveGroupRequest{Context: def.ContextID, Group: def.GroupID}))
+
+	groups, err := fakes.repo.ListGroups(ctx, def.ContextID, &test.GroupListFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	execute := func() error {
+		_, err := s.ExecuteTest(ctx, connect.NewRequest(&testsv1.ExecuteTestRequest{TestId: tt.ID.String()}))
+		return err
+	}
+
+	require.ErrorIs(t, execute(), test.ErrorGroupArchived)
+
+	require.NoError(t, s.RestoreGroup(ctx, &RestoreGroupRequest{Context: def.ContextID, Group: def.GroupID}))
+	require.NoError(t, execute())
+
+	require.NoError(t, s.ArchiveContext(ctx, &ArchiveContextRequest{Context: def.ContextID}))
+	require.ErrorIs(t, execute(), test.ErrorContextArchived)
+
+	contexts, err := fakes.repo.ListContexts(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, contexts, def.ContextID)
+
+	require.NoError(t, s.RestoreContext(ctx, &RestoreContextRequest{Context: def.ContextID}))
+	require.NoError(t, execute())
+}
+
+func TestService_DeleteGroup(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+	taskQueue := getTaskQueue(contextID, groupID)
+
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, contextID, groupID, getTaskQueue(contextID, groupID)))
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID)))
+	require.NoError(t, err)
+
+	err = s.DeleteGroup(ctx, &DeleteGroupRequest{Context: contextID, Group: uuid.NewString()})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+
+	fakes.workflower.SetPollers(taskQueue, &taskqueue.PollerInfo{
+		Identity:       "runner",
+		LastAccessTime: timestamppb.Now(),
+	})
+	err = s.DeleteGroup(ctx, &DeleteGroupRequest{Context: contextID, Group: groupID})
+	require.ErrorIs(t, err, test.ErrorRunnersActive)
+
+	fakes.workflower.SetPollers(taskQueue, &taskqueue.PollerInfo{
+		Identity:       "runner",
+		LastAccessTime: timestamppb.New(time.Now().Add(-time.Hour)),
+	})
+	res, err := s.ListGroups(ctx, connect.NewRequest(&testsv1.ListGroupsRequest{Context: contextID}))
+	require.NoError(t, err)
+	require.Len(t, res.Msg.Groups, 1)
+	assert.False(t, res.Msg.Groups[0].Available)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+	err = s.DeleteGroup(ctx, &DeleteGroupRequest{Context: contextID, Group: groupID})
+	require.ErrorIs(t, err, test.ErrorExecutionsActive)
+
+	_, err = fakes.repo.UpdateCancelledTestExecution(ctx, &test.CancelledTestExecution{
+		ID:         te.ID,
+		CancelTime: time.Now(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteGroup(ctx, &DeleteGroupRequest{Context: contextID, Group: groupID}))
+
+	_, err = fakes.repo.GetTest(ctx, tt.ID)
+	require.Error(t, err)
+	_, err = fakes.repo.GetTestExecution(ctx, te.ID)
+	require.Error(t, err)
+}
+
+func TestService_DeleteContext(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, contextID, groupID, getTaskQueue(contextID, groupID)))
+	require.NoError(t, fakes.repo.UpdateGroupArchived(ctx, contextID, groupID, true))
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID)))
+	require.NoError(t, err)
+
+	err = s.DeleteContext(ctx, &DeleteContextRequest{Context: uuid.NewString()})
+	require.ErrorIs(t, err, test.ErrorContextNotFound)
+
+	// Runners of archived groups block deletion too
+	err = s.DeleteContext(ctx, &DeleteContextRequest{Context: contextID})
+	require.ErrorIs(t, err, test.ErrorRunnersActive)
+
+	fakes.workflower.SetPollers(getTaskQueue(contextID, groupID))
+	require.NoError(t, s.DeleteContext(ctx, &DeleteContextRequest{Context: contextID}))
+
+	exists, err := fakes.repo.ContextExists(ctx, contextID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	groups, err := fakes.repo.ListGroups(ctx, contextID, &test.GroupListFilter{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	_, err = fakes.repo.GetTest(ctx, tt.ID)
+	require.Error(t, err)
+}
+
+func TestService_RenameGroup(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+	newGroupID := uuid.NewString()
+
+	require.NoError(t, fakes.repo.CreateContext(ctx, def.ContextID))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, def.ContextID, def.GroupID, getTaskQueue(def.ContextID, def.GroupID)))
+	tt, err := fakes.repo.CreateTest(ctx, def)
+	require.NoError(t, err)
+	before := createTestExec(t, ctx, fakes.repo, tt.ID, nil)
+
+	err = s.RenameGroup(ctx, &RenameGroupRequest{Context: def.ContextID, Group: uuid.NewString(), NewGroup: newGroupID})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+
+	// Runners of the group would be stranded on the old task queue
+	err = s.RenameGroup(ctx, &RenameGroupRequest{Context: def.ContextID, Group: def.GroupID, NewGroup: newGroupID})
+	require.ErrorIs(t, err, test.ErrorRunnersActive)
+
+	fakes.workflower.SetPollers(getTaskQueue(def.ContextID, def.GroupID))
+	require.NoError(t, s.RenameGroup(ctx, &RenameGroupRequest{Context: def.ContextID, Group: def.GroupID, NewGroup: newGroupID}))
+
+	got, err := fakes.repo.GetTest(ctx, tt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newGroupID, got.GroupID)
+
+	execs, err := fakes.repo.ListTestExecutions(ctx, tt.ID, &test.TestExecutionListFilter{PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, execs, 1)
+	assert.Equal(t, before.ID, execs[0].ID)
+
+	res, err := s.ExecuteTest(ctx, connect.NewRequest(&testsv1.ExecuteTestRequest{TestId: tt.ID.String()}))
+	require.NoError(t, err)
+	execID, err := test.ParseTestExecutionID(res.Msg.TestExecution.Id)
+	require.NoError(t, err)
+
+	// Renamed groups keep their task queue
+	wr := fakes.workflower.GetWorkflow(ctx, execID.WorkflowID(), "")
+	assert.Equal(t, getTaskQueue(def.ContextID, def.GroupID), wr.(*fake.WorkflowRun).Options().TaskQueue)
+}
+
+func TestService_groupNotFound_postgres(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newPostgresService(t)
+
+	contextID := uniqueName()
+	groupID := uniqueName()
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, contextID, groupID, getTaskQueue(contextID, groupID)))
+
+	missing := uniqueName()
+	err := s.ArchiveGroup(ctx, &ArchiveGroupRequest{Context: contextID, Group: missing})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+	err = s.RestoreGroup(ctx, &RestoreGroupRequest{Context: contextID, Group: missing})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+	err = s.DeleteGroup(ctx, &DeleteGroupRequest{Context: contextID, Group: missing})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+	err = s.ArchiveGroup(ctx, &ArchiveGroupRequest{Context: uniqueName(), Group: groupID})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+
+	require.NoError(t, s.ArchiveGroup(ctx, &ArchiveGroupRequest{Context: contextID, Group: groupID}))
+	require.NoError(t, s.RestoreGroup(ctx, &RestoreGroupRequest{Context: contextID, Group: groupID}))
+	fakes.workflower.SetPollers(getTaskQueue(contextID, groupID))
+	require.NoError(t, s.DeleteGroup(ctx, &DeleteGroupRequest{Context: contextID, Group: groupID}))
+
+	err = s.DeleteGroup(ctx, &DeleteGroupRequest{Context: contextID, Group: groupID})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+}
+
+func TestService_contextNotFound_postgres(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newPostgresService(t)
+
+	contextID := uniqueName()
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID))
+
+	missing := uniqueName()
+	err := s.ArchiveContext(ctx, &ArchiveContextRequest{Context: missing})
+	require.ErrorIs(t, err, test.ErrorContextNotFound)
+	err = s.RestoreContext(ctx, &RestoreContextRequest{Context: missing})
+	require.ErrorIs(t, err, test.ErrorContextNotFound)
+	err = s.DeleteContext(ctx, &DeleteContextRequest{Context: missing})
+	require.ErrorIs(t, err, test.ErrorContextNotFound)
+
+	require.NoError(t, s.ArchiveContext(ctx, &ArchiveContextRequest{Context: contextID}))
+	require.NoError(t, s.RestoreContext(ctx, &RestoreContextRequest{Context: contextID}))
+	require.NoError(t, s.DeleteContext(ctx, &DeleteContextRequest{Context: contextID}))
+
+	err = s.DeleteContext(ctx, &DeleteContextRequest{Context: contextID})
+	require.ErrorIs(t, err, test.ErrorContextNotFound)
+}
@@ -0,0 +1,69 @@
+package testservice
+
+import (
+	"bytes"
+	"fmt"
+
+	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"go.temporal.io/sdk/converter"
+)
+
+// inputSchemaURL identifies the input schema being compiled. Relative
+// references in the schema resolve against it.
+const inputSchemaURL = "urn:annex:input-schema"
+
+// compileInputSchema compiles the JSON Schema of a test input. Formats are
+// asserted rather than treated as annotations. References may only point
+// within the schema or to the standard meta-schemas, so that registered
+// schemas cannot load files or URLs from the server.
+func compileInputSchema(schema []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	c.UseLoader(jsonschema.SchemeURLLoader{})
+	if err = c.AddResource(inputSchemaURL, doc); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	compiled, err := c.Compile(inputSchemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return compiled, nil
+}
+
+// validateInput validates an input against the JSON Schema of a test. A nil
+// input is validated as null since the test then executes without one. The
+// error wraps a *jsonschema.ValidationError describing each invalid value.
+func validateInput(schema []byte, input *testsv1.Payload) error {
+	compiled, err := compileInputSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	data := []byte("null")
+
+	if input != nil {
+		switch encoding := string(input.Metadata[converter.MetadataEncoding]); encoding {
+		case converter.MetadataEncodingJSON:
+			data = input.Data
+		case converter.MetadataEncodingNil:
+		default:
+			return fmt.Errorf("input with encoding '%s' cannot be validated against the input schema", encoding)
+		}
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+	if err = compiled.Validate(doc); err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package testservice
+
+import (
+	"strings"
+	"testing"
+
+	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+)
+
+func TestValidateInput(t *testing.T) {
+	tests := []struct {
+		name          string
+		schema        string
+		input         any
+		wantSchemaErr bool
+		wantInvalid   []string // locations of the invalid values
+	}{
+		{
+			name:   "valid input",
+			schema: testInputSchema,
+			input:  map[string]any{"Foo": 1, "Bar": "bar"},
+		},
+		{
+			name:        "invalid input",
+			schema:      testInputSchema,
+			input:       map[string]any{"Foo": 1.5, "Bar": ""},
+			wantInvalid: []string{"/Foo", "/Bar"},
+		},
+		{
+			name:   "local reference",
+			schema: `{"$defs": {"user": {"type": "object", "required": ["name"]}}, "properties": {"user": {"$ref": "#/$defs/user"}}}`,
+			input:  map[string]any{"user": map[string]any{"name": "foo"}},
+		},
+		{
+			name:        "invalid local reference",
+			schema:      `{"$defs": {"user": {"type": "object", "required": ["name"]}}, "properties": {"user": {"$ref": "#/$defs/user"}}}`,
+			input:       map[string]any{"user": map[string]any{}},
+			wantInvalid: []string{"/user"},
+		},
+		{
+			name:        "format",
+			schema:      `{"type": "string", "format": "email"}`,
+			input:       "foo",
+			wantInvalid: []string{""},
+		},
+		{
+			name:          "file reference",
+			schema:        `{"$ref": "file:///etc/passwd"}`,
+			wantSchemaErr: true,
+		},
+		{
+			name:          "invalid schema",
+			schema:        `{"type": "text"}`,
+			wantSchemaErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := converter.NewJSONPayloadConverter().ToPayload(tt.input)
+			require.NoError(t, err)
+
+			err = validateInput([]byte(tt.schema), &testsv1.Payload{
+				Metadata: input.Metadata,
+				Data:     input.Data,
+			})
+			if tt.wantSchemaErr {
+				require.ErrorContains(t, err, "invalid schema")
+				return
+			}
+			if tt.wantInvalid != nil {
+				var validationErr *jsonschema.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.ElementsMatch(t, tt.wantInvalid, invalidLocations(validationErr))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// invalidLocations gets the JSON Pointers of the values that failed validation.
+func invalidLocations(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		loc := ""
+		for _, token := range err.InstanceLocation {
+			loc += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+		}
+		return []string{loc}
+	}
+
+	var locs []string
+	for _, cause := range err.Causes {
+		locs = append(locs, invalidLocations(cause)...)
+	}
+	return locs
+}
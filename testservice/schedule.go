@@ -0,0 +1,202 @@
+package testservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/annexsh/annex/test"
+)
+
+type CreateScheduleRequest struct {
+	TestID uuid.UUID
+	Cron   string           // standard cron spec or descriptor, e.g. "0 * * * *" or "@hourly"
+	Input  *testsv1.Payload // optional: executions have no input when nil
+}
+
+// CreateSchedule creates a recurring execution of a test. Each fire of the
+// schedule executes the test the same way as ExecuteTest.
+func (s *Service) CreateSchedule(ctx context.Context, req *CreateScheduleRequest) (*test.Schedule, error) {
+	t, err := s.repo.GetTest(ctx, req.TestID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Deprecated {
+		return nil, test.ErrorTestDeprecated
+	}
+	if err = checkGroupActive(ctx, s.repo, t.ContextID, t.GroupID); err != nil {
+		return nil, err
+	}
+
+	if t.InputSchema != nil {
+		if err = validateInput(t.InputSchema, req.Input); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+
+	next, err := nextFireTime(req.Cron, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	def := &test.ScheduleDefinition{
+		ID:           uuid.New(),
+		TestID:       req.TestID,
+		Cron:         req.Cron,
+		NextFireTime: next,
+	}
+	if req.Input != nil {
+		if req.Input.Metadata == nil {
+			return nil, errors.New("payload metadata cannot be nil")
+		}
+		def.Input = &test.Payload{
+			Metadata: req.Input.Metadata,
+			Data:     req.Input.Data,
+		}
+	}
+
+	return s.repo.CreateSchedule(ctx, def)
+}
+
+type ListSchedulesRequest struct {
+	TestID uuid.UUID
+}
+
+func (s *Service) ListSchedules(ctx context.Context, req *ListSchedulesRequest) (test.ScheduleList, error) {
+	return s.repo.ListSchedules(ctx, req.TestID)
+}
+
+type PauseScheduleRequest struct {
+	ScheduleID uuid.UUID
+}
+
+// PauseSchedule stops a schedule from firing until it is resumed.
+func (s *Service) PauseSchedule(ctx context.Context, req *PauseScheduleRequest) (*test.Schedule, error) {
+	return s.repo.UpdatePausedSchedule(ctx, req.ScheduleID)
+}
+
+type ResumeScheduleRequest struct {
+	ScheduleID uuid.UUID
+}
+
+// ResumeSchedule resumes a paused schedule. Fires missed while paused are
+// skipped.
+func (s *Service) ResumeSchedule(ctx context.Context, req *ResumeScheduleRequest) (*test.Schedule, error) {
+	schedule, err := s.repo.GetSchedule(ctx, req.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := nextFireTime(schedule.Cron, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.UpdateResumedSchedule(ctx, &test.ResumedSchedule{
+		ID:           req.ScheduleID,
+		NextFireTime: next,
+	})
+}
+
+type DeleteScheduleRequest struct {
+	ScheduleID uuid.UUID
+}
+
+func (s *Service) DeleteSchedule(ctx context.Context, req *DeleteScheduleRequest) error {
+	return s.repo.DeleteSchedule(ctx, req.ScheduleID)
+}
+
+// RunSchedules periodically executes the tests of due schedules. It blocks
+// until the context is done.
+func (s *Service) RunSchedules(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.executor.fireSchedules(ctx); err != nil {
+				s.logger.Error("failed to fire test schedules", "error", err)
+			}
+		}
+	}
+}
+
+// fireSchedules executes the tests of all due schedules. Fires missed while
+// the server was down are collapsed into a single execution.
+func (e *executor) fireSchedules(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	due, err := e.repo.ListDueSchedules(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, schedule := range due {
+		if err = e.fireSchedule(ctx, schedule, now); err != nil {
+			errs = append(errs, fmt.Errorf("schedule %s: %w", schedule.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e *executor) fireSchedule(ctx context.Context, schedule *test.Schedule, now time.Time) error {
+	next, err := nextFireTime(schedule.Cron, now)
+	if err != nil {
+		return err
+	}
+
+	// Claim the fire before executing so that a schedule is only fired once
+	// when multiple servers share a database.
+	_, err = e.repo.UpdateFiredSchedule(ctx, &test.FiredSchedule{
+		ID:           schedule.ID,
+		FireTime:     schedule.NextFireTime,
+		NextFireTime: next,
+	})
+	if err != nil {
+		if errors.Is(err, test.ErrorScheduleNotDue) {
+			return nil
+		}
+		return err
+	}
+
+	var opts []executeOption
+	if schedule.HasInput {
+		input, err := e.repo.GetScheduleInput(ctx, schedule.ID)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, withInput(input.Proto()))
+	}
+
+	_, err = e.execute(ctx, schedule.TestID, opts...)
+	if errors.Is(err, test.ErrorTestDeprecated) || errors.Is(err, test.ErrorGroupArchived) || errors.Is(err, test.ErrorContextArchived) {
+		// The schedule resumes firing if the test is registered again or its
+		// group and context are restored.
+		return nil
+	}
+	return err
+}
+
+func nextFireTime(spec string, after time.Time) (time.Time, error) {
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron spec: %w", err)
+	}
+	next := sched.Next(after)
+	if next.IsZero() {
+		return time.Time{}, errors.New("cron spec never fires")
+	}
+	return next, nil
+}
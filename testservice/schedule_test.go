@@ -0,0 +1,184 @@
+package testservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/internal/ptr"
+	"github.com/annexsh/annex/test"
+)
+
+func TestService_CreateSchedule(t *testing.T) {
+	invalidInput, err := converter.NewJSONPayloadConverter().ToPayload(map[string]any{"Foo": "bar"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		cron        string
+		input       *testsv1.Payload
+		inputSchema string
+		testID      *uuid.UUID
+		wantErr     bool
+		wantCode    connect.Code
+	}{
+		{
+			name: "create success",
+			cron: "*/5 * * * *",
+		},
+		{
+			name:  "create with input success",
+			cron:  "@daily",
+			input: fake.GenInput().Proto(),
+		},
+		{
+			name:    "invalid cron spec error",
+			cron:    "every five minutes",
+			wantErr: true,
+		},
+		{
+			name:    "input without metadata error",
+			cron:    "@daily",
+			input:   &testsv1.Payload{Data: fake.GenInput().Data},
+			wantErr: true,
+		},
+		{
+			name: "input failing schema error",
+			cron: "@daily",
+			input: &testsv1.Payload{
+				Metadata: invalidInput.Metadata,
+				Data:     invalidInput.Data,
+			},
+			inputSchema: testInputSchema,
+			wantErr:     true,
+			wantCode:    connect.CodeInvalidArgument,
+		},
+		{
+			name:    "test not found error",
+			cron:    "@daily",
+			testID:  ptr.Get(uuid.New()),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, fakes := newService()
+
+			def := fake.GenTestDefinition()
+			if tt.inputSchema != "" {
+				def.InputSchema = []byte(tt.inputSchema)
+			}
+			created, err := createTest(ctx, fakes.repo, def)
+			require.NoError(t, err)
+
+			testID := created.ID
+			if tt.testID != nil {
+				testID = *tt.testID
+			}
+
+			got, err := s.CreateSchedule(ctx, &CreateScheduleRequest{
+				TestID: testID,
+				Cron:   tt.cron,
+				Input:  tt.input,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantCode != 0 {
+					assert.Equal(t, tt.wantCode, connect.CodeOf(err))
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testID, got.TestID)
+			assert.Equal(t, tt.cron, got.Cron)
+			assert.Equal(t, tt.input != nil, got.HasInput)
+			assert.True(t, got.NextFireTime.After(time.Now()))
+
+			list, err := s.ListSchedules(ctx, &ListSchedulesRequest{TestID: testID})
+			require.NoError(t, err)
+			assert.Equal(t, test.ScheduleList{got}, list)
+		})
+	}
+}
+
+func TestService_fireSchedules(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+
+	tt, err := createTest(ctx, fakes.repo, def)
+	require.NoError(t, err)
+
+	schedule, err := s.CreateSchedule(ctx, &CreateScheduleRequest{
+		TestID: tt.ID,
+		Cron:   "@hourly",
+		Input:  fake.GenInput().Proto(),
+	})
+	require.NoError(t, err)
+
+	// Simulate the schedule being due
+	dueTime := time.Now().UTC().Add(-time.Minute)
+	_, err = fakes.repo.UpdateResumedSchedule(ctx, &test.ResumedSchedule{
+		ID:           schedule.ID,
+		NextFireTime: dueTime,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.executor.fireSchedules(ctx))
+
+	execs, err := fakes.repo.ListTestExecutions(ctx, tt.ID, &test.TestExecutionListFilter{PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, execs, 1)
+	assert.True(t, execs[0].HasInput)
+
+	wr := fakes.workflower.GetWorkflow(ctx, execs[0].ID.WorkflowID(), "")
+	require.NoError(t, wr.Get(ctx, nil))
+
+	got, err := fakes.repo.GetSchedule(ctx, schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dueTime, *got.LastFireTime)
+	assert.True(t, got.NextFireTime.After(time.Now()))
+
+	// Not due until the next fire time
+	require.NoError(t, s.executor.fireSchedules(ctx))
+	execs, err = fakes.repo.ListTestExecutions(ctx, tt.ID, &test.TestExecutionListFilter{PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, execs, 1)
+
+	// Paused schedules don't fire
+	_, err = fakes.repo.UpdateResumedSchedule(ctx, &test.ResumedSchedule{
+		ID:           schedule.ID,
+		NextFireTime: dueTime,
+	})
+	require.NoError(t, err)
+	_, err = s.PauseSchedule(ctx, &PauseScheduleRequest{ScheduleID: schedule.ID})
+	require.NoError(t, err)
+
+	require.NoError(t, s.executor.fireSchedules(ctx))
+	execs, err = fakes.repo.ListTestExecutions(ctx, tt.ID, &test.TestExecutionListFilter{PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, execs, 1)
+
+	got, err = s.ResumeSchedule(ctx, &ResumeScheduleRequest{ScheduleID: schedule.ID})
+	require.NoError(t, err)
+	assert.False(t, got.Paused)
+	assert.True(t, got.NextFireTime.After(time.Now()))
+
+	require.NoError(t, s.DeleteSchedule(ctx, &DeleteScheduleRequest{ScheduleID: schedule.ID}))
+	list, err := s.ListSchedules(ctx, &ListSchedulesRequest{TestID: tt.ID})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
@@ -1,3 +1,15 @@
+// Package testservice implements the tests API on top of a test repository and
+// Temporal.
+//
+// Operations that the tests API does not define yet are exported as Service
+// methods that take a request struct and return test types, such as
+// CancelTestExecution with a CancelTestExecutionRequest. They are meant to be
+// exposed as Connect RPCs once the API defines them. Methods called by the
+// workflow proxy take test types instead.
+//
+// The tests API types also omit execution statuses and structured failures.
+// GetTestExecutionTree and GetTestExecutionAttempt return them with the test
+// and case executions.
 package testservice
 
 import (
@@ -22,6 +34,7 @@ type Workflower interface {
 	GetWorkflowHistory(ctx context.Context, workflowID string, runID string, isLongPoll bool, filterType enums.HistoryEventFilterType) client.HistoryEventIterator
 	ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error)
 	CancelWorkflow(ctx context.Context, workflowID string, runID string) error
+	DescribeWorkflowExecution(ctx context.Context, workflowID string, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error)
 	DescribeTaskQueue(ctx context.Context, taskQueue string, taskQueueType enums.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error)
 }
 
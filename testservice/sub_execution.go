@@ -0,0 +1,78 @@
+package testservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/annexsh/annex/test"
+)
+
+// TestExecutionTree is a test execution with its case executions and the
+// sub-executions of the child workflows it started, in schedule order.
+type TestExecutionTree struct {
+	TestExecution  *test.TestExecution
+	CaseExecutions test.CaseExecutionList
+	SubExecutions  []*TestExecutionTree
+}
+
+// RecordSubTestExecutionScheduled records a child workflow started by a test
+// execution as a sub-execution of the test execution.
+func (s *Service) RecordSubTestExecutionScheduled(ctx context.Context, scheduled *test.ScheduledSubTestExecution) error {
+	if _, err := s.repo.CreateScheduledSubTestExecution(ctx, scheduled); err != nil {
+		return fmt.Errorf("failed to create sub test execution: %w", err)
+	}
+	return nil
+}
+
+// ResolveSubTestExecution resolves a child workflow to its sub-execution. It
+// returns test.ErrorNotTestExecution if the workflow is not a child workflow of
+// a test execution.
+func (s *Service) ResolveSubTestExecution(ctx context.Context, childWorkflowID string) (test.TestExecutionID, error) {
+	subExec, err := s.repo.GetSubTestExecution(ctx, childWorkflowID)
+	if err != nil {
+		if errors.Is(err, test.ErrorTestExecutionNotFound) {
+			return test.TestExecutionID{}, test.ErrorNotTestExecution
+		}
+		return test.TestExecutionID{}, err
+	}
+	return subExec.ID, nil
+}
+
+type GetTestExecutionTreeRequest struct {
+	TestExecutionID test.TestExecutionID
+}
+
+// GetTestExecutionTree gets a test execution with its nested sub-executions.
+func (s *Service) GetTestExecutionTree(ctx context.Context, req *GetTestExecutionTreeRequest) (*TestExecutionTree, error) {
+	testExec, err := s.repo.GetTestExecution(ctx, req.TestExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.getTestExecutionTree(ctx, testExec)
+}
+
+func (s *Service) getTestExecutionTree(ctx context.Context, testExec *test.TestExecution) (*TestExecutionTree, error) {
+	caseExecs, err := s.repo.ListCaseExecutions(ctx, testExec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	subExecs, err := s.repo.ListSubTestExecutions(ctx, testExec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &TestExecutionTree{
+		TestExecution:  testExec,
+		CaseExecutions: caseExecs,
+		SubExecutions:  make([]*TestExecutionTree, len(subExecs)),
+	}
+	for i, subExec := range subExecs {
+		if tree.SubExecutions[i], err = s.getTestExecutionTree(ctx, subExec); err != nil {
+			return nil, err
+		}
+	}
+
+	return tree, nil
+}
@@ -0,0 +1,90 @@
+package testservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/test"
+)
+
+func TestService_GetTestExecutionTree(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+	te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+
+	_, err = s.ResolveSubTestExecution(ctx, "child")
+	require.ErrorIs(t, err, test.ErrorNotTestExecution)
+
+	child := &test.ScheduledSubTestExecution{
+		ID:                test.NewSubTestExecutionID(te.ID, "run", "child"),
+		ParentID:          te.ID,
+		ChildWorkflowID:   "child",
+		ChildWorkflowType: "ChildWorkflow",
+		ScheduleTime:      time.Now().UTC(),
+	}
+	require.NoError(t, s.RecordSubTestExecutionScheduled(ctx, child))
+
+	childID, err := s.ResolveSubTestExecution(ctx, child.ChildWorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, child.ID, childID)
+
+	grandchild := &test.ScheduledSubTestExecution{
+		ID:                test.NewSubTestExecutionID(childID, "run", "grandchild"),
+		ParentID:          childID,
+		ChildWorkflowID:   "grandchild",
+		ChildWorkflowType: "GrandchildWorkflow",
+		ScheduleTime:      time.Now().UTC(),
+	}
+	require.NoError(t, s.RecordSubTestExecutionScheduled(ctx, grandchild))
+
+	// Sub-executions have their own case executions
+	caseExec, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(grandchild.ID))
+	require.NoError(t, err)
+	_, err = fakes.repo.UpdateStartedCaseExecution(ctx, fake.GenStartedCaseExec(grandchild.ID, caseExec.ID))
+	require.NoError(t, err)
+	failure := &test.Failure{Message: "bang", Type: "AssertionError"}
+	_, err = fakes.repo.UpdateFinishedCaseExecution(ctx, &test.FinishedCaseExecution{
+		ID:              caseExec.ID,
+		TestExecutionID: grandchild.ID,
+		FinishTime:      time.Now().UTC(),
+		Error:           &failure.Message,
+		Failure:         failure,
+	})
+	require.NoError(t, err)
+
+	got, err := s.GetTestExecutionTree(ctx, &GetTestExecutionTreeRequest{TestExecutionID: te.ID})
+	require.NoError(t, err)
+	assert.Equal(t, te.ID, got.TestExecution.ID)
+	assert.Equal(t, test.ExecutionStatusScheduled, got.TestExecution.Status)
+	assert.Empty(t, got.CaseExecutions)
+	require.Len(t, got.SubExecutions, 1)
+
+	gotChild := got.SubExecutions[0]
+	assert.Equal(t, child.ID, gotChild.TestExecution.ID)
+	assert.Equal(t, child.ChildWorkflowType, gotChild.TestExecution.ChildWorkflowType)
+	require.Len(t, gotChild.SubExecutions, 1)
+
+	gotGrandchild := gotChild.SubExecutions[0]
+	assert.Equal(t, grandchild.ID, gotGrandchild.TestExecution.ID)
+	require.Len(t, gotGrandchild.CaseExecutions, 1)
+	assert.Equal(t, caseExec.ID, gotGrandchild.CaseExecutions[0].ID)
+	// Statuses and failures the tests API omits are returned with the tree
+	assert.Equal(t, test.ExecutionStatusFailed, gotGrandchild.CaseExecutions[0].Status)
+	assert.Equal(t, failure, gotGrandchild.CaseExecutions[0].Failure)
+	assert.Empty(t, gotGrandchild.SubExecutions)
+
+	// Sub-executions are retried and cancelled with their parent
+	_, err = s.CancelTestExecution(ctx, &CancelTestExecutionRequest{TestExecutionID: childID})
+	require.ErrorIs(t, err, test.ErrorSubTestExecution)
+	_, err = s.RerunTestExecution(ctx, &RerunTestExecutionRequest{TestExecutionID: childID})
+	require.ErrorIs(t, err, test.ErrorSubTestExecution)
+}
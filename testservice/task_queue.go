@@ -0,0 +1,166 @@
+package testservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/annexsh/annex/test"
+)
+
+const (
+	taskQueueSeparator = "-"
+	maxIdentifierLen   = 200
+)
+
+// taskQueueEscaper percent-encodes the separator in context and group names so
+// that it only appears between them. Names without '%' or '-' are unchanged.
+var taskQueueEscaper = strings.NewReplacer("%", "%25", taskQueueSeparator, "%2D")
+
+// getTaskQueue gets the task queue of a new group. Task queues are unique to
+// each context and group pair. Groups keep the task queue they were created
+// with, so groups created before task queues were escaped keep their legacy
+// task queue and the workflows already started on it.
+func getTaskQueue(contextID string, groupID string) string {
+	return taskQueueEscaper.Replace(contextID) + taskQueueSeparator + taskQueueEscaper.Replace(groupID)
+}
+
+// getLegacyTaskQueue gets the task queue runners derive for a group. Legacy
+// task queues are ambiguous when the context or group contains the separator,
+// e.g. context "a-b" with group "c" and context "a" with group "b-c".
+func getLegacyTaskQueue(contextID string, groupID string) string {
+	return contextID + taskQueueSeparator + groupID
+}
+
+// ResolveTaskQueue resolves the legacy task queue polled by a runner to the
+// task queue of its group. Task queues that are not the legacy task queue of
+// any group are returned unchanged. It returns ErrorTaskQueueAmbiguous if the
+// legacy task queue is shared by several groups, even if they share a task
+// queue, since the runner could otherwise receive the tests of another group.
+// Groups created before task queues were escaped keep their legacy task queue
+// and are resolved to it.
+func (s *Service) ResolveTaskQueue(ctx context.Context, taskQueue string) (string, error) {
+	groups, err := s.findLegacyTaskQueueGroups(ctx, taskQueue)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(groups) {
+	case 0:
+		return taskQueue, nil
+	case 1:
+		return groups[0].taskQueue, nil
+	default:
+		return "", fmt.Errorf("task queue '%s': %w", taskQueue, test.ErrorTaskQueueAmbiguous)
+	}
+}
+
+type taskQueueGroup struct {
+	contextID string
+	groupID   string
+	taskQueue string
+}
+
+// findLegacyTaskQueueGroups finds the groups with the legacy task queue by
+// splitting it at each separator.
+func (s *Service) findLegacyTaskQueueGroups(ctx context.Context, taskQueue string) ([]taskQueueGroup, error) {
+	var groups []taskQueueGroup
+
+	for i := 0; i < len(taskQueue); i++ {
+		if !strings.HasPrefix(taskQueue[i:], taskQueueSeparator) {
+			continue
+		}
+		contextID := taskQueue[:i]
+		groupID := taskQueue[i+len(taskQueueSeparator):]
+		if contextID == "" || groupID == "" {
+			continue
+		}
+		groupTaskQueue, err := s.repo.GetGroupTaskQueue(ctx, contextID, groupID)
+		if err != nil {
+			if errors.Is(err, test.ErrorGroupNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		groups = append(groups, taskQueueGroup{contextID: contextID, groupID: groupID, taskQueue: groupTaskQueue})
+	}
+
+	return groups, nil
+}
+
+// validateNewGroup validates the names of a group before it is registered.
+// Groups that already exist are not validated so that groups registered before
+// validation remain usable.
+func (s *Service) validateNewGroup(ctx context.Context, contextID string, groupID string) error {
+	exists, err := s.repo.GroupExists(ctx, contextID, groupID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	if err = validateIdentifier("context", contextID); err != nil {
+		return err
+	}
+	if err = validateIdentifier("group", groupID); err != nil {
+		return err
+	}
+
+	// Runners of the group would share their legacy task queue with another group
+	groups, err := s.findLegacyTaskQueueGroups(ctx, getLegacyTaskQueue(contextID, groupID))
+	if err != nil {
+		return err
+	}
+	if len(groups) > 0 {
+		return fmt.Errorf("group '%s' of context '%s' and group '%s' of context '%s': %w",
+			groupID, contextID, groups[0].groupID, groups[0].contextID, test.ErrorTaskQueueConflict)
+	}
+
+	// Renamed groups keep the task queue of their previous name
+	taskQueue := getTaskQueue(contextID, groupID)
+	exists, err = s.repo.TaskQueueExists(ctx, taskQueue)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("group '%s' of context '%s' and task queue '%s': %w",
+			groupID, contextID, taskQueue, test.ErrorTaskQueueConflict)
+	}
+
+	return nil
+}
+
+// validateIdentifier validates the name of a context or group. Names must be
+// printable and cannot contain '%' so that they are never confused with the
+// escaped names of task queues.
+func validateIdentifier(kind string, id string) error {
+	if err := checkIdentifier(id); err != nil {
+		return fmt.Errorf("invalid %s '%s': %w", kind, id, err)
+	}
+	return nil
+}
+
+func checkIdentifier(id string) error {
+	if id == "" {
+		return errors.New("name cannot be empty")
+	}
+	if len(id) > maxIdentifierLen {
+		return fmt.Errorf("name cannot be longer than %d bytes", maxIdentifierLen)
+	}
+	if !utf8.ValidString(id) {
+		return errors.New("name must be valid UTF-8")
+	}
+	for _, r := range id {
+		if r == '%' {
+			return errors.New("name cannot contain '%'")
+		}
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return errors.New("name cannot contain whitespace or control characters")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package testservice
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/test"
+)
+
+func TestGetTaskQueue(t *testing.T) {
+	tests := []struct {
+		name      string
+		contextID string
+		groupID   string
+		want      string
+	}{
+		{
+			name:      "unchanged legacy task queue",
+			contextID: "default",
+			groupID:   "payments",
+			want:      "default-payments",
+		},
+		{
+			name:      "separator in context",
+			contextID: "a-b",
+			groupID:   "c",
+			want:      "a%2Db-c",
+		},
+		{
+			name:      "separator in group",
+			contextID: "a",
+			groupID:   "b-c",
+			want:      "a-b%2Dc",
+		},
+		{
+			name:      "escape character",
+			contextID: "a%2Db",
+			groupID:   "c",
+			want:      "a%252Db-c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getTaskQueue(tt.contextID, tt.groupID))
+		})
+	}
+}
+
+func TestCheckIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			id:   "payments-v2.eu_west",
+		},
+		{
+			name: "valid unicode",
+			id:   "zahlungen-ü",
+		},
+		{
+			name:    "empty",
+			id:      "",
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			id:      strings.Repeat("a", maxIdentifierLen+1),
+			wantErr: true,
+		},
+		{
+			name:    "invalid utf-8",
+			id:      "a\xffb",
+			wantErr: true,
+		},
+		{
+			name:    "escape character",
+			id:      "a%2Db",
+			wantErr: true,
+		},
+		{
+			name:    "whitespace",
+			id:      "a b",
+			wantErr: true,
+		},
+		{
+			name:    "control character",
+			id:      "a\nb",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkIdentifier(tt.id)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_ResolveTaskQueue(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "default", "payments", getTaskQueue("default", "payments")))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "a", "b-c", getTaskQueue("a", "b-c")))
+	// Groups registered before validation can share a legacy task queue
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "x-y", "z", getTaskQueue("x-y", "z")))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "x", "y-z", getTaskQueue("x", "y-z")))
+	// Groups created before task queue escaping keep their legacy task queue
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "old", "my-tests", getLegacyTaskQueue("old", "my-tests")))
+	// Groups created before validation could share their legacy task queue
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "p-q", "r", getLegacyTaskQueue("p-q", "r")))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "p", "q-r", getLegacyTaskQueue("p", "q-r")))
+
+	tests := []struct {
+		name      string
+		taskQueue string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "unknown task queue",
+			taskQueue: "sticky-runner-123",
+			want:      "sticky-runner-123",
+		},
+		{
+			name:      "unchanged task queue",
+			taskQueue: "default-payments",
+			want:      "default-payments",
+		},
+		{
+			name:      "legacy task queue",
+			taskQueue: "a-b-c",
+			want:      "a-b%2Dc",
+		},
+		{
+			name:      "pinned legacy task queue",
+			taskQueue: "old-my-tests",
+			want:      "old-my-tests",
+		},
+		{
+			name:      "shared legacy task queue",
+			taskQueue: "p-q-r",
+			wantErr:   test.ErrorTaskQueueAmbiguous,
+		},
+		{
+			name:      "ambiguous legacy task queue",
+			taskQueue: "x-y-z",
+			wantErr:   test.ErrorTaskQueueAmbiguous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ResolveTaskQueue(ctx, tt.taskQueue)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_ExecuteTest_legacyTaskQueue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+	def.ContextID = "old"
+	def.GroupID = "my-tests"
+
+	// Simulate a group created before task queue escaping, with an execution
+	// in flight on its legacy task queue
+	legacyTaskQueue := getLegacyTaskQueue(def.ContextID, def.GroupID)
+	require.NoError(t, fakes.repo.CreateContext(ctx, def.ContextID))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, def.ContextID, def.GroupID, legacyTaskQueue))
+	tt, err := fakes.repo.CreateTest(ctx, def)
+	require.NoError(t, err)
+
+	inFlight, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+	require.NoError(t, err)
+
+	// Runners polling the legacy task queue keep receiving its tasks
+	got, err := s.ResolveTaskQueue(ctx, legacyTaskQueue)
+	require.NoError(t, err)
+	assert.Equal(t, legacyTaskQueue, got)
+
+	res, err := s.ExecuteTest(ctx, connect.NewRequest(&testsv1.ExecuteTestRequest{
+		TestId: tt.ID.String(),
+		Input:  fake.GenInput().Proto(),
+	}))
+	require.NoError(t, err)
+
+	// New executions of the group are started on the same legacy task queue
+	execID, err := test.ParseTestExecutionID(res.Msg.TestExecution.Id)
+	require.NoError(t, err)
+	assert.NotEqual(t, inFlight.ID, execID)
+	wr := fakes.workflower.GetWorkflow(ctx, execID.WorkflowID(), "")
+	assert.Equal(t, legacyTaskQueue, wr.(*fake.WorkflowRun).Options().TaskQueue)
+}
+
+func TestService_RegisterGroup_taskQueue(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	register := func(contextID string, groupID string) error {
+		_, err := s.RegisterGroup(ctx, connect.NewRequest(&testsv1.RegisterGroupRequest{
+			Context: contextID,
+			Name:    groupID,
+		}))
+		return err
+	}
+
+	require.NoError(t, register("a-b", "c"))
+	// Re-registering an existing group is allowed
+	require.NoError(t, register("a-b", "c"))
+
+	err := register("a", "b-c")
+	require.ErrorIs(t, err, test.ErrorTaskQueueConflict)
+
+	require.Error(t, register("a", "b c"))
+	require.Error(t, register("a%", "b"))
+
+	// Existing groups with invalid names remain usable
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "legacy", "b c", getTaskQueue("legacy", "b c")))
+	require.NoError(t, register("legacy", "b c"))
+
+	_, err = s.RegisterTestsWithOptions(ctx, &RegisterTestsRequest{
+		Context: "a",
+		Group:   "b-c",
+	})
+	require.ErrorIs(t, err, test.ErrorTaskQueueConflict)
+}
+
+func TestService_ResolveTaskQueue_postgres(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newPostgresService(t)
+
+	contextID := uniqueName()
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, contextID, "payments", getTaskQueue(contextID, "payments")))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, contextID, "my-tests", getTaskQueue(contextID, "my-tests")))
+
+	got, err := s.ResolveTaskQueue(ctx, contextID+"-payments")
+	require.NoError(t, err)
+	assert.Equal(t, contextID+"-payments", got)
+
+	got, err = s.ResolveTaskQueue(ctx, contextID+"-my-tests")
+	require.NoError(t, err)
+	assert.Equal(t, contextID+"-my%2Dtests", got)
+
+	got, err = s.ResolveTaskQueue(ctx, "sticky-runner-123")
+	require.NoError(t, err)
+	assert.Equal(t, "sticky-runner-123", got)
+
+	// Groups created before validation could share their legacy task queue
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID+"-a"))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, contextID+"-a", "b", getLegacyTaskQueue(contextID+"-a", "b")))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, contextID, "a-b", getLegacyTaskQueue(contextID, "a-b")))
+	_, err = s.ResolveTaskQueue(ctx, contextID+"-a-b")
+	require.ErrorIs(t, err, test.ErrorTaskQueueAmbiguous)
+}
+
+func TestService_RegisterGroup_taskQueue_postgres(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newPostgresService(t)
+
+	register := func(contextID string, groupID string) error {
+		_, err := s.RegisterGroup(ctx, connect.NewRequest(&testsv1.RegisterGroupRequest{
+			Context: contextID,
+			Name:    groupID,
+		}))
+		return err
+	}
+
+	contextID := uniqueName()
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID))
+	require.NoError(t, fakes.repo.CreateContext(ctx, contextID+"-a"))
+
+	require.NoError(t, register(contextID+"-a", "b"))
+	require.ErrorIs(t, register(contextID, "a-b"), test.ErrorTaskQueueConflict)
+	require.Error(t, register(contextID, "b c"))
+}
@@ -3,11 +3,14 @@ package testservice
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"connectrpc.com/connect"
 	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
 	"github.com/google/uuid"
 
+	"github.com/annexsh/annex/internal/pagination"
 	"github.com/annexsh/annex/test"
 )
 
@@ -15,38 +18,198 @@ func (s *Service) RegisterTests(
 	ctx context.Context,
 	req *connect.Request[testsv1.RegisterTestsRequest],
 ) (*connect.Response[testsv1.RegisterTestsResponse], error) {
-	if err := s.repo.CreateGroup(ctx, req.Msg.Context, req.Msg.Group); err != nil {
+	res, err := s.RegisterTestsWithOptions(ctx, &RegisterTestsRequest{
+		Context:     req.Msg.Context,
+		Group:       req.Msg.Group,
+		Definitions: req.Msg.Definitions,
+	})
+	if err != nil {
 		return nil, err
 	}
+	return connect.NewResponse(&testsv1.RegisterTestsResponse{
+		Tests: res.Tests.Proto(),
+	}), nil
+}
+
+// RegisterTestsRequest describes registered tests beyond what the tests API
+// defines.
+type RegisterTestsRequest struct {
+	Context           string
+	Group             string
+	Definitions       []*testsv1.TestDefinition
+	InputSchemas      map[string][]byte            // optional: JSON Schemas of test inputs by test definition name
+	Metadata          map[string]test.TestMetadata // optional: test metadata by test definition name; tests without metadata keep their previous metadata
+	ExecutionTimeouts map[string]time.Duration     // optional: execution timeouts by test definition name; tests without a timeout keep their previous timeout
+	RunTimeouts       map[string]time.Duration     // optional: run timeouts by test definition name; tests without a timeout keep their previous timeout
+}
+
+// RegisterTestsResult is the diff of a group registration against the tests
+// previously registered in the group.
+type RegisterTestsResult struct {
+	Tests      test.TestList // all registered tests in definition order
+	Added      test.TestList // tests new to the group
+	Updated    test.TestList // existing tests with a changed definition or metadata, including restored deprecated tests
+	Deprecated test.TestList // tests missing from the registration
+}
 
+// RegisterTestsWithOptions registers tests like RegisterTests. Tests with an
+// input schema have their default input validated against the schema before
+// anything is registered. Tests registered again without an input schema,
+// timeouts or metadata keep their previous ones.
+//
+// A registration describes every test of a group: tests of the group missing
+// from it are deprecated. Deprecated tests keep their history but are hidden
+// from ListTests and cannot be executed until they are registered again.
+func (s *Service) RegisterTestsWithOptions(ctx context.Context, req *RegisterTestsRequest) (*RegisterTestsResult, error) {
 	var defs []*test.TestDefinition
 
-	for _, defpb := range req.Msg.Definitions {
+	for _, defpb := range req.Definitions {
 		def := &test.TestDefinition{
-			ContextID:    req.Msg.Context,
-			GroupID:      req.Msg.Group,
+			ContextID:    req.Context,
+			GroupID:      req.Group,
 			TestID:       uuid.New(),
 			Name:         defpb.Name,
 			DefaultInput: nil,
+			InputSchema:  req.InputSchemas[defpb.Name],
+		}
+
+		if metadata, ok := req.Metadata[defpb.Name]; ok {
+			def.Metadata = &metadata
+		}
+
+		if timeout, ok := req.ExecutionTimeouts[defpb.Name]; ok {
+			if timeout <= 0 {
+				return nil, fmt.Errorf("test '%s': execution timeout must be positive", defpb.Name)
+			}
+			def.ExecutionTimeout = &timeout
+		}
+		if timeout, ok := req.RunTimeouts[defpb.Name]; ok {
+			if timeout <= 0 {
+				return nil, fmt.Errorf("test '%s': run timeout must be positive", defpb.Name)
+			}
+			def.RunTimeout = &timeout
 		}
 
 		if defpb.DefaultInput != nil {
 			def.DefaultInput = &test.Payload{
-				Data: defpb.DefaultInput.Data,
+				Metadata: defpb.DefaultInput.Metadata,
+				Data:     defpb.DefaultInput.Data,
 			}
 		}
 
 		defs = append(defs, def)
 	}
 
+	existing, err := s.repo.ListTests(ctx, req.Context, req.Group, &test.TestListFilter{IncludeDeprecated: true})
+	if err != nil {
+		return nil, err
+	}
+
+	existingByName := make(map[string]*test.Test, len(existing))
+	for _, t := range existing {
+		existingByName[t.Name] = t
+	}
+
+	// Tests registered without a schema keep their previous one, which the
+	// default input must still satisfy. Schemas are compiled even without a
+	// default input to reject invalid schemas.
+	for i, def := range defs {
+		schema := def.InputSchema
+		if prev, ok := existingByName[def.Name]; ok && schema == nil {
+			schema = prev.InputSchema
+		}
+		if schema == nil {
+			continue
+		}
+		if def.DefaultInput == nil {
+			if _, err = compileInputSchema(schema); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("test '%s': %w", def.Name, err))
+			}
+		} else if err = validateInput(schema, req.Definitions[i].DefaultInput); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("test '%s': %w", def.Name, err))
+		}
+	}
+
+	if err = s.validateNewGroup(ctx, req.Context, req.Group); err != nil {
+		return nil, err
+	}
+	if err = s.repo.CreateGroup(ctx, req.Context, req.Group, getTaskQueue(req.Context, req.Group)); err != nil {
+		return nil, err
+	}
+
 	created, err := s.repo.CreateTests(ctx, defs...)
 	if err != nil {
 		return nil, err
 	}
 
-	return connect.NewResponse(&testsv1.RegisterTestsResponse{
-		Tests: created.Proto(),
-	}), nil
+	res := &RegisterTestsResult{
+		Tests: created,
+	}
+
+	names := make([]string, len(created))
+	for i, t := range created {
+		names[i] = t.Name
+		if prev, ok := existingByName[t.Name]; !ok {
+			res.Added = append(res.Added, t)
+		} else if prev.Deprecated || prev.Version != t.Version || !equalMetadata(prev.Metadata, t.Metadata) {
+			res.Updated = append(res.Updated, t)
+		}
+	}
+
+	if res.Deprecated, err = s.repo.DeprecateTests(ctx, req.Context, req.Group, names); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+type GetTestInputSchemaRequest struct {
+	TestID uuid.UUID
+}
+
+// GetTestInputSchema gets the JSON Schema of the input of a test so that
+// clients can render input forms. It returns nil when the test has no schema.
+func (s *Service) GetTestInputSchema(ctx context.Context, req *GetTestInputSchemaRequest) ([]byte, error) {
+	t, err := s.repo.GetTest(ctx, req.TestID)
+	if err != nil {
+		return nil, err
+	}
+	return t.InputSchema, nil
+}
+
+type MoveTestRequest struct {
+	TestID uuid.UUID
+	Group  string
+}
+
+// MoveTest moves a test to another group of its context. The test keeps its ID
+// and execution history.
+//
+// Executions of the moved test are dispatched to the task queue of its new
+// group, so runners of the new group must register the test. Executions
+// dispatched to the old task queue must have finished before the move, and
+// cannot be retried after it.
+func (s *Service) MoveTest(ctx context.Context, req *MoveTestRequest) (*test.Test, error) {
+	testID := req.TestID
+	groupID := req.Group
+
+	t, err := s.repo.GetTest(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	if t.GroupID == groupID {
+		return t, nil
+	}
+
+	exists, err := s.repo.GroupExists(ctx, t.ContextID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, test.ErrorGroupNotFound
+	}
+
+	return s.repo.MoveTest(ctx, testID, groupID)
 }
 
 func (s *Service) GetTestDefaultInput(
@@ -68,11 +231,21 @@ func (s *Service) GetTestDefaultInput(
 	}), nil
 }
 
+type GetTestDefaultInputPayloadRequest struct {
+	TestID uuid.UUID
+}
+
+// GetTestDefaultInputPayload gets the default input of a test with the
+// metadata needed to decode it. GetTestDefaultInput only returns the data.
+func (s *Service) GetTestDefaultInputPayload(ctx context.Context, req *GetTestDefaultInputPayloadRequest) (*test.Payload, error) {
+	return s.repo.GetTestDefaultInput(ctx, req.TestID)
+}
+
 func (s *Service) ListTests(
 	ctx context.Context,
 	req *connect.Request[testsv1.ListTestsRequest],
 ) (*connect.Response[testsv1.ListTestsResponse], error) {
-	tests, err := s.repo.ListTests(ctx, req.Msg.Context, req.Msg.Group)
+	tests, err := s.repo.ListTests(ctx, req.Msg.Context, req.Msg.Group, &test.TestListFilter{})
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +255,97 @@ func (s *Service) ListTests(
 	}), nil
 }
 
+type ListTestsRequest struct {
+	Context           string
+	Group             string
+	IncludeDeprecated bool
+	Tags              []string // optional: only list tests with all tags
+	Owner             string   // optional: only list tests with the owner
+}
+
+// ListTestsWithOptions lists tests like ListTests with their deprecation
+// status and metadata, optionally including deprecated tests.
+func (s *Service) ListTestsWithOptions(ctx context.Context, req *ListTestsRequest) (test.TestList, error) {
+	return s.repo.ListTests(ctx, req.Context, req.Group, &test.TestListFilter{
+		IncludeDeprecated: req.IncludeDeprecated,
+		Tags:              req.Tags,
+		Owner:             req.Owner,
+	})
+}
+
+type SearchTestsRequest struct {
+	NameContains      *string  // optional: case-insensitive substring of the test name
+	Tags              []string // optional: only find tests with all tags
+	Owner             string   // optional: only find tests with the owner
+	IncludeDeprecated bool
+	PageSize          int32
+	NextPageToken     string
+}
+
+func (r *SearchTestsRequest) GetNextPageToken() string {
+	return r.NextPageToken
+}
+
+type SearchTestsResponse struct {
+	Tests         test.TestList
+	NextPageToken string
+}
+
+// SearchTests finds tests across all contexts and groups that are not
+// archived, most recently registered first. Found tests include their context
+// and group.
+func (s *Service) SearchTests(ctx context.Context, req *SearchTestsRequest) (*SearchTestsResponse, error) {
+	queryPageSize := defaultPageSize + 1
+
+	if req.PageSize > 0 {
+		queryPageSize = req.PageSize + 1
+	}
+
+	filter := &test.TestSearchFilter{
+		NameContains:      req.NameContains,
+		Tags:              req.Tags,
+		Owner:             req.Owner,
+		IncludeDeprecated: req.IncludeDeprecated,
+		PageSize:          uint32(queryPageSize),
+	}
+
+	if req.NextPageToken != "" {
+		lastTimestamp, lastID, err := pagination.DecodeNextPageToken(req)
+		if err != nil {
+			return nil, err
+		}
+		filter.LastCreateTime = &lastTimestamp
+		filter.LastTestID = &lastID
+	}
+
+	tests, err := s.repo.SearchTests(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SearchTestsResponse{}
+
+	hasNextPage := len(tests) == int(queryPageSize)
+	if hasNextPage {
+		tests = tests[:len(tests)-1] // remove page buffer item
+		last := tests[len(tests)-1]
+		res.NextPageToken, err = pagination.EncodeNextPageToken(last.CreateTime, last.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res.Tests = tests
+	return res, nil
+}
+
+func equalMetadata(a, b test.TestMetadata) bool {
+	return a.Description == b.Description &&
+		a.Owner == b.Owner &&
+		a.SourceLocation == b.SourceLocation &&
+		slices.Equal(a.Tags, b.Tags)
+}
+
 func (s *Service) ExecuteTest(
 	ctx context.Context,
 	req *connect.Request[testsv1.ExecuteTestRequest],
@@ -91,14 +355,12 @@ func (s *Service) ExecuteTest(
 		return nil, err
 	}
 
-	var opts []executeOption
-	if req.Msg.Input != nil {
-		opts = append(opts, withInput(req.Msg.Input))
-	}
-
-	testExec, err := s.executor.execute(ctx, testID, opts...)
+	testExec, err := s.ExecuteTestWithOptions(ctx, &ExecuteTestRequest{
+		TestID: testID,
+		Input:  req.Msg.Input,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute test: %w", err)
+		return nil, err
 	}
 
 	return connect.NewResponse(&testsv1.ExecuteTestResponse{
@@ -106,6 +368,30 @@ func (s *Service) ExecuteTest(
 	}), nil
 }
 
-func getTaskQueue(context string, groupName string) string {
-	return fmt.Sprintf("%s-%s", context, groupName)
+type ExecuteTestRequest struct {
+	TestID           uuid.UUID
+	Input            *testsv1.Payload // optional: the test is executed without input when nil
+	ExecutionTimeout *time.Duration   // optional: overrides the execution timeout of the test
+	RunTimeout       *time.Duration   // optional: overrides the run timeout of the test
+}
+
+// ExecuteTestWithOptions executes a test like ExecuteTest, optionally
+// overriding the timeouts of the test for this execution.
+func (s *Service) ExecuteTestWithOptions(ctx context.Context, req *ExecuteTestRequest) (*test.TestExecution, error) {
+	var execOpts []executeOption
+	if req.Input != nil {
+		execOpts = append(execOpts, withInput(req.Input))
+	}
+	if req.ExecutionTimeout != nil {
+		execOpts = append(execOpts, withExecutionTimeout(*req.ExecutionTimeout))
+	}
+	if req.RunTimeout != nil {
+		execOpts = append(execOpts, withRunTimeout(*req.RunTimeout))
+	}
+
+	testExec, err := s.executor.execute(ctx, req.TestID, execOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute test: %w", err)
+	}
+	return testExec, nil
 }
@@ -3,6 +3,7 @@ package testservice
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"connectrpc.com/connect"
 	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
@@ -45,23 +46,150 @@ func (s *Service) ListTestExecutions(
 	ctx context.Context,
 	req *connect.Request[testsv1.ListTestExecutionsRequest],
 ) (*connect.Response[testsv1.ListTestExecutionsResponse], error) {
-	testID, err := uuid.Parse(req.Msg.TestId)
+	res, err := s.listTestExecutions(ctx, req.Msg, &test.TestExecutionListFilter{})
 	if err != nil {
 		return nil, err
 	}
+	return connect.NewResponse(res), nil
+}
+
+type FilterTestExecutionsRequest struct {
+	TestID          uuid.UUID
+	Statuses        []test.ExecutionStatus // optional: matches any of the statuses
+	ScheduledAfter  *time.Time             // optional: inclusive lower bound on schedule time
+	ScheduledBefore *time.Time             // optional: exclusive upper bound on schedule time
+	ErrorContains   *string                // optional: case-insensitive substring of the error
+	PageSize        int32
+	NextPageToken   string
+}
+
+func (r *FilterTestExecutionsRequest) GetNextPageToken() string {
+	return r.NextPageToken
+}
+
+type FilterTestExecutionsResponse struct {
+	TestExecutions test.TestExecutionList
+	NextPageToken  string
+}
+
+// FilterTestExecutions lists the executions of a test that match the status,
+// schedule time and error filters. The same filters must be provided for every
+// page.
+func (s *Service) FilterTestExecutions(ctx context.Context, req *FilterTestExecutionsRequest) (*FilterTestExecutionsResponse, error) {
+	testExecs, nextPageToken, err := s.listTestExecutionsPage(ctx, req.TestID, req.PageSize, req, &test.TestExecutionListFilter{
+		Statuses:        req.Statuses,
+		ScheduledAfter:  req.ScheduledAfter,
+		ScheduledBefore: req.ScheduledBefore,
+		ErrorContains:   req.ErrorContains,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &FilterTestExecutionsResponse{
+		TestExecutions: testExecs,
+		NextPageToken:  nextPageToken,
+	}, nil
+}
 
+func (s *Service) listTestExecutions(
+	ctx context.Context,
+	req *testsv1.ListTestExecutionsRequest,
+	filter *test.TestExecutionListFilter,
+) (*testsv1.ListTestExecutionsResponse, error) {
+	testID, err := uuid.Parse(req.TestId)
+	if err != nil {
+		return nil, err
+	}
+
+	testExecs, nextPageToken, err := s.listTestExecutionsPage(ctx, testID, req.PageSize, req, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &testsv1.ListTestExecutionsResponse{
+		TestExecutions: testExecs.Proto(),
+		NextPageToken:  nextPageToken,
+	}, nil
+}
+
+func (s *Service) listTestExecutionsPage(
+	ctx context.Context,
+	testID uuid.UUID,
+	pageSize int32,
+	page pagination.PaginatedRequest,
+	filter *test.TestExecutionListFilter,
+) (test.TestExecutionList, string, error) {
 	queryPageSize := defaultPageSize + 1
 
-	if req.Msg.PageSize > 0 {
-		queryPageSize = req.Msg.PageSize + 1
+	if pageSize > 0 {
+		queryPageSize = pageSize + 1
+	}
+
+	filter.PageSize = uint32(queryPageSize)
+	filter.LastScheduleTime = nil
+	filter.LastTestExecutionID = nil
+
+	if page.GetNextPageToken() != "" {
+		lastTimestamp, lastID, err := pagination.DecodeNextPageToken(page)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.LastScheduleTime = &lastTimestamp
+		filter.LastTestExecutionID = &lastID
+	}
+
+	testExecs, err := s.repo.ListTestExecutions(ctx, testID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextPageToken string
+
+	hasNextPage := len(testExecs) == int(queryPageSize)
+	if hasNextPage {
+		testExecs = testExecs[:len(testExecs)-1] // remove page buffer item
+		lastExec := testExecs[len(testExecs)-1]
+		nextPageToken, err = pagination.EncodeNextPageToken(lastExec.ScheduleTime, lastExec.ID.UUID)
+		if err != nil {
+			return nil, "", err
+		}
 	}
 
-	filter := &test.TestExecutionListFilter{
+	return testExecs, nextPageToken, nil
+}
+
+type ListRecentTestExecutionsRequest struct {
+	Context       string
+	Group         *string // optional: all groups in the context when nil
+	PageSize      int32
+	NextPageToken string
+}
+
+func (r *ListRecentTestExecutionsRequest) GetNextPageToken() string {
+	return r.NextPageToken
+}
+
+type ListRecentTestExecutionsResponse struct {
+	TestExecutions test.TestExecutionSummaryList
+	NextPageToken  string
+}
+
+// ListRecentTestExecutions lists the executions of all tests in a context, or
+// in a single group of the context, most recently scheduled first.
+func (s *Service) ListRecentTestExecutions(ctx context.Context, req *ListRecentTestExecutionsRequest) (*ListRecentTestExecutionsResponse, error) {
+	queryPageSize := defaultPageSize + 1
+
+	if req.PageSize > 0 {
+		queryPageSize = req.PageSize + 1
+	}
+
+	filter := &test.RecentTestExecutionListFilter{
+		GroupID:  req.Group,
 		PageSize: uint32(queryPageSize),
 	}
 
-	if req.Msg.NextPageToken != "" {
-		lastTimestamp, lastID, err := pagination.DecodeNextPageToken(req.Msg)
+	if req.NextPageToken != "" {
+		lastTimestamp, lastID, err := pagination.DecodeNextPageToken(req)
 		if err != nil {
 			return nil, err
 		}
@@ -69,27 +197,25 @@ func (s *Service) ListTestExecutions(
 		filter.LastTestExecutionID = &lastID
 	}
 
-	var testExecs test.TestExecutionList
-
-	testExecs, err = s.repo.ListTestExecutions(ctx, testID, filter)
+	summaries, err := s.repo.ListRecentTestExecutions(ctx, req.Context, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &testsv1.ListTestExecutionsResponse{}
+	res := &ListRecentTestExecutionsResponse{}
 
-	hasNextPage := len(testExecs) == int(queryPageSize)
+	hasNextPage := len(summaries) == int(queryPageSize)
 	if hasNextPage {
-		testExecs = testExecs[:len(testExecs)-1] // remove page buffer item
-		lastExec := testExecs[len(testExecs)-1]
+		summaries = summaries[:len(summaries)-1] // remove page buffer item
+		lastExec := summaries[len(summaries)-1].Execution
 		res.NextPageToken, err = pagination.EncodeNextPageToken(lastExec.ScheduleTime, lastExec.ID.UUID)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	res.TestExecutions = testExecs.Proto()
-	return connect.NewResponse(res), nil
+	res.TestExecutions = summaries
+	return res, nil
 }
 
 func (s *Service) AckTestExecutionStarted(
@@ -122,19 +248,26 @@ func (s *Service) AckTestExecutionFinished(
 		return nil, err
 	}
 
-	finished := &test.FinishedTestExecution{
+	if err = s.RecordTestExecutionFinished(ctx, &test.FinishedTestExecution{
 		ID:         execID,
 		FinishTime: req.Msg.FinishTime.AsTime(),
 		Error:      req.Msg.Error,
-	}
-
-	if _, err = s.repo.UpdateFinishedTestExecution(ctx, finished); err != nil {
-		return nil, fmt.Errorf("failed to update test execution: %w", err)
+	}); err != nil {
+		return nil, err
 	}
 
 	return connect.NewResponse(&testsv1.AckTestExecutionFinishedResponse{}), nil
 }
 
+// RecordTestExecutionFinished records the end of a test execution with the
+// structured failure the tests API cannot carry.
+func (s *Service) RecordTestExecutionFinished(ctx context.Context, finished *test.FinishedTestExecution) error {
+	if _, err := s.repo.UpdateFinishedTestExecution(ctx, finished); err != nil {
+		return fmt.Errorf("failed to update test execution: %w", err)
+	}
+	return nil
+}
+
 func (s *Service) RetryTestExecution(
 	ctx context.Context,
 	req *connect.Request[testsv1.RetryTestExecutionRequest],
@@ -144,7 +277,9 @@ func (s *Service) RetryTestExecution(
 		return nil, err
 	}
 
-	testExec, err := s.executor.retry(ctx, testExecID)
+	testExec, err := s.RetryTestExecutionWithOptions(ctx, &RetryTestExecutionRequest{
+		TestExecutionID: testExecID,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -153,3 +288,102 @@ func (s *Service) RetryTestExecution(
 		TestExecution: testExec.Proto(),
 	}), nil
 }
+
+// RetryTestExecutionRequest selects the point a test execution is retried
+// from. A test execution is retried from its first failure by default.
+type RetryTestExecutionRequest struct {
+	TestExecutionID   test.TestExecutionID
+	FromCaseExecution *test.CaseExecutionID // optional: reruns the case and all later cases
+	FromStart         bool                  // reruns all cases
+}
+
+// RetryTestExecutionWithOptions retries a test execution like
+// RetryTestExecution, optionally from a chosen case or from the start. It
+// returns ErrorTestMoved if the test moved to another group after the test
+// execution started, since the retry would be dispatched to the task queue of
+// the old group.
+func (s *Service) RetryTestExecutionWithOptions(ctx context.Context, req *RetryTestExecutionRequest) (*test.TestExecution, error) {
+	var retryOpts []retryOption
+	if req.FromCaseExecution != nil {
+		retryOpts = append(retryOpts, retryFromCase(*req.FromCaseExecution))
+	}
+	if req.FromStart {
+		retryOpts = append(retryOpts, retryFromStart())
+	}
+
+	return s.executor.retry(ctx, req.TestExecutionID, retryOpts...)
+}
+
+type RerunTestExecutionRequest struct {
+	TestExecutionID test.TestExecutionID
+	Input           *testsv1.Payload // optional: the input of the source execution is reused when nil
+}
+
+// RerunTestExecution executes the test of an existing test execution as a new
+// test execution linked to the source execution. Unlike a retry, the source
+// execution is left untouched. The rerun uses the timeouts of the source
+// execution but is not added to its test run.
+func (s *Service) RerunTestExecution(ctx context.Context, req *RerunTestExecutionRequest) (*test.TestExecution, error) {
+	src, err := s.repo.GetTestExecution(ctx, req.TestExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	// Child workflows are only executed by their parent
+	if src.ParentID != nil {
+		return nil, test.ErrorSubTestExecution
+	}
+
+	opts := []executeOption{withRerunOf(src.ID)}
+
+	if req.Input != nil {
+		opts = append(opts, withInput(req.Input))
+	} else if src.HasInput {
+		input, err := s.repo.GetTestExecutionInput(ctx, src.ID)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, withInput(input.Proto()))
+	}
+
+	if src.ExecutionTimeout != nil {
+		opts = append(opts, withExecutionTimeout(*src.ExecutionTimeout))
+	}
+	if src.RunTimeout != nil {
+		opts = append(opts, withRunTimeout(*src.RunTimeout))
+	}
+
+	testExec, err := s.executor.execute(ctx, src.TestID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rerun test execution: %w", err)
+	}
+	return testExec, nil
+}
+
+type CancelTestExecutionRequest struct {
+	TestExecutionID test.TestExecutionID
+}
+
+// CancelTestExecution requests cancellation of the test execution workflow and
+// marks the test execution and its unfinished case executions as cancelled.
+func (s *Service) CancelTestExecution(ctx context.Context, req *CancelTestExecutionRequest) (*test.TestExecution, error) {
+	return s.executor.cancel(ctx, req.TestExecutionID)
+}
+
+// WatchTimeouts periodically records test executions whose workflows have
+// timed out or otherwise closed without being acknowledged. It blocks until
+// the context is done.
+func (s *Service) WatchTimeouts(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.executor.recordTimeouts(ctx); err != nil {
+				s.logger.Error("failed to record timed out test executions", "error", err)
+			}
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/enums/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/annexsh/annex/inmem"
@@ -35,7 +36,7 @@ func TestService_GetTestExecution(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, te.Proto(), res.Msg.TestExecution)
-	assert.Equal(t, scheduled.Payload, res.Msg.Input.Data)
+	assert.Equal(t, scheduled.Input.Proto(), res.Msg.Input)
 }
 
 func TestService_ListTestExecutions(t *testing.T) {
@@ -83,6 +84,118 @@ func TestService_ListTestExecutions(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestService_FilterTestExecutions(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	tt, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition())
+	require.NoError(t, err)
+
+	wantCount := 10
+	want := make(test.TestExecutionList, 0, wantCount)
+
+	for i := range 2 * wantCount {
+		scheduled := fake.GenScheduledTestExec(tt.ID)
+		te, err := fakes.repo.CreateScheduledTestExecution(ctx, scheduled)
+		require.NoError(t, err)
+		if i%2 == 0 {
+			continue
+		}
+		te, err = fakes.repo.UpdateFinishedTestExecution(ctx, fake.GenFinishedTestExec(te.ID, ptr.Get("bang")))
+		require.NoError(t, err)
+		want = append(want, te)
+	}
+
+	pageSize := 5
+	numReqs := wantCount / pageSize
+
+	var got test.TestExecutionList
+
+	var nextPageTkn string
+
+	for i := range numReqs {
+		req := &FilterTestExecutionsRequest{
+			TestID:        tt.ID,
+			Statuses:      []test.ExecutionStatus{test.ExecutionStatusFailed},
+			ErrorContains: ptr.Get("BANG"),
+			PageSize:      int32(pageSize),
+			NextPageToken: nextPageTkn,
+		}
+		res, err := s.FilterTestExecutions(ctx, req)
+		require.NoError(t, err)
+		if i == numReqs-1 {
+			require.Empty(t, res.NextPageToken)
+		} else {
+			require.NotEmpty(t, res.NextPageToken)
+			nextPageTkn = res.NextPageToken
+		}
+		got = append(got, res.TestExecutions...)
+	}
+
+	assert.Len(t, got, wantCount)
+	assert.Equal(t, want, got)
+}
+
+func TestService_ListRecentTestExecutions(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	contextID := "foo"
+	groupID := "bar"
+
+	inGroup, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID)))
+	require.NoError(t, err)
+	otherGroup, err := fakes.repo.CreateTest(ctx, fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID("baz")))
+	require.NoError(t, err)
+
+	wantCount := 20
+	var want test.TestExecutionSummaryList
+
+	for i := range 2 * wantCount {
+		tt := inGroup
+		if i%2 == 0 {
+			tt = otherGroup
+		}
+		te, err := fakes.repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(tt.ID))
+		require.NoError(t, err)
+		if tt == inGroup {
+			want = append(test.TestExecutionSummaryList{{
+				ContextID: contextID,
+				GroupID:   groupID,
+				TestName:  inGroup.Name,
+				Execution: te,
+			}}, want...) // most recent first
+		}
+	}
+
+	pageSize := 5
+	numReqs := wantCount / pageSize
+
+	var got test.TestExecutionSummaryList
+
+	var nextPageTkn string
+
+	for i := range numReqs {
+		res, err := s.ListRecentTestExecutions(ctx, &ListRecentTestExecutionsRequest{
+			Context:       contextID,
+			Group:         &groupID,
+			PageSize:      int32(pageSize),
+			NextPageToken: nextPageTkn,
+		})
+		require.NoError(t, err)
+		if i == numReqs-1 {
+			require.Empty(t, res.NextPageToken)
+		} else {
+			require.NotEmpty(t, res.NextPageToken)
+			nextPageTkn = res.NextPageToken
+		}
+		got = append(got, res.TestExecutions...)
+	}
+
+	assert.Len(t, got, wantCount)
+	assert.Equal(t, want, got)
+}
+
 func TestService_AckTestExecutionStarted(t *testing.T) {
 	ctx := context.Background()
 	s, fakes := newService()
@@ -140,7 +253,7 @@ func TestService_RetryTestExecution(t *testing.T) {
 
 	// Setup test
 	caseErr := "case error: bang"
-	baseTest, err := repo.CreateTest(ctx, fake.GenTestDefinition())
+	baseTest, err := createTest(ctx, repo, fake.GenTestDefinition())
 	require.NoError(t, err)
 
 	// Setup test/case executions
@@ -160,6 +273,7 @@ func TestService_RetryTestExecution(t *testing.T) {
 		fake.WithHistory(
 			fake.GenCaseFailureHistory(
 				testExec.ID,
+				getTaskQueue(baseTest.ContextID, baseTest.GroupID),
 				testExecLog.ID,
 				successCaseExec.ID,
 				failureCaseExec.ID,
@@ -200,4 +314,476 @@ func TestService_RetryTestExecution(t *testing.T) {
 		_, err = repo.GetLog(ctx, l.ID)
 		assert.ErrorIs(t, err, test.ErrorLogNotFound)
 	}
+
+	// Assert failed attempt was archived with its full history
+	reset, err := repo.GetTestExecution(ctx, testExec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), reset.Attempt)
+
+	attempts, err := svc.ListTestExecutionAttempts(ctx, &ListTestExecutionAttemptsRequest{TestExecutionID: testExec.ID})
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, int32(1), attempts[0].Attempt)
+	assert.Equal(t, test.ExecutionStatusFailed, attempts[0].Status)
+	assert.Equal(t, testExec.Error, attempts[0].Error)
+
+	gotAttempt, err := svc.GetTestExecutionAttempt(ctx, &GetTestExecutionAttemptRequest{TestExecutionID: testExec.ID, Attempt: 1})
+	require.NoError(t, err)
+	assert.Equal(t, attempts[0], gotAttempt.Attempt)
+	assert.Equal(t, test.CaseExecutionList{successCaseExec, failureCaseExec}, gotAttempt.CaseExecutions)
+	assert.Len(t, gotAttempt.Logs, 1+2*numCaseLogs)
+
+	_, err = svc.GetTestExecutionAttempt(ctx, &GetTestExecutionAttemptRequest{TestExecutionID: testExec.ID, Attempt: 2})
+	assert.ErrorIs(t, err, test.ErrorAttemptNotFound)
+}
+
+func TestService_RetryTestExecutionWithOptions(t *testing.T) {
+	type testFixtures struct {
+		successCaseExec *test.CaseExecution
+		failureCaseExec *test.CaseExecution
+	}
+
+	tests := []struct {
+		name          string
+		req           func(f testFixtures) *RetryTestExecutionRequest
+		wantResetID   int64
+		wantCaseExecs func(f testFixtures) test.CaseExecutionList
+		wantNumLogs   int
+		wantErr       error
+	}{
+		{
+			name:        "retry from first failure",
+			req:         func(f testFixtures) *RetryTestExecutionRequest { return &RetryTestExecutionRequest{} },
+			wantResetID: 11,
+			wantCaseExecs: func(f testFixtures) test.CaseExecutionList {
+				return test.CaseExecutionList{f.successCaseExec}
+			},
+			wantNumLogs: 11, // test execution log and success case logs
+		},
+		{
+			name: "retry from failed case",
+			req: func(f testFixtures) *RetryTestExecutionRequest {
+				return &RetryTestExecutionRequest{FromCaseExecution: &f.failureCaseExec.ID}
+			},
+			wantResetID: 11,
+			wantCaseExecs: func(f testFixtures) test.CaseExecutionList {
+				return test.CaseExecutionList{f.successCaseExec}
+			},
+			wantNumLogs: 11,
+		},
+		{
+			name: "retry from passed case",
+			req: func(f testFixtures) *RetryTestExecutionRequest {
+				return &RetryTestExecutionRequest{FromCaseExecution: &f.successCaseExec.ID}
+			},
+			wantResetID: 4,
+			wantCaseExecs: func(f testFixtures) test.CaseExecutionList {
+				return nil
+			},
+			wantNumLogs: 0, // test execution log is published again after the reset point
+		},
+		{
+			name:        "retry from start",
+			req:         func(f testFixtures) *RetryTestExecutionRequest { return &RetryTestExecutionRequest{FromStart: true} },
+			wantResetID: 3,
+			wantCaseExecs: func(f testFixtures) test.CaseExecutionList {
+				return nil
+			},
+			wantNumLogs: 0,
+		},
+		{
+			name: "case execution not found error",
+			req: func(f testFixtures) *RetryTestExecutionRequest {
+				return &RetryTestExecutionRequest{FromCaseExecution: ptr.Get(fake.GenCaseID())}
+			},
+			wantErr: test.ErrorCaseExecutionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := inmem.NewTestRepository(inmem.NewDB())
+
+			caseErr := "case error: bang"
+			baseTest, err := createTest(ctx, repo, fake.GenTestDefinition())
+			require.NoError(t, err)
+
+			testExec := createTestExec(t, ctx, repo, baseTest.ID, &caseErr)
+			f := testFixtures{
+				successCaseExec: createCaseExec(t, ctx, repo, testExec.ID, nil),
+				failureCaseExec: createCaseExec(t, ctx, repo, testExec.ID, &caseErr),
+			}
+
+			testExecLog := fake.GenTestExecLog(testExec.ID)
+			err = repo.CreateLog(ctx, testExecLog)
+			require.NoError(t, err)
+			createCaseLogs(t, ctx, repo, testExec.ID, f.successCaseExec.ID, 10)
+			createCaseLogs(t, ctx, repo, testExec.ID, f.failureCaseExec.ID, 10)
+
+			workflower := fake.NewWorkflower(
+				fake.WithHistory(
+					fake.GenCaseFailureHistory(testExec.ID, getTaskQueue(baseTest.ContextID, baseTest.GroupID), testExecLog.ID, f.successCaseExec.ID, f.failureCaseExec.ID),
+				),
+			)
+			svc := New(repo, workflower)
+
+			req := tt.req(f)
+			req.TestExecutionID = testExec.ID
+			res, err := svc.RetryTestExecutionWithOptions(ctx, req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, workflower.ResetRequests())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testExec.ID, res.ID)
+
+			resets := workflower.ResetRequests()
+			require.Len(t, resets, 1)
+			assert.Equal(t, tt.wantResetID, resets[0].WorkflowTaskFinishEventId)
+
+			gotCaseExecs, err := repo.ListCaseExecutions(ctx, testExec.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCaseExecs(f), gotCaseExecs)
+
+			gotLogs, err := repo.ListLogs(ctx, testExec.ID)
+			require.NoError(t, err)
+			assert.Len(t, gotLogs, tt.wantNumLogs)
+		})
+	}
+}
+
+func TestService_RetryTestExecution_groupChanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(ctx context.Context, s *Service, tt *test.Test) error
+		wantErr error
+	}{
+		{
+			name: "group renamed",
+			change: func(ctx context.Context, s *Service, tt *test.Test) error {
+				return s.RenameGroup(ctx, &RenameGroupRequest{
+					Context:  tt.ContextID,
+					Group:    tt.GroupID,
+					NewGroup: "renamed",
+				})
+			},
+		},
+		{
+			name: "test moved",
+			change: func(ctx context.Context, s *Service, tt *test.Test) error {
+				if err := s.repo.CreateGroup(ctx, tt.ContextID, "other", getTaskQueue(tt.ContextID, "other")); err != nil {
+					return err
+				}
+				_, err := s.MoveTest(ctx, &MoveTestRequest{TestID: tt.ID, Group: "other"})
+				return err
+			},
+			wantErr: test.ErrorTestMoved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := inmem.NewTestRepository(inmem.NewDB())
+
+			caseErr := "case error: bang"
+			baseTest, err := createTest(ctx, repo, fake.GenTestDefinition())
+			require.NoError(t, err)
+
+			testExec := createTestExec(t, ctx, repo, baseTest.ID, &caseErr)
+			successCaseExec := createCaseExec(t, ctx, repo, testExec.ID, nil)
+			failureCaseExec := createCaseExec(t, ctx, repo, testExec.ID, &caseErr)
+			testExecLog := fake.GenTestExecLog(testExec.ID)
+			require.NoError(t, repo.CreateLog(ctx, testExecLog))
+
+			// The test execution was started on the task queue of the group
+			// before the change
+			workflower := fake.NewWorkflower(
+				fake.WithHistory(
+					fake.GenCaseFailureHistory(testExec.ID, getTaskQueue(baseTest.ContextID, baseTest.GroupID), testExecLog.ID, successCaseExec.ID, failureCaseExec.ID),
+				),
+			)
+			// Groups can only be renamed once their runners stop polling
+			workflower.SetPollers(getTaskQueue(baseTest.ContextID, baseTest.GroupID))
+			svc := New(repo, workflower)
+
+			require.NoError(t, tt.change(ctx, svc, baseTest))
+
+			_, err = svc.RetryTestExecutionWithOptions(ctx, &RetryTestExecutionRequest{TestExecutionID: testExec.ID})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, workflower.ResetRequests())
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, workflower.ResetRequests(), 1)
+		})
+	}
+}
+
+func TestService_RerunTestExecution(t *testing.T) {
+	srcInput := fake.GenInput()
+	editedInput := fake.GenInput()
+
+	tests := []struct {
+		name      string
+		srcInput  *test.Payload
+		input     *test.Payload
+		wantInput *test.Payload
+		srcExists bool
+		wantErr   bool
+	}{
+		{
+			name:      "rerun with source input",
+			srcInput:  srcInput,
+			wantInput: srcInput,
+			srcExists: true,
+		},
+		{
+			name:      "rerun with edited input",
+			srcInput:  srcInput,
+			input:     editedInput,
+			wantInput: editedInput,
+			srcExists: true,
+		},
+		{
+			name:      "rerun without input",
+			srcExists: true,
+		},
+		{
+			name:    "source not found error",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, fakes := newService()
+
+			def := fake.GenTestDefinition()
+			def.Name = fake.WorkflowName
+			tst, err := createTest(ctx, fakes.repo, def)
+			require.NoError(t, err)
+
+			var execOpts []executeOption
+			if tt.srcInput != nil {
+				execOpts = append(execOpts, withInput(tt.srcInput.Proto()))
+			}
+			execOpts = append(execOpts, withExecutionTimeout(time.Hour))
+
+			src := &test.TestExecution{ID: test.NewTestExecutionID()}
+			if tt.srcExists {
+				src, err = s.executor.execute(ctx, tst.ID, execOpts...)
+				require.NoError(t, err)
+			}
+
+			req := &RerunTestExecutionRequest{
+				TestExecutionID: src.ID,
+			}
+			if tt.input != nil {
+				req.Input = tt.input.Proto()
+			}
+
+			got, err := s.RerunTestExecution(ctx, req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.NotEqual(t, src.ID, got.ID)
+			assert.Equal(t, src.TestID, got.TestID)
+			assert.Equal(t, &src.ID, got.RerunOfID)
+			assert.Equal(t, src.ExecutionTimeout, got.ExecutionTimeout)
+			assert.Equal(t, test.ExecutionStatusScheduled, got.Status)
+			assert.Equal(t, tt.wantInput != nil, got.HasInput)
+
+			if tt.wantInput != nil {
+				gotInput, err := fakes.repo.GetTestExecutionInput(ctx, got.ID)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantInput.Data, gotInput.Data)
+			}
+
+			// Source execution is untouched
+			gotSrc, err := fakes.repo.GetTestExecution(ctx, src.ID)
+			require.NoError(t, err)
+			assert.Equal(t, src, gotSrc)
+		})
+	}
+}
+
+func TestService_CancelTestExecution(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+
+	tt, err := createTest(ctx, fakes.repo, def)
+	require.NoError(t, err)
+
+	res, err := s.ExecuteTest(ctx, connect.NewRequest(&testsv1.ExecuteTestRequest{
+		TestId: tt.ID.String(),
+	}))
+	require.NoError(t, err)
+
+	testExecID, err := test.ParseTestExecutionID(res.Msg.TestExecution.Id)
+	require.NoError(t, err)
+
+	caseExec, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(testExecID))
+	require.NoError(t, err)
+
+	got, err := s.CancelTestExecution(ctx, &CancelTestExecutionRequest{TestExecutionID: testExecID})
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusCancelled, got.Status)
+	assert.NotNil(t, got.FinishTime)
+
+	gotCaseExec, err := fakes.repo.GetCaseExecution(ctx, testExecID, caseExec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusCancelled, gotCaseExec.Status)
+
+	wr := fakes.workflower.GetWorkflow(ctx, testExecID.WorkflowID(), "")
+	assert.Error(t, wr.Get(ctx, nil))
+
+	_, err = s.CancelTestExecution(ctx, &CancelTestExecutionRequest{TestExecutionID: testExecID})
+	assert.ErrorIs(t, err, test.ErrorTestExecutionFinished)
+}
+
+func TestService_CancelTestExecution_thenAck(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+
+	tt, err := createTest(ctx, fakes.repo, def)
+	require.NoError(t, err)
+
+	testExec, err := s.ExecuteTestWithOptions(ctx, &ExecuteTestRequest{TestID: tt.ID})
+	require.NoError(t, err)
+
+	caseExec, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(testExec.ID))
+	require.NoError(t, err)
+
+	cancelled, err := s.CancelTestExecution(ctx, &CancelTestExecutionRequest{TestExecutionID: testExec.ID})
+	require.NoError(t, err)
+
+	// Late started acks do not restart cancelled executions
+	_, err = s.AckTestExecutionStarted(ctx, connect.NewRequest(&testsv1.AckTestExecutionStartedRequest{
+		TestExecutionId: testExec.ID.String(),
+		StartTime:       timestamppb.Now(),
+	}))
+	require.NoError(t, err)
+	_, err = s.AckCaseExecutionStarted(ctx, connect.NewRequest(&testsv1.AckCaseExecutionStartedRequest{
+		TestExecutionId: testExec.ID.String(),
+		CaseExecutionId: caseExec.ID.Int32(),
+		StartTime:       timestamppb.Now(),
+	}))
+	require.NoError(t, err)
+
+	// Runners acknowledge the end of cancelled workflows and activities
+	_, err = s.AckCaseExecutionFinished(ctx, connect.NewRequest(&testsv1.AckCaseExecutionFinishedRequest{
+		TestExecutionId: testExec.ID.String(),
+		CaseExecutionId: caseExec.ID.Int32(),
+		FinishTime:      timestamppb.Now(),
+		Error:           ptr.Get("canceled"),
+	}))
+	require.NoError(t, err)
+	_, err = s.AckTestExecutionFinished(ctx, connect.NewRequest(&testsv1.AckTestExecutionFinishedRequest{
+		TestExecutionId: testExec.ID.String(),
+		FinishTime:      timestamppb.Now(),
+		Error:           ptr.Get("canceled"),
+	}))
+	require.NoError(t, err)
+
+	got, err := fakes.repo.GetTestExecution(ctx, testExec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, cancelled, got)
+
+	gotCaseExec, err := fakes.repo.GetCaseExecution(ctx, testExec.ID, caseExec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusCancelled, gotCaseExec.Status)
+	assert.Nil(t, gotCaseExec.Error)
+}
+
+func TestService_WatchTimeouts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+
+	tt, err := createTest(ctx, fakes.repo, def)
+	require.NoError(t, err)
+
+	execute := func() test.TestExecutionID {
+		res, err := s.ExecuteTestWithOptions(ctx, &ExecuteTestRequest{
+			TestID:           tt.ID,
+			ExecutionTimeout: ptr.Get(time.Millisecond),
+		})
+		require.NoError(t, err)
+		return res.ID
+	}
+
+	missingID := execute() // cannot be described, which must not stop the pass
+	timedOutID := execute()
+	runningID := execute() // expired locally but still running in temporal
+	completedID := execute()
+	failedID := execute()
+	terminatedID := execute()
+
+	caseExec, err := fakes.repo.CreateScheduledCaseExecution(ctx, fake.GenScheduledCaseExec(timedOutID))
+	require.NoError(t, err)
+
+	require.NoError(t, fakes.workflower.CancelWorkflow(ctx, missingID.WorkflowID(), ""))
+
+	closed := map[test.TestExecutionID]enums.WorkflowExecutionStatus{
+		timedOutID:   enums.WORKFLOW_EXECUTION_STATUS_TIMED_OUT,
+		completedID:  enums.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+		failedID:     enums.WORKFLOW_EXECUTION_STATUS_FAILED,
+		terminatedID: enums.WORKFLOW_EXECUTION_STATUS_TERMINATED,
+	}
+	for id, status := range closed {
+		require.NoError(t, fakes.workflower.SetWorkflowStatus(id.WorkflowID(), status))
+	}
+
+	go s.WatchTimeouts(ctx, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		for id := range closed {
+			got, err := fakes.repo.GetTestExecution(ctx, id)
+			require.NoError(t, err)
+			if !got.Status.IsFinal() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	got, err := fakes.repo.GetTestExecution(ctx, timedOutID)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusTimedOut, got.Status)
+
+	gotCaseExec, err := fakes.repo.GetCaseExecution(ctx, timedOutID, caseExec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusTimedOut, gotCaseExec.Status)
+	assert.NotNil(t, gotCaseExec.FinishTime)
+
+	wantStatuses := map[test.TestExecutionID]test.ExecutionStatus{
+		missingID:    test.ExecutionStatusScheduled,
+		runningID:    test.ExecutionStatusScheduled,
+		completedID:  test.ExecutionStatusPassed,
+		failedID:     test.ExecutionStatusFailed,
+		terminatedID: test.ExecutionStatusCancelled,
+	}
+	for id, want := range wantStatuses {
+		got, err := fakes.repo.GetTestExecution(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, got.Status)
+	}
 }
@@ -0,0 +1,133 @@
+package testservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/annexsh/annex/test"
+)
+
+type ExecuteTestRunRequest struct {
+	Context string
+	Group   *string     // optional: all groups in the context when nil
+	TestIDs []uuid.UUID // optional: all tests in the context or group when empty
+}
+
+// ExecuteTestRun executes many tests as a single test run. The tests are
+// selected by ID when provided, otherwise all tests in the group or context
+// are executed. Tests are executed with their default input.
+//
+// Every test is validated and the test run is created with all of its test
+// executions before any workflow is started, so a test that cannot be
+// executed fails the request without leaving a partial test run. A workflow
+// that fails to start fails its test execution, and so the test run.
+func (s *Service) ExecuteTestRun(ctx context.Context, req *ExecuteTestRunRequest) (*test.TestRun, error) {
+	tests, err := s.getTestRunTests(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(tests) == 0 {
+		return nil, errors.New("test run has no tests to execute")
+	}
+
+	runID := uuid.New()
+	execs := make([]*scheduledExecution, len(tests))
+	scheduled := make([]*test.ScheduledTestExecution, len(tests))
+
+	for i, t := range tests {
+		opts := []executeOption{withTestRun(runID)}
+		if t.HasInput {
+			input, err := s.repo.GetTestDefaultInput(ctx, t.ID)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, withInput(input.Proto()))
+		}
+		if execs[i], err = s.executor.schedule(ctx, t, opts...); err != nil {
+			return nil, fmt.Errorf("test '%s' cannot be executed in test run: %w", t.Name, err)
+		}
+		scheduled[i] = execs[i].scheduled
+	}
+
+	if _, err = s.repo.CreateTestRun(ctx, &test.TestRunDefinition{
+		ID:         runID,
+		ContextID:  req.Context,
+		GroupID:    req.Group,
+		Executions: scheduled,
+	}); err != nil {
+		return nil, err
+	}
+
+	for i, exec := range execs {
+		if err = s.executor.start(ctx, exec); err != nil {
+			s.logger.Error("failed to execute test in test run", "test_run_id", runID.String(), "test_id", tests[i].ID.String(), "error", err)
+		}
+	}
+
+	return s.repo.GetTestRun(ctx, runID)
+}
+
+type GetTestRunRequest struct {
+	TestRunID uuid.UUID
+}
+
+// GetTestRun gets a test run with its status aggregated from its test executions.
+func (s *Service) GetTestRun(ctx context.Context, req *GetTestRunRequest) (*test.TestRun, error) {
+	return s.repo.GetTestRun(ctx, req.TestRunID)
+}
+
+type ListTestRunExecutionsRequest struct {
+	TestRunID uuid.UUID
+}
+
+func (s *Service) ListTestRunExecutions(ctx context.Context, req *ListTestRunExecutionsRequest) (test.TestExecutionList, error) {
+	return s.repo.ListTestRunExecutions(ctx, req.TestRunID)
+}
+
+func (s *Service) getTestRunTests(ctx context.Context, req *ExecuteTestRunRequest) (test.TestList, error) {
+	if len(req.TestIDs) > 0 {
+		var tests test.TestList
+		seen := map[uuid.UUID]bool{}
+
+		for _, id := range req.TestIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			t, err := s.repo.GetTest(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if t.ContextID != req.Context || (req.Group != nil && t.GroupID != *req.Group) {
+				return nil, fmt.Errorf("test '%s' is not in the test run context or group", t.ID)
+			}
+			tests = append(tests, t)
+		}
+
+		return tests, nil
+	}
+
+	if req.Group != nil {
+		return s.repo.ListTests(ctx, req.Context, *req.Group, &test.TestListFilter{})
+	}
+
+	groups, err := s.repo.ListGroups(ctx, req.Context, &test.GroupListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var tests test.TestList
+	for _, group := range groups {
+		groupTests, err := s.repo.ListTests(ctx, req.Context, group, &test.TestListFilter{})
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, groupTests...)
+	}
+
+	return tests, nil
+}
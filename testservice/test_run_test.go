@@ -0,0 +1,225 @@
+package testservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/internal/ptr"
+	"github.com/annexsh/annex/test"
+)
+
+func TestService_ExecuteTestRun(t *testing.T) {
+	contextID := "foo"
+
+	type testFixtures struct {
+		groupA *test.Test
+		groupB *test.Test
+		other  *test.Test // in another context
+	}
+
+	tests := []struct {
+		name      string
+		req       func(f testFixtures) *ExecuteTestRunRequest
+		wantTests func(f testFixtures) []*test.Test
+		wantErr   bool
+	}{
+		{
+			name: "execute context",
+			req: func(f testFixtures) *ExecuteTestRunRequest {
+				return &ExecuteTestRunRequest{Context: contextID}
+			},
+			wantTests: func(f testFixtures) []*test.Test {
+				return []*test.Test{f.groupA, f.groupB}
+			},
+		},
+		{
+			name: "execute group",
+			req: func(f testFixtures) *ExecuteTestRunRequest {
+				return &ExecuteTestRunRequest{Context: contextID, Group: ptr.Get(f.groupA.GroupID)}
+			},
+			wantTests: func(f testFixtures) []*test.Test {
+				return []*test.Test{f.groupA}
+			},
+		},
+		{
+			name: "execute test ids",
+			req: func(f testFixtures) *ExecuteTestRunRequest {
+				return &ExecuteTestRunRequest{
+					Context: contextID,
+					TestIDs: []uuid.UUID{f.groupB.ID, f.groupB.ID},
+				}
+			},
+			wantTests: func(f testFixtures) []*test.Test {
+				return []*test.Test{f.groupB}
+			},
+		},
+		{
+			name: "test outside context error",
+			req: func(f testFixtures) *ExecuteTestRunRequest {
+				return &ExecuteTestRunRequest{Context: contextID, TestIDs: []uuid.UUID{f.other.ID}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "no tests error",
+			req: func(f testFixtures) *ExecuteTestRunRequest {
+				return &ExecuteTestRunRequest{Context: contextID, Group: ptr.Get("empty")}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, fakes := newService()
+
+			createTest := func(contextID string, groupID string) *test.Test {
+				require.NoError(t, fakes.repo.CreateGroup(ctx, contextID, groupID, getTaskQueue(contextID, groupID)))
+				def := fake.GenTestDefinition(fake.WithContextID(contextID), fake.WithGroupID(groupID))
+				def.Name = fake.WorkflowName
+				created, err := fakes.repo.CreateTest(ctx, def)
+				require.NoError(t, err)
+				return created
+			}
+
+			f := testFixtures{
+				groupA: createTest(contextID, "a"),
+				groupB: createTest(contextID, "b"),
+				other:  createTest("other", "a"),
+			}
+
+			got, err := s.ExecuteTestRun(ctx, tt.req(f))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			wantTests := tt.wantTests(f)
+			assert.Equal(t, contextID, got.ContextID)
+			assert.Equal(t, test.ExecutionStatusScheduled, got.Status)
+			assert.Equal(t, test.TestRunCounts{Total: len(wantTests), Scheduled: len(wantTests)}, got.Counts)
+
+			execs, err := s.ListTestRunExecutions(ctx, &ListTestRunExecutionsRequest{TestRunID: got.ID})
+			require.NoError(t, err)
+
+			var wantTestIDs, gotTestIDs []uuid.UUID
+			for _, wt := range wantTests {
+				wantTestIDs = append(wantTestIDs, wt.ID)
+			}
+			for _, exec := range execs {
+				gotTestIDs = append(gotTestIDs, exec.TestID)
+				assert.Equal(t, &got.ID, exec.TestRunID)
+
+				wr := fakes.workflower.GetWorkflow(ctx, exec.ID.WorkflowID(), "")
+				require.NoError(t, wr.Get(ctx, nil))
+			}
+			assert.ElementsMatch(t, wantTestIDs, gotTestIDs)
+
+			// Aggregate status follows the test executions
+			for _, exec := range execs {
+				_, err = fakes.repo.UpdateStartedTestExecution(ctx, fake.GenStartedTestExec(exec.ID))
+				require.NoError(t, err)
+				_, err = fakes.repo.UpdateFinishedTestExecution(ctx, fake.GenFinishedTestExec(exec.ID, nil))
+				require.NoError(t, err)
+			}
+
+			got, err = s.GetTestRun(ctx, &GetTestRunRequest{TestRunID: got.ID})
+			require.NoError(t, err)
+			assert.Equal(t, test.ExecutionStatusPassed, got.Status)
+			assert.Equal(t, test.TestRunCounts{Total: len(wantTests), Passed: len(wantTests)}, got.Counts)
+			assert.NotNil(t, got.FinishTime)
+		})
+	}
+}
+
+func TestService_ExecuteTestRun_validation(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	createTest := func(name string, schema string, defaultInput *test.Payload) *test.Test {
+		def := fake.GenTestDefinition(fake.WithContextID("foo"), fake.WithGroupID("a"))
+		def.Name = name
+		def.DefaultInput = defaultInput
+		if schema != "" {
+			def.InputSchema = []byte(schema)
+		}
+		created, err := fakes.repo.CreateTest(ctx, def)
+		require.NoError(t, err)
+		return created
+	}
+
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "foo", "a", getTaskQueue("foo", "a")))
+	defaultInput := fake.GenInput()
+	withDefault := createTest(fake.WorkflowName, testInputSchema, defaultInput)
+
+	// Executed with its default input
+	run, err := s.ExecuteTestRun(ctx, &ExecuteTestRunRequest{Context: "foo", TestIDs: []uuid.UUID{withDefault.ID}})
+	require.NoError(t, err)
+	execs, err := s.ListTestRunExecutions(ctx, &ListTestRunExecutionsRequest{TestRunID: run.ID})
+	require.NoError(t, err)
+	require.Len(t, execs, 1)
+	gotInput, err := fakes.repo.GetTestExecutionInput(ctx, execs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, defaultInput.Data, gotInput.Data)
+
+	// A test requiring input without a default input fails the whole test run
+	// before any test is executed
+	withoutDefault := createTest(uuid.NewString(), testInputSchema, nil)
+	_, err = s.ExecuteTestRun(ctx, &ExecuteTestRunRequest{Context: "foo", Group: ptr.Get("a")})
+	require.ErrorContains(t, err, withoutDefault.Name)
+
+	execs, err = fakes.repo.ListTestExecutions(ctx, withDefault.ID, &test.TestExecutionListFilter{PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, execs, 1)
+	execs, err = fakes.repo.ListTestExecutions(ctx, withoutDefault.ID, &test.TestExecutionListFilter{PageSize: 10})
+	require.NoError(t, err)
+	assert.Empty(t, execs)
+}
+
+func TestService_ExecuteTestRun_workflowStartFailed(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	require.NoError(t, fakes.repo.CreateGroup(ctx, "foo", "a", getTaskQueue("foo", "a")))
+	createTest := func(name string) *test.Test {
+		def := fake.GenTestDefinition(fake.WithContextID("foo"), fake.WithGroupID("a"))
+		def.Name = name
+		def.DefaultInput = nil
+		created, err := fakes.repo.CreateTest(ctx, def)
+		require.NoError(t, err)
+		return created
+	}
+
+	started := createTest(fake.WorkflowName)
+	unstarted := createTest(uuid.NewString()) // the fake only starts workflows named fake.WorkflowName
+
+	// The workflow that fails to start fails its test execution rather than
+	// the test run
+	run, err := s.ExecuteTestRun(ctx, &ExecuteTestRunRequest{Context: "foo", Group: ptr.Get("a")})
+	require.NoError(t, err)
+	assert.Equal(t, test.ExecutionStatusRunning, run.Status)
+	assert.Equal(t, test.TestRunCounts{Total: 2, Scheduled: 1, Failed: 1}, run.Counts)
+
+	execs, err := s.ListTestRunExecutions(ctx, &ListTestRunExecutionsRequest{TestRunID: run.ID})
+	require.NoError(t, err)
+	require.Len(t, execs, 2)
+	for _, exec := range execs {
+		switch exec.TestID {
+		case started.ID:
+			assert.Equal(t, test.ExecutionStatusScheduled, exec.Status)
+		case unstarted.ID:
+			assert.Equal(t, test.ExecutionStatusFailed, exec.Status)
+			require.NotNil(t, exec.Error)
+			assert.Contains(t, *exec.Error, "failed to start workflow")
+		default:
+			t.Fatalf("unexpected test execution of test %s", exec.TestID)
+		}
+	}
+}
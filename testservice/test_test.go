@@ -2,16 +2,20 @@ package testservice
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"connectrpc.com/connect"
 	testsv1 "github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
 	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
 
 	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/internal/ptr"
 	"github.com/annexsh/annex/test"
 )
 
@@ -63,6 +67,436 @@ func TestService_RegisterTest(t *testing.T) {
 	}
 }
 
+const testInputSchema = `{
+	"type": "object",
+	"properties": {
+		"Foo": {"type": "integer"},
+		"Bar": {"type": "string", "minLength": 1}
+	},
+	"required": ["Foo", "Bar"]
+}`
+
+func TestService_RegisterTestsWithOptions(t *testing.T) {
+	invalidInput, err := converter.NewJSONPayloadConverter().ToPayload(map[string]any{"Foo": "bar"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		schema       string
+		defaultInput *testsv1.Payload
+		wantErr      bool
+	}{
+		{
+			name:         "valid default input",
+			schema:       testInputSchema,
+			defaultInput: fake.GenInput().Proto(),
+		},
+		{
+			name:   "no default input",
+			schema: testInputSchema,
+		},
+		{
+			name:   "invalid default input",
+			schema: testInputSchema,
+			defaultInput: &testsv1.Payload{
+				Metadata: invalidInput.Metadata,
+				Data:     invalidInput.Data,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid schema",
+			schema:  `{"type": "text"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, fakes := newService()
+
+			name := uuid.NewString()
+			req := &RegisterTestsRequest{
+				Context: uuid.NewString(),
+				Group:   uuid.NewString(),
+				Definitions: []*testsv1.TestDefinition{
+					{
+						Name:         name,
+						DefaultInput: tt.defaultInput,
+					},
+				},
+				InputSchemas: map[string][]byte{
+					name: []byte(tt.schema),
+				},
+			}
+
+			res, err := s.RegisterTestsWithOptions(ctx, req)
+			if tt.wantErr {
+				assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+				tests, err := fakes.repo.ListTests(ctx, req.Context, req.Group, &test.TestListFilter{IncludeDeprecated: true})
+				require.NoError(t, err)
+				assert.Empty(t, tests)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, res.Tests, 1)
+
+			got, err := s.GetTestInputSchema(ctx, &GetTestInputSchemaRequest{TestID: res.Tests[0].ID})
+			require.NoError(t, err)
+			assert.Equal(t, []byte(tt.schema), got)
+		})
+	}
+}
+
+func TestService_RegisterTestsWithOptions_keepInputSchema(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newService()
+
+	req := &RegisterTestsRequest{
+		Context:     uuid.NewString(),
+		Group:       uuid.NewString(),
+		Definitions: []*testsv1.TestDefinition{{Name: fake.WorkflowName}},
+		InputSchemas: map[string][]byte{
+			fake.WorkflowName: []byte(testInputSchema),
+		},
+	}
+	_, err := s.RegisterTestsWithOptions(ctx, req)
+	require.NoError(t, err)
+
+	req.InputSchemas = nil
+	res, err := s.RegisterTestsWithOptions(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, res.Tests, 1)
+	assert.Equal(t, int32(1), res.Tests[0].Version)
+	assert.Empty(t, res.Updated)
+
+	got, err := s.GetTestInputSchema(ctx, &GetTestInputSchemaRequest{TestID: res.Tests[0].ID})
+	require.NoError(t, err)
+	assert.JSONEq(t, testInputSchema, string(got))
+
+	invalidInput, err := converter.NewJSONPayloadConverter().ToPayload(map[string]any{"Foo": "bar"})
+	require.NoError(t, err)
+
+	_, err = s.ExecuteTest(ctx, connect.NewRequest(&testsv1.ExecuteTestRequest{
+		TestId: res.Tests[0].ID.String(),
+		Input: &testsv1.Payload{
+			Metadata: invalidInput.Metadata,
+			Data:     invalidInput.Data,
+		},
+	}))
+	var validationErr *jsonschema.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	// The kept schema still applies to the default input.
+	req.Definitions[0].DefaultInput = &testsv1.Payload{
+		Metadata: invalidInput.Metadata,
+		Data:     invalidInput.Data,
+	}
+	_, err = s.RegisterTestsWithOptions(ctx, req)
+	require.Error(t, err)
+}
+
+func TestService_RegisterTestsWithOptions_diff(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	contextID := uuid.NewString()
+	groupID := uuid.NewString()
+
+	register := func(defs ...*testsv1.TestDefinition) *RegisterTestsResult {
+		res, err := s.RegisterTestsWithOptions(ctx, &RegisterTestsRequest{
+			Context:     contextID,
+			Group:       groupID,
+			Definitions: defs,
+		})
+		require.NoError(t, err)
+		return res
+	}
+	names := func(tests test.TestList) []string {
+		var out []string
+		for _, tt := range tests {
+			out = append(out, tt.Name)
+		}
+		return out
+	}
+
+	unchanged := &testsv1.TestDefinition{Name: "unchanged", DefaultInput: fake.GenInput().Proto()}
+	updated := &testsv1.TestDefinition{Name: "updated"}
+	removed := &testsv1.TestDefinition{Name: "removed"}
+
+	res := register(unchanged, updated, removed)
+	assert.ElementsMatch(t, []string{"unchanged", "updated", "removed"}, names(res.Added))
+	assert.Empty(t, res.Updated)
+	assert.Empty(t, res.Deprecated)
+
+	updated.DefaultInput = fake.GenInput().Proto()
+	res = register(unchanged, updated, &testsv1.TestDefinition{Name: "added"})
+	assert.Equal(t, []string{"added"}, names(res.Added))
+	assert.Equal(t, []string{"updated"}, names(res.Updated))
+	require.Len(t, res.Deprecated, 1)
+	assert.Equal(t, "removed", res.Deprecated[0].Name)
+	assert.True(t, res.Deprecated[0].Deprecated)
+	removedID := res.Deprecated[0].ID
+
+	listed, err := s.ListTests(ctx, connect.NewRequest(&testsv1.ListTestsRequest{Context: contextID, Group: groupID}))
+	require.NoError(t, err)
+	assert.Len(t, listed.Msg.Tests, 3)
+
+	all, err := s.ListTestsWithOptions(ctx, &ListTestsRequest{Context: contextID, Group: groupID, IncludeDeprecated: true})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	_, err = s.ExecuteTest(ctx, connect.NewRequest(&testsv1.ExecuteTestRequest{TestId: removedID.String()}))
+	require.ErrorIs(t, err, test.ErrorTestDeprecated)
+
+	_, err = fakes.repo.GetTest(ctx, removedID)
+	require.NoError(t, err)
+
+	res = register(unchanged, updated, removed)
+	assert.Empty(t, res.Added)
+	assert.Equal(t, []string{"removed"}, names(res.Updated))
+	assert.Equal(t, []string{"added"}, names(res.Deprecated))
+	assert.Equal(t, removedID, res.Updated[0].ID)
+	assert.False(t, res.Updated[0].Deprecated)
+}
+
+func TestService_RegisterTestsWithOptions_metadata(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newService()
+
+	req := &RegisterTestsRequest{
+		Context: uuid.NewString(),
+		Group:   uuid.NewString(),
+		Definitions: []*testsv1.TestDefinition{
+			{Name: "checkout"},
+			{Name: "search"},
+		},
+	}
+
+	metadata := test.TestMetadata{
+		Description:    "Checks out a basket",
+		Tags:           []string{"smoke", "payments"},
+		Owner:          "payments-team",
+		SourceLocation: "https://example.com/tests/checkout_test.go#L10",
+	}
+
+	req.Metadata = map[string]test.TestMetadata{"checkout": metadata}
+	res, err := s.RegisterTestsWithOptions(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, res.Tests, 2)
+	assert.Equal(t, metadata, res.Tests[0].Metadata)
+	assert.Empty(t, res.Tests[1].Metadata)
+
+	listed, err := s.ListTestsWithOptions(ctx, &ListTestsRequest{
+		Context: req.Context,
+		Group:   req.Group,
+		Tags:    []string{"smoke"},
+		Owner:   "payments-team",
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "checkout", listed[0].Name)
+	assert.Equal(t, metadata, listed[0].Metadata)
+
+	// Metadata changes are reported as updates without recording a version.
+	metadata.Owner = "checkout-team"
+	req.Metadata = map[string]test.TestMetadata{"checkout": metadata}
+	res, err = s.RegisterTestsWithOptions(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, res.Updated, 1)
+	assert.Equal(t, "checkout", res.Updated[0].Name)
+	assert.Equal(t, int32(1), res.Updated[0].Version)
+
+	// Tests registered without metadata keep their metadata.
+	req.Metadata = nil
+	res, err = s.RegisterTestsWithOptions(ctx, req)
+	require.NoError(t, err)
+	assert.Empty(t, res.Updated)
+	assert.Equal(t, metadata, res.Tests[0].Metadata)
+	assert.Equal(t, int32(1), res.Tests[0].Version)
+}
+
+func TestService_RegisterTestsWithOptions_timeouts(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	req := &RegisterTestsRequest{
+		Context:           uuid.NewString(),
+		Group:             uuid.NewString(),
+		Definitions:       []*testsv1.TestDefinition{{Name: fake.WorkflowName}},
+		ExecutionTimeouts: map[string]time.Duration{fake.WorkflowName: time.Hour},
+		RunTimeouts:       map[string]time.Duration{fake.WorkflowName: time.Minute},
+	}
+	res, err := s.RegisterTestsWithOptions(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, res.Tests, 1)
+	assert.Equal(t, ptr.Get(time.Hour), res.Tests[0].ExecutionTimeout)
+	assert.Equal(t, ptr.Get(time.Minute), res.Tests[0].RunTimeout)
+
+	testExec, err := s.ExecuteTestWithOptions(ctx, &ExecuteTestRequest{TestID: res.Tests[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, ptr.Get(time.Hour), testExec.ExecutionTimeout)
+	assert.Equal(t, ptr.Get(time.Minute), testExec.RunTimeout)
+
+	wr := fakes.workflower.GetWorkflow(ctx, testExec.ID.WorkflowID(), "")
+	wfOpts := wr.(*fake.WorkflowRun).Options()
+	assert.Equal(t, time.Hour, wfOpts.WorkflowExecutionTimeout)
+	assert.Equal(t, time.Minute, wfOpts.WorkflowRunTimeout)
+
+	// Registrations without timeouts, such as by older runners, keep them.
+	rpcRes, err := s.RegisterTests(ctx, connect.NewRequest(&testsv1.RegisterTestsRequest{
+		Context:     req.Context,
+		Group:       req.Group,
+		Definitions: req.Definitions,
+	}))
+	require.NoError(t, err)
+	require.Len(t, rpcRes.Msg.Tests, 1)
+
+	got, err := fakes.repo.GetTest(ctx, res.Tests[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, ptr.Get(time.Hour), got.ExecutionTimeout)
+	assert.Equal(t, ptr.Get(time.Minute), got.RunTimeout)
+	assert.Equal(t, int32(1), got.Version)
+
+	req.RunTimeouts[fake.WorkflowName] = 0
+	_, err = s.RegisterTestsWithOptions(ctx, req)
+	require.Error(t, err)
+}
+
+func TestService_MoveTest(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	require.NoError(t, fakes.repo.CreateGroup(ctx, def.ContextID, def.GroupID, getTaskQueue(def.ContextID, def.GroupID)))
+	require.NoError(t, fakes.repo.CreateGroup(ctx, def.ContextID, "target", getTaskQueue(def.ContextID, "target")))
+
+	tt, err := fakes.repo.CreateTest(ctx, def)
+	require.NoError(t, err)
+	te := createTestExec(t, ctx, fakes.repo, tt.ID, nil)
+
+	_, err = s.MoveTest(ctx, &MoveTestRequest{TestID: tt.ID, Group: "missing"})
+	require.ErrorIs(t, err, test.ErrorGroupNotFound)
+
+	moved, err := s.MoveTest(ctx, &MoveTestRequest{TestID: tt.ID, Group: "target"})
+	require.NoError(t, err)
+	assert.Equal(t, "target", moved.GroupID)
+
+	got, err := fakes.repo.GetTestExecution(ctx, te.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tt.ID, got.TestID)
+}
+
+func TestService_SearchTests(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	var want []string
+	for i := range 5 {
+		name := uuid.NewString()
+		req := &RegisterTestsRequest{
+			Context:     uuid.NewString(),
+			Group:       uuid.NewString(),
+			Definitions: []*testsv1.TestDefinition{{Name: name}},
+			Metadata: map[string]test.TestMetadata{
+				name: {Owner: "team", Tags: []string{fmt.Sprint(i % 2)}},
+			},
+		}
+		_, err := s.RegisterTestsWithOptions(ctx, req)
+		require.NoError(t, err)
+		if i%2 == 0 {
+			want = append(want, req.Definitions[0].Name)
+		}
+	}
+
+	// Tests in archived groups and contexts are not found
+	for _, archive := range []func(req *RegisterTestsRequest) error{
+		func(req *RegisterTestsRequest) error {
+			return s.ArchiveGroup(ctx, &ArchiveGroupRequest{Context: req.Context, Group: req.Group})
+		},
+		func(req *RegisterTestsRequest) error {
+			return s.ArchiveContext(ctx, &ArchiveContextRequest{Context: req.Context})
+		},
+	} {
+		name := uuid.NewString()
+		req := &RegisterTestsRequest{
+			Context:     uuid.NewString(),
+			Group:       uuid.NewString(),
+			Definitions: []*testsv1.TestDefinition{{Name: name}},
+			Metadata: map[string]test.TestMetadata{
+				name: {Owner: "team", Tags: []string{"0"}},
+			},
+		}
+		require.NoError(t, fakes.repo.CreateContext(ctx, req.Context))
+		require.NoError(t, fakes.repo.CreateGroup(ctx, req.Context, req.Group, getTaskQueue(req.Context, req.Group)))
+		_, err := s.RegisterTestsWithOptions(ctx, req)
+		require.NoError(t, err)
+		require.NoError(t, archive(req))
+	}
+
+	var got []string
+	req := &SearchTestsRequest{
+		Tags:     []string{"0"},
+		Owner:    "team",
+		PageSize: 2,
+	}
+	for {
+		res, err := s.SearchTests(ctx, req)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(res.Tests), 2)
+		for _, tt := range res.Tests {
+			got = append(got, tt.Name)
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.NextPageToken = res.NextPageToken
+	}
+
+	assert.ElementsMatch(t, want, got)
+}
+
+func TestService_GetTestExecutionVersion(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	def.Name = fake.WorkflowName
+
+	tt, err := createTest(ctx, fakes.repo, def)
+	require.NoError(t, err)
+
+	execute := func() test.TestExecutionID {
+		res, err := s.ExecuteTest(ctx, connect.NewRequest(&testsv1.ExecuteTestRequest{TestId: tt.ID.String()}))
+		require.NoError(t, err)
+		id, err := test.ParseTestExecutionID(res.Msg.TestExecution.Id)
+		require.NoError(t, err)
+		return id
+	}
+
+	before := execute()
+
+	def.DefaultInput = fake.GenInput()
+	_, err = createTest(ctx, fakes.repo, def)
+	require.NoError(t, err)
+
+	after := execute()
+
+	got, err := s.GetTestExecutionVersion(ctx, &GetTestExecutionVersionRequest{TestExecutionID: before})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), got.Version)
+
+	got, err = s.GetTestExecutionVersion(ctx, &GetTestExecutionVersionRequest{TestExecutionID: after})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), got.Version)
+	assert.Equal(t, def.DefaultInput, got.DefaultInput)
+
+	versions, err := s.ListTestVersions(ctx, &ListTestVersionsRequest{TestID: tt.ID})
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+}
+
 func TestService_GetDefaultInput(t *testing.T) {
 	ctx := context.Background()
 	s, fakes := newService()
@@ -82,6 +516,20 @@ func TestService_GetDefaultInput(t *testing.T) {
 	assert.Equal(t, want, res.Msg.DefaultInput)
 }
 
+func TestService_GetTestDefaultInputPayload(t *testing.T) {
+	ctx := context.Background()
+	s, fakes := newService()
+
+	def := fake.GenTestDefinition()
+	_, err := fakes.repo.CreateTest(ctx, def)
+	require.NoError(t, err)
+
+	got, err := s.GetTestDefaultInputPayload(ctx, &GetTestDefaultInputPayloadRequest{TestID: def.TestID})
+	require.NoError(t, err)
+	assert.Equal(t, def.DefaultInput, got)
+	assert.NotEmpty(t, got.Metadata)
+}
+
 func TestService_ListTests(t *testing.T) {
 	ctx := context.Background()
 	s, fakes := newService()
@@ -93,7 +541,7 @@ func TestService_ListTests(t *testing.T) {
 	groupID := "test-group"
 	err := fakes.repo.CreateContext(ctx, contextID)
 	require.NoError(t, err)
-	err = fakes.repo.CreateGroup(ctx, contextID, groupID)
+	err = fakes.repo.CreateGroup(ctx, contextID, groupID, getTaskQueue(contextID, groupID))
 	require.NoError(t, err)
 
 	for i := range wantCount {
@@ -126,7 +574,7 @@ func TestService_ExecuteTest(t *testing.T) {
 	def := fake.GenTestDefinition()
 	def.Name = fake.WorkflowName
 
-	tt, err := fakes.repo.CreateTest(ctx, def)
+	tt, err := createTest(ctx, fakes.repo, def)
 	require.NoError(t, err)
 
 	req := &testsv1.ExecuteTestRequest{
@@ -143,3 +591,151 @@ func TestService_ExecuteTest(t *testing.T) {
 	err = wr.Get(ctx, nil)
 	require.NoError(t, err)
 }
+
+func TestService_ExecuteTest_inputSchema(t *testing.T) {
+	invalidInput, err := converter.NewJSONPayloadConverter().ToPayload(map[string]any{"Foo": 1.5, "Bar": ""})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		input       *testsv1.Payload
+		wantInvalid []string // locations of the invalid values
+	}{
+		{
+			name:  "valid input",
+			input: fake.GenInput().Proto(),
+		},
+		{
+			name:        "no input",
+			input:       nil,
+			wantInvalid: []string{""},
+		},
+		{
+			name: "invalid input",
+			input: &testsv1.Payload{
+				Metadata: invalidInput.Metadata,
+				Data:     invalidInput.Data,
+			},
+			wantInvalid: []string{"/Foo", "/Bar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, fakes := newService()
+
+			def := fake.GenTestDefinition()
+			def.Name = fake.WorkflowName
+			def.InputSchema = []byte(testInputSchema)
+
+			created, err := createTest(ctx, fakes.repo, def)
+			require.NoError(t, err)
+
+			req := &testsv1.ExecuteTestRequest{
+				TestId: created.ID.String(),
+				Input:  tt.input,
+			}
+			_, err = s.ExecuteTest(ctx, connect.NewRequest(req))
+
+			if tt.wantInvalid != nil {
+				assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+				var validationErr *jsonschema.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.ElementsMatch(t, tt.wantInvalid, invalidLocations(validationErr))
+
+				execs, err := fakes.repo.ListTestExecutions(ctx, created.ID, &test.TestExecutionListFilter{PageSize: 10})
+				require.NoError(t, err)
+				assert.Empty(t, execs)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_ExecuteTestWithOptions(t *testing.T) {
+	tests := []struct {
+		name                 string
+		testExecutionTimeout *time.Duration
+		testRunTimeout       *time.Duration
+		executionTimeout     *time.Duration
+		runTimeout           *time.Duration
+		wantExecutionTimeout time.Duration
+		wantRunTimeout       *time.Duration
+		wantErr              bool
+	}{
+		{
+			name:                 "default timeout",
+			wantExecutionTimeout: defaultExecutionTimeout,
+		},
+		{
+			name:                 "test timeouts",
+			testExecutionTimeout: ptr.Get(time.Hour),
+			testRunTimeout:       ptr.Get(10 * time.Minute),
+			wantExecutionTimeout: time.Hour,
+			wantRunTimeout:       ptr.Get(10 * time.Minute),
+		},
+		{
+			name:                 "override test timeouts",
+			testExecutionTimeout: ptr.Get(time.Hour),
+			testRunTimeout:       ptr.Get(10 * time.Minute),
+			executionTimeout:     ptr.Get(2 * time.Hour),
+			runTimeout:           ptr.Get(time.Hour),
+			wantExecutionTimeout: 2 * time.Hour,
+			wantRunTimeout:       ptr.Get(time.Hour),
+		},
+		{
+			name:             "invalid execution timeout error",
+			executionTimeout: ptr.Get(-time.Hour),
+			wantErr:          true,
+		},
+		{
+			name:       "invalid run timeout error",
+			runTimeout: ptr.Get(time.Duration(0)),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, fakes := newService()
+
+			def := fake.GenTestDefinition()
+			def.Name = fake.WorkflowName
+			def.ExecutionTimeout = tt.testExecutionTimeout
+			def.RunTimeout = tt.testRunTimeout
+
+			created, err := createTest(ctx, fakes.repo, def)
+			require.NoError(t, err)
+
+			res, err := s.ExecuteTestWithOptions(ctx, &ExecuteTestRequest{
+				TestID:           created.ID,
+				ExecutionTimeout: tt.executionTimeout,
+				RunTimeout:       tt.runTimeout,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			testExecID := res.ID
+
+			got, err := fakes.repo.GetTestExecution(ctx, testExecID)
+			require.NoError(t, err)
+			assert.Equal(t, &tt.wantExecutionTimeout, got.ExecutionTimeout)
+			assert.Equal(t, tt.wantRunTimeout, got.RunTimeout)
+
+			wr := fakes.workflower.GetWorkflow(ctx, testExecID.WorkflowID(), "")
+			wfOpts := wr.(*fake.WorkflowRun).Options()
+			assert.Equal(t, tt.wantExecutionTimeout, wfOpts.WorkflowExecutionTimeout)
+			if tt.wantRunTimeout != nil {
+				assert.Equal(t, *tt.wantRunTimeout, wfOpts.WorkflowRunTimeout)
+			} else {
+				assert.Zero(t, wfOpts.WorkflowRunTimeout)
+			}
+		})
+	}
+}
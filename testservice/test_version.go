@@ -0,0 +1,49 @@
+package testservice
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/annexsh/annex/test"
+)
+
+type ListTestVersionsRequest struct {
+	TestID uuid.UUID
+}
+
+// ListTestVersions lists the distinct definitions a test has been registered
+// with in version order.
+func (s *Service) ListTestVersions(ctx context.Context, req *ListTestVersionsRequest) (test.TestVersionList, error) {
+	if _, err := s.repo.GetTest(ctx, req.TestID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTestVersions(ctx, req.TestID)
+}
+
+type GetTestVersionRequest struct {
+	TestID  uuid.UUID
+	Version int32
+}
+
+func (s *Service) GetTestVersion(ctx context.Context, req *GetTestVersionRequest) (*test.TestVersion, error) {
+	return s.repo.GetTestVersion(ctx, req.TestID, req.Version)
+}
+
+type GetTestExecutionVersionRequest struct {
+	TestExecutionID test.TestExecutionID
+}
+
+// GetTestExecutionVersion gets the test definition a test execution ran
+// against. Comparing the versions of two executions tells whether the test
+// changed between them.
+func (s *Service) GetTestExecutionVersion(ctx context.Context, req *GetTestExecutionVersionRequest) (*test.TestVersion, error) {
+	exec, err := s.repo.GetTestExecution(ctx, req.TestExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	if exec.TestVersion == nil {
+		return nil, test.ErrorTestVersionNotFound
+	}
+	return s.repo.GetTestVersion(ctx, exec.TestID, *exec.TestVersion)
+}
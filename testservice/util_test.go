@@ -2,6 +2,8 @@ package testservice
 
 import (
 	"context"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -9,12 +11,16 @@ import (
 
 	"github.com/annexsh/annex/inmem"
 	"github.com/annexsh/annex/internal/fake"
+	"github.com/annexsh/annex/postgres"
 	"github.com/annexsh/annex/test"
 )
 
+// postgresSchemaVersion is the latest migration version.
+const postgresSchemaVersion = 24
+
 type fakeDeps struct {
 	repo       test.Repository
-	workflower Workflower
+	workflower *fake.Workflower
 }
 
 func newService() (*Service, *fakeDeps) {
@@ -26,6 +32,46 @@ func newService() (*Service, *fakeDeps) {
 	}
 }
 
+// newPostgresService creates a service backed by the database at
+// ANNEX_TEST_POSTGRES_URL, e.g. the database of the compose stack. Tests are
+// skipped if it is not set. The database is shared by test runs, so tests must
+// use unique names, e.g. from uniqueName.
+func newPostgresService(t *testing.T) (*Service, *fakeDeps) {
+	url := os.Getenv("ANNEX_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("ANNEX_TEST_POSTGRES_URL not set")
+	}
+
+	pool, err := postgres.OpenPool(context.Background(), url, postgresSchemaVersion)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	repo := postgres.NewTestRepository(postgres.NewDB(pool))
+	workflower := fake.NewWorkflower()
+	return New(repo, workflower), &fakeDeps{
+		repo:       repo,
+		workflower: workflower,
+	}
+}
+
+// uniqueName generates a context or group name without the task queue
+// separator.
+func uniqueName() string {
+	return strings.ReplaceAll(uuid.NewString(), taskQueueSeparator, "")
+}
+
+// createTest creates a test in its group. Tests are executed on the task queue
+// of their group, so they cannot be executed unless their group exists.
+func createTest(ctx context.Context, repo test.Repository, def *test.TestDefinition) (*test.Test, error) {
+	if err := repo.CreateContext(ctx, def.ContextID); err != nil {
+		return nil, err
+	}
+	if err := repo.CreateGroup(ctx, def.ContextID, def.GroupID, getTaskQueue(def.ContextID, def.GroupID)); err != nil {
+		return nil, err
+	}
+	return repo.CreateTest(ctx, def)
+}
+
 func createTestExec(t *testing.T, ctx context.Context, repo test.Repository, testID uuid.UUID, failure *string) *test.TestExecution {
 	te, err := repo.CreateScheduledTestExecution(ctx, fake.GenScheduledTestExec(testID))
 	require.NoError(t, err)
@@ -8,15 +8,23 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/annexsh/annex-proto/gen/go/annex/tests/v1"
+	commonpb "go.temporal.io/api/common/v1"
 	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/taskqueue/v1"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/server/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/annexsh/annex/test"
 )
 
 func (s *ProxyService) PollWorkflowTaskQueue(ctx context.Context, req *workflowservice.PollWorkflowTaskQueueRequest) (*workflowservice.PollWorkflowTaskQueueResponse, error) {
+	if err := s.resolveTaskQueue(ctx, req.TaskQueue); err != nil {
+		return nil, err
+	}
+
 	res, err := s.workflow.PollWorkflowTaskQueue(ctx, req)
 	if err != nil {
 		return nil, err
@@ -53,7 +61,7 @@ func (s *ProxyService) PollWorkflowTaskQueue(ctx context.Context, req *workflows
 		failedEventAttrs := initEvent.GetWorkflowTaskFailedEventAttributes()
 		isWorkflowExecReset := failedEventAttrs != nil && failedEventAttrs.Cause == enums.WORKFLOW_TASK_FAILED_CAUSE_RESET_WORKFLOW
 		if isWorkflowExecStarted || isWorkflowExecReset {
-			testExecID, err := test.ParseTestWorkflowID(res.WorkflowExecution.WorkflowId)
+			testExecID, err := s.getTestExecutionID(ctx, res.WorkflowExecution.WorkflowId)
 			if err == nil {
 				if _, err = s.test.AckTestExecutionStarted(ctx, connect.NewRequest(&testsv1.AckTestExecutionStartedRequest{
 					TestExecutionId: testExecID.String(),
@@ -76,7 +84,7 @@ func (s *ProxyService) RespondWorkflowTaskCompleted(ctx context.Context, req *wo
 		return nil, err
 	}
 
-	testExecID, err := test.ParseTestWorkflowID(tkn.WorkflowId)
+	testExecID, err := s.getTestExecutionID(ctx, tkn.WorkflowId)
 	if err != nil {
 		if errors.Is(err, test.ErrorNotTestExecution) {
 			return s.workflow.RespondWorkflowTaskCompleted(ctx, req)
@@ -107,16 +115,38 @@ func (s *ProxyService) RespondWorkflowTaskCompleted(ctx context.Context, req *wo
 			})); err != nil {
 				return nil, fmt.Errorf("failed to acknowledge scheduled case execution: %w", err)
 			}
+
+			if s.casePayloads != nil {
+				input := test.NewPayloadsFromTemporal(attrs.Input)
+				if err = s.casePayloads.RecordCaseExecutionInput(ctx, testExecID, caseExecID, input); err != nil {
+					return nil, err
+				}
+			}
+		case enums.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+			attrs := cmd.GetStartChildWorkflowExecutionCommandAttributes()
+			if attrs == nil || s.subExecs == nil {
+				continue
+			}
+
+			if err = s.subExecs.RecordSubTestExecutionScheduled(ctx, &test.ScheduledSubTestExecution{
+				ID:                test.NewSubTestExecutionID(testExecID, tkn.RunId, attrs.WorkflowId),
+				ParentID:          testExecID,
+				ChildWorkflowID:   attrs.WorkflowId,
+				ChildWorkflowType: attrs.WorkflowType.GetName(),
+				ScheduleTime:      time.Now().UTC(),
+			}); err != nil {
+				return nil, err
+			}
 		case enums.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION:
 			attrs := cmd.GetCompleteWorkflowExecutionCommandAttributes()
 			if attrs == nil {
 				continue
 			}
 
-			if _, err = s.test.AckTestExecutionFinished(ctx, connect.NewRequest(&testsv1.AckTestExecutionFinishedRequest{
-				TestExecutionId: testExecID.String(),
-				FinishTime:      timestamppb.New(time.Now().UTC()),
-			})); err != nil {
+			if err = s.ackTestExecutionFinished(ctx, &test.FinishedTestExecution{
+				ID:         testExecID,
+				FinishTime: time.Now().UTC(),
+			}); err != nil {
 				return nil, err
 			}
 		case enums.COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION:
@@ -130,11 +160,12 @@ func (s *ProxyService) RespondWorkflowTaskCompleted(ctx context.Context, req *wo
 				testExecError = &attrs.Failure.Message
 			}
 
-			if _, err = s.test.AckTestExecutionFinished(ctx, connect.NewRequest(&testsv1.AckTestExecutionFinishedRequest{
-				TestExecutionId: testExecID.String(),
-				FinishTime:      timestamppb.New(time.Now().UTC()),
-				Error:           testExecError,
-			})); err != nil {
+			if err = s.ackTestExecutionFinished(ctx, &test.FinishedTestExecution{
+				ID:         testExecID,
+				FinishTime: time.Now().UTC(),
+				Error:      testExecError,
+				Failure:    test.NewFailureFromTemporal(attrs.Failure),
+			}); err != nil {
 				return nil, err
 			}
 		}
@@ -144,6 +175,10 @@ func (s *ProxyService) RespondWorkflowTaskCompleted(ctx context.Context, req *wo
 }
 
 func (s *ProxyService) PollActivityTaskQueue(ctx context.Context, req *workflowservice.PollActivityTaskQueueRequest) (*workflowservice.PollActivityTaskQueueResponse, error) {
+	if err := s.resolveTaskQueue(ctx, req.TaskQueue); err != nil {
+		return nil, err
+	}
+
 	res, err := s.workflow.PollActivityTaskQueue(ctx, req)
 	if err != nil {
 		return nil, err
@@ -153,7 +188,7 @@ func (s *ProxyService) PollActivityTaskQueue(ctx context.Context, req *workflows
 		return res, nil
 	}
 
-	testExecID, err := test.ParseTestWorkflowID(res.WorkflowExecution.WorkflowId)
+	testExecID, err := s.getTestExecutionID(ctx, res.WorkflowExecution.WorkflowId)
 	if err != nil {
 		if errors.Is(err, test.ErrorNotTestExecution) {
 			return res, nil
@@ -169,17 +204,92 @@ func (s *ProxyService) PollActivityTaskQueue(ctx context.Context, req *workflows
 		return nil, err
 	}
 
+	startTime := timestamppb.Now()
+
 	if _, err = s.test.AckCaseExecutionStarted(ctx, connect.NewRequest(&testsv1.AckCaseExecutionStartedRequest{
 		TestExecutionId: testExecID.String(),
 		CaseExecutionId: caseExecID.Int32(),
-		StartTime:       timestamppb.Now(),
+		StartTime:       startTime,
 	})); err != nil {
 		return nil, err
 	}
 
+	if s.caseAttempts != nil {
+		if err = s.caseAttempts.RecordCaseExecutionAttemptStarted(ctx, &test.StartedCaseExecutionAttempt{
+			TestExecutionID: testExecID,
+			CaseExecutionID: caseExecID,
+			Attempt:         res.Attempt,
+			StartTime:       startTime.AsTime(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
+func (s *ProxyService) RecordActivityTaskHeartbeat(ctx context.Context, req *workflowservice.RecordActivityTaskHeartbeatRequest) (*workflowservice.RecordActivityTaskHeartbeatResponse, error) {
+	// Heartbeats are proxied even if they cannot be recorded so that healthy
+	// activities are not timed out by a recording failure.
+	if err := s.recordTaskTokenHeartbeat(ctx, req.TaskToken, req.Details); err != nil {
+		s.logger.Error("failed to record case execution heartbeat", "error", err)
+	}
+	return s.workflow.RecordActivityTaskHeartbeat(ctx, req)
+}
+
+func (s *ProxyService) RecordActivityTaskHeartbeatById(ctx context.Context, req *workflowservice.RecordActivityTaskHeartbeatByIdRequest) (*workflowservice.RecordActivityTaskHeartbeatByIdResponse, error) {
+	if err := s.recordCaseHeartbeat(ctx, req.WorkflowId, req.ActivityId, req.Details); err != nil {
+		s.logger.Error("failed to record case execution heartbeat", "error", err)
+	}
+	return s.workflow.RecordActivityTaskHeartbeatById(ctx, req)
+}
+
+func (s *ProxyService) recordTaskTokenHeartbeat(ctx context.Context, taskToken []byte, details *commonpb.Payloads) error {
+	if s.caseHeartbeats == nil {
+		return nil
+	}
+
+	tkn, err := common.NewProtoTaskTokenSerializer().Deserialize(taskToken)
+	if err != nil {
+		return err
+	}
+
+	return s.recordCaseHeartbeat(ctx, tkn.WorkflowId, tkn.ActivityId, details)
+}
+
+func (s *ProxyService) recordCaseHeartbeat(ctx context.Context, workflowID string, activityID string, details *commonpb.Payloads) error {
+	if s.caseHeartbeats == nil {
+		return nil
+	}
+
+	if workflowID == "" || activityID == "" {
+		return nil
+	}
+
+	testExecID, err := s.getTestExecutionID(ctx, workflowID)
+	if err != nil {
+		if errors.Is(err, test.ErrorNotTestExecution) {
+			return nil
+		}
+		return err
+	}
+
+	caseExecID, err := test.ParseCaseActivityID(activityID)
+	if err != nil {
+		if errors.Is(err, test.ErrorNotCaseExecution) {
+			return nil
+		}
+		return err
+	}
+
+	return s.caseHeartbeats.RecordCaseExecutionHeartbeat(ctx, &test.CaseExecutionHeartbeat{
+		ID:              caseExecID,
+		TestExecutionID: testExecID,
+		HeartbeatTime:   time.Now().UTC(),
+		Progress:        test.NewPayloadsFromTemporal(details),
+	})
+}
+
 func (s *ProxyService) RespondActivityTaskCompleted(ctx context.Context, req *workflowservice.RespondActivityTaskCompletedRequest) (*workflowservice.RespondActivityTaskCompletedResponse, error) {
 	tkn, err := common.NewProtoTaskTokenSerializer().Deserialize(req.TaskToken)
 	if err != nil {
@@ -190,7 +300,7 @@ func (s *ProxyService) RespondActivityTaskCompleted(ctx context.Context, req *wo
 		return s.workflow.RespondActivityTaskCompleted(ctx, req)
 	}
 
-	testExecID, err := test.ParseTestWorkflowID(tkn.WorkflowId)
+	testExecID, err := s.getTestExecutionID(ctx, tkn.WorkflowId)
 	if err != nil {
 		if errors.Is(err, test.ErrorNotTestExecution) {
 			return s.workflow.RespondActivityTaskCompleted(ctx, req)
@@ -206,14 +316,34 @@ func (s *ProxyService) RespondActivityTaskCompleted(ctx context.Context, req *wo
 		return nil, err
 	}
 
-	if _, err = s.test.AckCaseExecutionFinished(ctx, connect.NewRequest(&testsv1.AckCaseExecutionFinishedRequest{
-		TestExecutionId: testExecID.String(),
-		CaseExecutionId: caseExecID.Int32(),
-		FinishTime:      timestamppb.Now(),
-	})); err != nil {
+	finishTime := time.Now().UTC()
+
+	if err = s.ackCaseExecutionFinished(ctx, &test.FinishedCaseExecution{
+		ID:              caseExecID,
+		TestExecutionID: testExecID,
+		FinishTime:      finishTime,
+	}); err != nil {
 		return nil, err
 	}
 
+	if s.caseAttempts != nil {
+		if err = s.caseAttempts.RecordCaseExecutionAttemptFinished(ctx, &test.FinishedCaseExecutionAttempt{
+			TestExecutionID: testExecID,
+			CaseExecutionID: caseExecID,
+			Attempt:         tkn.Attempt,
+			FinishTime:      finishTime,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Activities return at most one result
+	if results := test.NewPayloadsFromTemporal(req.Result); s.casePayloads != nil && len(results) > 0 {
+		if err = s.casePayloads.RecordCaseExecutionResult(ctx, testExecID, caseExecID, results[0]); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.workflow.RespondActivityTaskCompleted(ctx, req)
 }
 
@@ -227,7 +357,7 @@ func (s *ProxyService) RespondActivityTaskFailed(ctx context.Context, req *workf
 		return s.workflow.RespondActivityTaskFailed(ctx, req)
 	}
 
-	testExecID, err := test.ParseTestWorkflowID(tkn.WorkflowId)
+	testExecID, err := s.getTestExecutionID(ctx, tkn.WorkflowId)
 	if err != nil {
 		if errors.Is(err, test.ErrorNotTestExecution) {
 			return s.workflow.RespondActivityTaskFailed(ctx, req)
@@ -248,14 +378,118 @@ func (s *ProxyService) RespondActivityTaskFailed(ctx context.Context, req *workf
 		execErr = &req.Failure.Message
 	}
 
-	if _, err = s.test.AckCaseExecutionFinished(ctx, connect.NewRequest(&testsv1.AckCaseExecutionFinishedRequest{
-		TestExecutionId: testExecID.String(),
-		CaseExecutionId: caseExecID.Int32(),
+	failure := test.NewFailureFromTemporal(req.Failure)
+	finishTime := time.Now().UTC()
+
+	if err = s.ackCaseExecutionFinished(ctx, &test.FinishedCaseExecution{
+		ID:              caseExecID,
+		TestExecutionID: testExecID,
+		FinishTime:      finishTime,
 		Error:           execErr,
-		FinishTime:      timestamppb.Now(),
-	})); err != nil {
+		Failure:         failure,
+	}); err != nil {
 		return nil, err
 	}
 
+	if s.caseAttempts != nil {
+		if err = s.caseAttempts.RecordCaseExecutionAttemptFinished(ctx, &test.FinishedCaseExecutionAttempt{
+			TestExecutionID: testExecID,
+			CaseExecutionID: caseExecID,
+			Attempt:         tkn.Attempt,
+			FinishTime:      finishTime,
+			Error:           execErr,
+			Failure:         failure,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.workflow.RespondActivityTaskFailed(ctx, req)
 }
+
+// ackTestExecutionFinished acknowledges a finished test execution with its
+// failure if the proxy has a finished acker, or with the tests API otherwise.
+func (s *ProxyService) ackTestExecutionFinished(ctx context.Context, finished *test.FinishedTestExecution) error {
+	if s.finished != nil {
+		return s.finished.AckTestExecutionFinished(ctx, finished)
+	}
+	_, err := s.test.AckTestExecutionFinished(ctx, connect.NewRequest(&testsv1.AckTestExecutionFinishedRequest{
+		TestExecutionId: finished.ID.String(),
+		FinishTime:      timestamppb.New(finished.FinishTime),
+		Error:           finished.Error,
+	}))
+	return err
+}
+
+// ackCaseExecutionFinished acknowledges a finished case execution with its
+// failure if the proxy has a finished acker, or with the tests API otherwise.
+func (s *ProxyService) ackCaseExecutionFinished(ctx context.Context, finished *test.FinishedCaseExecution) error {
+	if s.finished != nil {
+		return s.finished.AckCaseExecutionFinished(ctx, finished)
+	}
+	_, err := s.test.AckCaseExecutionFinished(ctx, connect.NewRequest(&testsv1.AckCaseExecutionFinishedRequest{
+		TestExecutionId: finished.TestExecutionID.String(),
+		CaseExecutionId: finished.ID.Int32(),
+		FinishTime:      timestamppb.New(finished.FinishTime),
+		Error:           finished.Error,
+	}))
+	return err
+}
+
+// getTestExecutionID gets the test execution of a workflow. Workflows that are
+// not test workflows are resolved as child workflows of test executions if the
+// proxy records sub-executions.
+func (s *ProxyService) getTestExecutionID(ctx context.Context, workflowID string) (test.TestExecutionID, error) {
+	testExecID, err := test.ParseTestWorkflowID(workflowID)
+	if errors.Is(err, test.ErrorNotTestExecution) && s.subExecs != nil {
+		return s.resolveSubExecution(ctx, workflowID)
+	}
+	return testExecID, err
+}
+
+// subExecution is the cached sub-execution of a workflow. isSubExec is false
+// for workflows that are not child workflows of a test execution.
+type subExecution struct {
+	id        test.TestExecutionID
+	isSubExec bool
+}
+
+// resolveSubExecution resolves a workflow to its sub-execution. Every task of
+// a workflow that is not a test execution would otherwise be looked up, so
+// results are cached. Sub-executions are recorded before their child workflow
+// starts, so a workflow is only found to be a sub-execution later if its
+// workflow ID is reused, which the cache TTL allows for.
+func (s *ProxyService) resolveSubExecution(ctx context.Context, workflowID string) (test.TestExecutionID, error) {
+	if cached, ok := s.subExecCache.Get(workflowID); ok {
+		if !cached.isSubExec {
+			return test.TestExecutionID{}, test.ErrorNotTestExecution
+		}
+		return cached.id, nil
+	}
+
+	testExecID, err := s.subExecs.ResolveSubTestExecution(ctx, workflowID)
+	if err != nil {
+		if errors.Is(err, test.ErrorNotTestExecution) {
+			s.subExecCache.Add(workflowID, subExecution{})
+		}
+		return test.TestExecutionID{}, err
+	}
+
+	s.subExecCache.Add(workflowID, subExecution{id: testExecID, isSubExec: true})
+	return testExecID, nil
+}
+
+// resolveTaskQueue replaces the task queue polled by a runner with the task
+// queue its tests are executed on. Sticky task queues are specific to a runner
+// and are not resolved.
+func (s *ProxyService) resolveTaskQueue(ctx context.Context, taskQueue *taskqueue.TaskQueue) error {
+	if s.taskQueues == nil || taskQueue == nil || taskQueue.Kind == enums.TASK_QUEUE_KIND_STICKY {
+		return nil
+	}
+	resolved, err := s.taskQueues.ResolveTaskQueue(ctx, taskQueue.Name)
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	taskQueue.Name = resolved
+	return nil
+}
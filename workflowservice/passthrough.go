@@ -88,14 +88,6 @@ func (s *ProxyService) DeprecateNamespace(ctx context.Context, req *workflowserv
 	return s.workflow.DeprecateNamespace(ctx, req)
 }
 
-func (s *ProxyService) RecordActivityTaskHeartbeat(ctx context.Context, req *workflowservice.RecordActivityTaskHeartbeatRequest) (*workflowservice.RecordActivityTaskHeartbeatResponse, error) {
-	return s.workflow.RecordActivityTaskHeartbeat(ctx, req)
-}
-
-func (s *ProxyService) RecordActivityTaskHeartbeatById(ctx context.Context, req *workflowservice.RecordActivityTaskHeartbeatByIdRequest) (*workflowservice.RecordActivityTaskHeartbeatByIdResponse, error) {
-	return s.workflow.RecordActivityTaskHeartbeatById(ctx, req)
-}
-
 func (s *ProxyService) RespondActivityTaskFailedById(ctx context.Context, req *workflowservice.RespondActivityTaskFailedByIdRequest) (*workflowservice.RespondActivityTaskFailedByIdResponse, error) {
 	return s.workflow.RespondActivityTaskFailedById(ctx, req)
 }
@@ -1,24 +1,156 @@
 package workflowservice
 
 import (
+	"context"
+	"time"
+
 	"github.com/annexsh/annex-proto/gen/go/annex/tests/v1/testsv1connect"
+	"github.com/hashicorp/golang-lru/v2/expirable"
 	"go.temporal.io/api/workflowservice/v1"
+
+	"github.com/annexsh/annex/log"
+	"github.com/annexsh/annex/test"
 )
 
 var _ workflowservice.WorkflowServiceServer = (*ProxyService)(nil)
 
+const (
+	// subExecCacheSize is the number of workflows whose sub-execution is cached.
+	subExecCacheSize = 10000
+	// subExecCacheTTL bounds how long a workflow is remembered as not being a
+	// sub-execution, in case its workflow ID is reused by a child workflow.
+	subExecCacheTTL = 10 * time.Minute
+)
+
+// TaskQueueResolver resolves the task queues polled by runners to the task
+// queues that tests are executed on.
+type TaskQueueResolver interface {
+	ResolveTaskQueue(ctx context.Context, taskQueue string) (string, error)
+}
+
+// CasePayloadRecorder records the payloads case executions are called with and
+// return.
+type CasePayloadRecorder interface {
+	RecordCaseExecutionInput(ctx context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID, input []*test.Payload) error
+	RecordCaseExecutionResult(ctx context.Context, testExecID test.TestExecutionID, caseExecID test.CaseExecutionID, result *test.Payload) error
+}
+
+// CaseAttemptRecorder records the attempts of case executions.
+type CaseAttemptRecorder interface {
+	RecordCaseExecutionAttemptStarted(ctx context.Context, started *test.StartedCaseExecutionAttempt) error
+	RecordCaseExecutionAttemptFinished(ctx context.Context, finished *test.FinishedCaseExecutionAttempt) error
+}
+
+// CaseHeartbeatRecorder records the heartbeats of running case executions.
+type CaseHeartbeatRecorder interface {
+	RecordCaseExecutionHeartbeat(ctx context.Context, heartbeat *test.CaseExecutionHeartbeat) error
+}
+
+// SubExecutionRecorder records the child workflows started by test executions
+// as sub-executions, and resolves child workflows to their sub-executions.
+type SubExecutionRecorder interface {
+	RecordSubTestExecutionScheduled(ctx context.Context, scheduled *test.ScheduledSubTestExecution) error
+	ResolveSubTestExecution(ctx context.Context, childWorkflowID string) (test.TestExecutionID, error)
+}
+
+// FinishedAcker acknowledges finished test and case executions with the
+// structured failures the tests API cannot carry.
+type FinishedAcker interface {
+	AckTestExecutionFinished(ctx context.Context, finished *test.FinishedTestExecution) error
+	AckCaseExecutionFinished(ctx context.Context, finished *test.FinishedCaseExecution) error
+}
+
+type ProxyOption func(s *ProxyService)
+
+// WithLogger logs the failures that do not fail proxied requests, such as
+// failures to record case heartbeats.
+func WithLogger(logger log.Logger) ProxyOption {
+	return func(s *ProxyService) {
+		s.logger = logger
+	}
+}
+
+// WithTaskQueueResolver resolves the task queues of polls before they are
+// proxied. Task queues are proxied unchanged by default.
+func WithTaskQueueResolver(resolver TaskQueueResolver) ProxyOption {
+	return func(s *ProxyService) {
+		s.taskQueues = resolver
+	}
+}
+
+// WithCasePayloadRecorder records the inputs and results of case executions.
+// Case payloads are not recorded by default.
+func WithCasePayloadRecorder(recorder CasePayloadRecorder) ProxyOption {
+	return func(s *ProxyService) {
+		s.casePayloads = recorder
+	}
+}
+
+// WithCaseAttemptRecorder records each attempt of case executions whose
+// activities are retried. Case attempts are not recorded by default.
+func WithCaseAttemptRecorder(recorder CaseAttemptRecorder) ProxyOption {
+	return func(s *ProxyService) {
+		s.caseAttempts = recorder
+	}
+}
+
+// WithCaseHeartbeatRecorder records the heartbeats of case executions and the
+// progress reported as their details. Case heartbeats are not recorded by
+// default.
+func WithCaseHeartbeatRecorder(recorder CaseHeartbeatRecorder) ProxyOption {
+	return func(s *ProxyService) {
+		s.caseHeartbeats = recorder
+	}
+}
+
+// WithSubExecutionRecorder records child workflows started by test executions
+// as sub-executions with their own case executions. Workflows that are not
+// test workflows are then looked up as child workflows, which costs a lookup
+// for each of their tasks. Child workflows are not recorded by default.
+func WithSubExecutionRecorder(recorder SubExecutionRecorder) ProxyOption {
+	return func(s *ProxyService) {
+		s.subExecs = recorder
+	}
+}
+
+// WithFinishedAcker acknowledges finished executions with their structured
+// failures. Finished executions are acknowledged with the tests API by default,
+// which only records failure messages.
+func WithFinishedAcker(acker FinishedAcker) ProxyOption {
+	return func(s *ProxyService) {
+		s.finished = acker
+	}
+}
+
 type ProxyService struct {
 	workflowservice.UnimplementedWorkflowServiceServer
-	workflow workflowservice.WorkflowServiceClient
-	test     testsv1connect.TestServiceClient
+	workflow       workflowservice.WorkflowServiceClient
+	test           testsv1connect.TestServiceClient
+	taskQueues     TaskQueueResolver
+	casePayloads   CasePayloadRecorder
+	caseAttempts   CaseAttemptRecorder
+	caseHeartbeats CaseHeartbeatRecorder
+	subExecs       SubExecutionRecorder
+	subExecCache   *expirable.LRU[string, subExecution]
+	finished       FinishedAcker
+	logger         log.Logger
 }
 
 func NewProxyService(
 	testClient testsv1connect.TestServiceClient,
 	workflowClient workflowservice.WorkflowServiceClient,
+	opts ...ProxyOption,
 ) *ProxyService {
-	return &ProxyService{
+	s := &ProxyService{
 		test:     testClient,
 		workflow: workflowClient,
+		logger:   log.NewNopLogger(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.subExecs != nil {
+		s.subExecCache = expirable.NewLRU[string, subExecution](subExecCacheSize, nil, subExecCacheTTL)
 	}
+	return s
 }